@@ -0,0 +1,176 @@
+// Package analyze reports statistics over a sample of IDs: node
+// distribution, generation rate, sequence saturation, out-of-order IDs, and
+// duplicates. Mis-assigned nodes show up as duplicates and out-of-order IDs
+// within one node; clock problems as out-of-order IDs and timestamps outside
+// the expected window.
+package analyze
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"math/bits"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+// Report summarizes a sample of IDs.
+type Report struct {
+	Count int       `json:"count"`
+	First time.Time `json:"first"` // earliest timestamp
+	Last  time.Time `json:"last"`  // latest timestamp
+
+	// Nodes counts IDs per node.
+	Nodes map[int64]int `json:"nodes"`
+
+	// Rate counts IDs per time bucket, in chronological order. Empty buckets
+	// are omitted.
+	Rate []Bucket `json:"rate"`
+
+	// Saturated counts IDs that used the last counter value of their tick
+	// (within their shard, see WithShards), meaning the generator ran out of
+	// sequence and had to wait or borrow.
+	Saturated int `json:"saturated"`
+
+	// OutOfOrder counts IDs smaller than the previous ID from the same node,
+	// in input order. Generators never go backwards, so these indicate two
+	// processes sharing a node or IDs merged from unordered sources.
+	OutOfOrder int `json:"out_of_order"`
+
+	// Duplicates lists IDs seen more than once.
+	Duplicates []usid.ID `json:"duplicates"`
+}
+
+// Bucket is the number of IDs generated in [Start, Start+width).
+type Bucket struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+}
+
+// Analyzer accumulates a Report from IDs added one at a time.
+// It is not safe for concurrent use.
+type Analyzer struct {
+	bucket   time.Duration
+	report   Report
+	buckets  map[time.Time]int
+	lastNode map[int64]usid.ID
+	seen     map[usid.ID]int
+	maxCtr   int64
+	randBits uint8
+}
+
+// Option configures an Analyzer.
+type Option func(*options)
+
+type options struct {
+	shardBits uint8
+}
+
+// WithShards interprets IDs as coming from generators created with
+// usid.WithShards(n), whose shard index takes the top sequence bits and so
+// leaves a smaller counter to saturate.
+func WithShards(n int) Option {
+	return func(o *options) {
+		if n > 1 {
+			o.shardBits = uint8(bits.Len(uint(n - 1)))
+		}
+	}
+}
+
+// New returns an Analyzer that counts generation rate in buckets of the given
+// width, interpreting IDs with the current usid layout.
+func New(bucket time.Duration, opts ...Option) *Analyzer {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	cfg := usid.CurrentConfig()
+	ctrBits := max(int(cfg.SeqBits)-int(cfg.RandBits)-int(o.shardBits), 0)
+	return &Analyzer{
+		bucket:   bucket,
+		report:   Report{Nodes: make(map[int64]int)},
+		buckets:  make(map[time.Time]int),
+		lastNode: make(map[int64]usid.ID),
+		seen:     make(map[usid.ID]int),
+		maxCtr:   1<<ctrBits - 1,
+		randBits: cfg.RandBits,
+	}
+}
+
+// Add records id.
+func (a *Analyzer) Add(id usid.ID) {
+	r := &a.report
+	ts := id.Timestamp()
+	if r.Count == 0 || ts.Before(r.First) {
+		r.First = ts
+	}
+	if r.Count == 0 || ts.After(r.Last) {
+		r.Last = ts
+	}
+	r.Count++
+
+	node := id.Node()
+	r.Nodes[node]++
+	if last, ok := a.lastNode[node]; ok && id < last {
+		r.OutOfOrder++
+	}
+	a.lastNode[node] = id
+
+	if id.Seq()>>a.randBits&a.maxCtr == a.maxCtr {
+		r.Saturated++
+	}
+	if a.seen[id]++; a.seen[id] == 2 {
+		r.Duplicates = append(r.Duplicates, id)
+	}
+	a.buckets[ts.Truncate(a.bucket)]++
+}
+
+// Report returns the statistics for the IDs added so far.
+func (a *Analyzer) Report() Report {
+	r := a.report
+	r.Nodes = maps.Clone(r.Nodes)
+	r.Duplicates = slices.Clone(r.Duplicates)
+	r.Rate = make([]Bucket, 0, len(a.buckets))
+	for _, start := range slices.SortedFunc(maps.Keys(a.buckets), time.Time.Compare) {
+		r.Rate = append(r.Rate, Bucket{Start: start, Count: a.buckets[start]})
+	}
+	return r
+}
+
+// Analyze returns the Report for ids, with generation rate counted in buckets
+// of the given width.
+func Analyze(ids iter.Seq[usid.ID], bucket time.Duration, opts ...Option) Report {
+	a := New(bucket, opts...)
+	for id := range ids {
+		a.Add(id)
+	}
+	return a.Report()
+}
+
+// String formats the report for terminals.
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ids:          %d\n", r.Count)
+	if r.Count == 0 {
+		return b.String()
+	}
+	fmt.Fprintf(&b, "span:         %s .. %s\n", r.First.UTC().Format(time.RFC3339Nano), r.Last.UTC().Format(time.RFC3339Nano))
+	fmt.Fprintf(&b, "saturated:    %d\n", r.Saturated)
+	fmt.Fprintf(&b, "out of order: %d\n", r.OutOfOrder)
+	fmt.Fprintf(&b, "duplicates:   %d\n", len(r.Duplicates))
+	for _, id := range r.Duplicates {
+		fmt.Fprintf(&b, "  %s (%d)\n", id, id.Int64())
+	}
+	b.WriteString("nodes:\n")
+	for _, node := range slices.Sorted(maps.Keys(r.Nodes)) {
+		fmt.Fprintf(&b, "  %4d %d\n", node, r.Nodes[node])
+	}
+	b.WriteString("rate:\n")
+	for _, bk := range r.Rate {
+		fmt.Fprintf(&b, "  %s %d\n", bk.Start.UTC().Format(time.RFC3339), bk.Count)
+	}
+	return b.String()
+}
@@ -0,0 +1,96 @@
+package analyze_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/analyze"
+)
+
+func idAt(t time.Time, node, seq int64) usid.ID {
+	return usid.MinIDForTime(t) | usid.ID(node<<usid.SeqBits|seq)
+}
+
+func TestAnalyze(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	ids := []usid.ID{
+		idAt(t0, 1, 0),
+		idAt(t0, 1, 63), // saturated
+		idAt(t0.Add(time.Hour), 2, 0),
+		idAt(t0.Add(time.Minute), 2, 0), // out of order on node 2
+		idAt(t0, 1, 0),                  // duplicate, and out of order on node 1
+	}
+	r := analyze.Analyze(slices.Values(ids), time.Hour)
+
+	if r.Count != 5 || !r.First.Equal(t0) || !r.Last.Equal(t0.Add(time.Hour)) {
+		t.Errorf("Count/First/Last = %d %v %v", r.Count, r.First, r.Last)
+	}
+	if r.Nodes[1] != 3 || r.Nodes[2] != 2 {
+		t.Errorf("Nodes = %v", r.Nodes)
+	}
+	if r.Saturated != 1 {
+		t.Errorf("Saturated = %d, want 1", r.Saturated)
+	}
+	if r.OutOfOrder != 2 {
+		t.Errorf("OutOfOrder = %d, want 2", r.OutOfOrder)
+	}
+	if !slices.Equal(r.Duplicates, []usid.ID{ids[0]}) {
+		t.Errorf("Duplicates = %v", r.Duplicates)
+	}
+	want := []analyze.Bucket{{Start: t0, Count: 4}, {Start: t0.Add(time.Hour), Count: 1}}
+	if len(r.Rate) != 2 || !r.Rate[0].Start.Equal(want[0].Start) || r.Rate[0].Count != 4 || r.Rate[1].Count != 1 {
+		t.Errorf("Rate = %v, want %v", r.Rate, want)
+	}
+	if r.String() == "" {
+		t.Error("String() is empty")
+	}
+}
+
+func TestAnalyzeShards(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	ids := []usid.ID{
+		idAt(t0, 1, 15),     // shard 0, saturated with 4 shards
+		idAt(t0, 1, 3<<4),   // shard 3, counter 0
+		idAt(t0, 1, 63),     // shard 3, saturated
+		idAt(t0, 1, 2<<4|7), // shard 2, counter 7
+	}
+	if r := analyze.Analyze(slices.Values(ids), time.Hour); r.Saturated != 1 {
+		t.Errorf("unsharded: Saturated = %d, want 1", r.Saturated)
+	}
+	if r := analyze.Analyze(slices.Values(ids), time.Hour, analyze.WithShards(4)); r.Saturated != 2 {
+		t.Errorf("WithShards(4): Saturated = %d, want 2", r.Saturated)
+	}
+}
+
+func TestEnumeration(t *testing.T) {
+	raw := make([]int64, 1000)
+	random := make([]int64, len(raw))
+	xored := make([]int64, len(raw))
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	g := usid.NewGenerator(1, usid.WithClock(func() time.Time {
+		now = now.Add(time.Microsecond)
+		return now
+	}))
+	obf := usid.NewObfuscator(0x5deece66d123f567)
+	for i := range raw {
+		id := g.Generate()
+		raw[i], xored[i] = id.Int64(), obf.Obfuscate(id).Int64()
+		random[i] = rand.Int64()
+	}
+
+	if e := analyze.MeasureEnumeration(raw); e.Ascending != 1 || e.CloseNeighbors != 1 || len(e.Warnings()) != 2 {
+		t.Errorf("raw IDs: %+v, warnings %q", e, e.Warnings())
+	}
+	if e := analyze.MeasureEnumeration(xored); e.Ascending > analyze.MaxAscending || e.CloseNeighbors != 1 || len(e.Warnings()) != 1 {
+		t.Errorf("XOR-obfuscated IDs: %+v, warnings %q", e, e.Warnings())
+	}
+	if e := analyze.MeasureEnumeration(random); e.MedianGapBits < 55 || len(e.Warnings()) != 0 {
+		t.Errorf("random values: %+v, warnings %q", e, e.Warnings())
+	}
+	if e := analyze.MeasureEnumeration(nil); e.Count != 0 || e.Warnings() != nil {
+		t.Errorf("empty sample: %+v", e)
+	}
+}
@@ -0,0 +1,107 @@
+package analyze
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+// Thresholds above which Enumeration.Warnings flags a sample.
+const (
+	// MaxAscending is the largest tolerated share of consecutive exposed
+	// values that increase. Unpredictable values increase about half the time.
+	MaxAscending = 0.75
+
+	// MaxCloseNeighbors is the largest tolerated share of consecutive exposed
+	// values within 2^32 of each other. Unpredictable 64-bit values almost
+	// never are.
+	MaxCloseNeighbors = 0.1
+)
+
+// Enumeration measures how predictable a sample of exposed ID values is: how
+// easily a client holding one ID can guess others by counting or scanning
+// nearby values. Values are compared in sample order, which should be the
+// order they were issued in, such as IDs from consecutive API calls.
+type Enumeration struct {
+	Count int `json:"count"`
+
+	// Ascending is the share of consecutive values that increase: near 1
+	// for raw time-ordered IDs, near 0.5 for unpredictable ones.
+	Ascending float64 `json:"ascending"`
+
+	// CloseNeighbors is the share of consecutive values within 2^32 of each
+	// other, which a client can find by scanning nearby values. XOR
+	// obfuscation keeps neighbors close, since IDs issued together differ
+	// only in their low bits.
+	CloseNeighbors float64 `json:"close_neighbors"`
+
+	// MedianGapBits is log2 of the median distance between consecutive
+	// values, about 62 for unpredictable ones.
+	MedianGapBits float64 `json:"median_gap_bits"`
+}
+
+// MeasureEnumeration returns the Enumeration of values, the int64 forms of
+// IDs exactly as clients see them.
+func MeasureEnumeration(values []int64) Enumeration {
+	e := Enumeration{Count: len(values)}
+	if len(values) < 2 {
+		return e
+	}
+	gaps := make([]uint64, 0, len(values)-1)
+	var ascending, close int
+	for i := 1; i < len(values); i++ {
+		a, b := values[i-1], values[i]
+		gap := uint64(b) - uint64(a)
+		if b > a {
+			ascending++
+		} else {
+			gap = uint64(a) - uint64(b)
+		}
+		if gap < 1<<32 {
+			close++
+		}
+		gaps = append(gaps, gap)
+	}
+	pairs := float64(len(gaps))
+	e.Ascending = float64(ascending) / pairs
+	e.CloseNeighbors = float64(close) / pairs
+	slices.Sort(gaps)
+	if median := gaps[len(gaps)/2]; median > 0 {
+		e.MedianGapBits = math.Log2(float64(median))
+	}
+	return e
+}
+
+// Enumerability parses exposed, ID strings in format f as returned to
+// clients, and measures the values clients see: obfuscated with
+// usid.DefaultObfuscator, if set, as the strings were.
+func Enumerability(exposed []string, f usid.Format) (Enumeration, error) {
+	values := make([]int64, len(exposed))
+	for i, s := range exposed {
+		id, err := usid.ParseFormat(s, f)
+		if err != nil {
+			return Enumeration{}, fmt.Errorf("usid: exposed[%d]: %w", i, err)
+		}
+		if usid.DefaultObfuscator != nil {
+			id = usid.DefaultObfuscator.Obfuscate(id)
+		}
+		values[i] = id.Int64()
+	}
+	return MeasureEnumeration(values), nil
+}
+
+// Warnings explains each way the sample is predictable, or returns nil if
+// it looks unpredictable.
+func (e Enumeration) Warnings() []string {
+	var w []string
+	if e.Ascending > MaxAscending {
+		w = append(w, fmt.Sprintf("%.0f%% of consecutive IDs increase: exposed IDs reveal their time order; obfuscation is off or its key barely reorders them", e.Ascending*100))
+	}
+	if e.CloseNeighbors > MaxCloseNeighbors {
+		w = append(w, fmt.Sprintf("%.0f%% of consecutive IDs are within 2^32 of each other (median gap 2^%.1f): neighbors can be found by scanning; "+
+			"XOR obfuscation does not hide them, so sign exposed IDs with usid.Signer", e.CloseNeighbors*100, e.MedianGapBits))
+	}
+	return w
+}
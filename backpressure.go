@@ -0,0 +1,86 @@
+package usid
+
+import (
+	"sync"
+	"time"
+)
+
+// Backpressure is the generator activity over one interval between
+// BackpressureMeter snapshots.
+type Backpressure struct {
+	GeneratorStats               // counters accumulated during the interval
+	Interval       time.Duration // length of the interval; zero for the first snapshot
+}
+
+// Rate returns the IDs generated per second over the interval, or 0 for the
+// first snapshot.
+func (b Backpressure) Rate() float64 {
+	if b.Interval <= 0 {
+		return 0
+	}
+	return float64(b.Generated) / b.Interval.Seconds()
+}
+
+// Saturation returns the spins spent waiting for an exhausted tick per ID
+// generated in the interval. Values above 1 mean callers of New() spend more
+// time waiting for the clock than issuing IDs, and more nodes are needed.
+func (b Backpressure) Saturation() float64 {
+	return float64(b.ExhaustionWaits) / float64(max(b.Generated, 1))
+}
+
+// Contention returns the compare-and-swap retries per ID generated in the
+// interval. A high value suggests WithShards rather than more nodes.
+func (b Backpressure) Contention() float64 {
+	return float64(b.Retries) / float64(max(b.Generated, 1))
+}
+
+// BackpressureMeter measures how hard a generator is pushed between calls to
+// Snapshot, for autoscaling on ID-generation saturation rather than on
+// latency. The zero value measures DefaultGenerator.
+type BackpressureMeter struct {
+	// Generator to measure; nil means DefaultGenerator.
+	Generator *Generator
+
+	mu   sync.Mutex
+	last GeneratorStats
+	at   time.Time
+}
+
+// Snapshot returns the activity since the previous Snapshot. The first call
+// covers everything since the generator was created. Safe for concurrent use.
+func (m *BackpressureMeter) Snapshot() Backpressure {
+	g := m.Generator
+	if g == nil {
+		g = DefaultGenerator
+	}
+	var st GeneratorStats
+	if g != nil {
+		st = g.Stats()
+	}
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b := Backpressure{GeneratorStats: st.Since(m.last)}
+	if !m.at.IsZero() {
+		b.Interval = now.Sub(m.at)
+	}
+	m.last, m.at = st, now
+	return b
+}
+
+// Since returns the counters accumulated after prev, an earlier snapshot of
+// the same generator. If prev belongs to a different generator, as after
+// SetNodeID replaced DefaultGenerator, it returns st unchanged.
+func (st GeneratorStats) Since(prev GeneratorStats) GeneratorStats {
+	if prev.Node != st.Node || prev.Generated > st.Generated {
+		return st
+	}
+	return GeneratorStats{
+		Node:             st.Node,
+		Generated:        st.Generated - prev.Generated,
+		Retries:          st.Retries - prev.Retries,
+		ExhaustionWaits:  st.ExhaustionWaits - prev.ExhaustionWaits,
+		ClockRegressions: st.ClockRegressions - prev.ClockRegressions,
+	}
+}
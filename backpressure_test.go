@@ -0,0 +1,59 @@
+package usid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackpressureMeter(t *testing.T) {
+	// A clock that advances one tick every 200 reads, so the 64 IDs of a
+	// tick run out and Generate spins.
+	base := time.Now()
+	var reads int64
+	g := NewGenerator(1, WithClock(func() time.Time {
+		reads++
+		return base.Add(time.Duration(reads/200) * time.Microsecond)
+	}))
+	m := &BackpressureMeter{Generator: g}
+
+	for range 10 {
+		g.Generate()
+	}
+	first := m.Snapshot()
+	if first.Generated != 10 || first.Interval != 0 || first.Rate() != 0 {
+		t.Errorf("first Snapshot() = %+v, want 10 IDs and no interval", first)
+	}
+
+	for range 1000 {
+		g.Generate()
+	}
+	b := m.Snapshot()
+	if b.Generated != 1000 || b.Interval <= 0 || b.Rate() <= 0 {
+		t.Errorf("Snapshot() = %+v, want 1000 IDs over a positive interval", b)
+	}
+	if b.ExhaustionWaits == 0 || b.Saturation() <= 0 {
+		t.Errorf("Snapshot() = %+v, want exhaustion waits", b)
+	}
+	if idle := m.Snapshot(); idle.Generated != 0 || idle.Saturation() != 0 || idle.Contention() != 0 {
+		t.Errorf("idle Snapshot() = %+v, want zero counters", idle)
+	}
+}
+
+func TestBackpressureMeterDefault(t *testing.T) {
+	defer SetNodeID(1)
+	SetNodeID(5)
+	var m BackpressureMeter
+	m.Snapshot()
+	New()
+	if b := m.Snapshot(); b.Node != 5 || b.Generated != 1 {
+		t.Errorf("Snapshot() = %+v, want 1 ID on node 5", b)
+	}
+
+	// Replacing DefaultGenerator starts counting afresh
+	SetNodeID(6)
+	New()
+	New()
+	if b := m.Snapshot(); b.Node != 6 || b.Generated != 2 {
+		t.Errorf("Snapshot() after SetNodeID = %+v, want 2 IDs on node 6", b)
+	}
+}
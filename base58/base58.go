@@ -2,7 +2,10 @@
 // It uses the Bitcoin alphabet which excludes 0, O, I, and l to avoid ambiguity.
 package base58
 
-import "errors"
+import (
+	"errors"
+	"math"
+)
 
 var encode = [58]byte{
 	'1', '2', '3', '4', '5', '6', '7', '8', '9', 'A',
@@ -28,27 +31,97 @@ var decode = [128]int64{
 	'x': 55, 'y': 56, 'z': 57,
 }
 
-// ErrInvalidBase58 is returned when decoding a string with invalid Base58 characters.
+// MaxLen is the maximum length of a Base58-encoded int64.
+const MaxLen = 11
+
+// ErrInvalidBase58 is returned when decoding a string with invalid Base58 characters
+// or a string longer than MaxLen.
 var ErrInvalidBase58 = errors.New("usid: invalid base58 character")
 
+// ErrOverflow is returned when a Base58-encoded string exceeds the int64 range.
+var ErrOverflow = errors.New("usid: base58 value overflows int64")
+
+// pairs holds the two Base58 digits of every value below 58², so encoders
+// emit two digits per division.
+var pairs [58 * 58 * 2]byte
+
+func init() {
+	for i := range 58 * 58 {
+		pairs[2*i] = encode[i/58]
+		pairs[2*i+1] = encode[i%58]
+	}
+}
+
 // Encode returns the Base58 encoding of the given int64.
+// Negative values return an empty string.
 func Encode(id int64) string {
+	var buf [MaxLen]byte
+	return string(AppendEncode(buf[:0], id))
+}
+
+// AppendEncode appends the Base58 encoding of the given int64 to dst and
+// returns the extended slice. Negative values append nothing, as with Encode.
+func AppendEncode(dst []byte, id int64) []byte {
+	if id < 0 {
+		return dst
+	}
 	if id == 0 {
-		return "1"
+		return append(dst, '1')
+	}
+	var buf [MaxLen]byte
+	i := MaxLen
+	n := uint64(id)
+	for n >= 58*58 {
+		q := n / (58 * 58)
+		r := 2 * (n - q*58*58)
+		i -= 2
+		buf[i], buf[i+1] = pairs[r], pairs[r+1]
+		n = q
 	}
-	var buf [11]byte
-	i := 10
-	for id > 0 {
-		buf[i] = encode[id%58]
-		id /= 58
+	if n >= 58 {
+		i -= 2
+		buf[i], buf[i+1] = pairs[2*n], pairs[2*n+1]
+	} else {
 		i--
+		buf[i] = encode[n]
+	}
+	return append(dst, buf[i:]...)
+}
+
+// EncodeFixed returns the Base58 encoding of the given int64 left-padded with '1'
+// (the zero digit) to MaxLen characters, so encoded strings sort lexicographically
+// in the same order as their values. Negative values return an empty string, as with Encode.
+func EncodeFixed(id int64) string {
+	var buf [MaxLen]byte
+	return string(AppendEncodeFixed(buf[:0], id))
+}
+
+// AppendEncodeFixed appends the EncodeFixed encoding of the given int64 to
+// dst and returns the extended slice. Negative values append nothing.
+func AppendEncodeFixed(dst []byte, id int64) []byte {
+	if id < 0 {
+		return dst
 	}
-	return string(buf[i+1:])
+	var buf [MaxLen]byte
+	n := uint64(id)
+	// MaxLen is odd: five digit pairs, then the leading digit.
+	for i := MaxLen - 2; i > 0; i -= 2 {
+		q := n / (58 * 58)
+		r := 2 * (n - q*58*58)
+		buf[i], buf[i+1] = pairs[r], pairs[r+1]
+		n = q
+	}
+	buf[0] = encode[n]
+	return append(dst, buf[:]...)
 }
 
 // Decode parses a Base58-encoded string and returns the int64 value.
-// Returns ErrInvalidBase58 if the string contains invalid characters.
+// Returns ErrInvalidBase58 if the string contains invalid characters or is longer
+// than MaxLen, and ErrOverflow if the value does not fit in an int64.
 func Decode(s string) (int64, error) {
+	if len(s) > MaxLen {
+		return 0, ErrInvalidBase58
+	}
 	var id int64
 	for i := 0; i < len(s); i++ {
 		c := s[i]
@@ -59,7 +132,44 @@ func Decode(s string) (int64, error) {
 		if v == 0 && c != '1' {
 			return 0, ErrInvalidBase58
 		}
+		if id > (math.MaxInt64-v)/58 {
+			return 0, ErrOverflow
+		}
 		id = id*58 + v
 	}
 	return id, nil
 }
+
+// Encoding is a Base58 codec, satisfying usid.Codec.
+type Encoding struct {
+	fixed bool
+}
+
+var (
+	// StdEncoding encodes like Encode.
+	StdEncoding = Encoding{}
+
+	// FixedEncoding encodes like EncodeFixed.
+	FixedEncoding = Encoding{fixed: true}
+)
+
+// AppendEncode appends the encoding of id to dst.
+func (e Encoding) AppendEncode(dst []byte, id int64) []byte {
+	if e.fixed {
+		return AppendEncodeFixed(dst, id)
+	}
+	return AppendEncode(dst, id)
+}
+
+// Encode returns the encoding of id as a string.
+func (e Encoding) Encode(id int64) string {
+	if e.fixed {
+		return EncodeFixed(id)
+	}
+	return Encode(id)
+}
+
+// Decode decodes s like Decode. Both encodings accept both forms.
+func (e Encoding) Decode(s string) (int64, error) {
+	return Decode(s)
+}
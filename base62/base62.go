@@ -0,0 +1,71 @@
+// Package base62 provides Base62 encoding and decoding for int64 values.
+// It uses the strictly alphanumeric alphabet 0-9, A-Z, a-z.
+package base62
+
+import (
+	"errors"
+	"math"
+)
+
+const alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+var decode [128]int64
+
+func init() {
+	for i := range decode {
+		decode[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		decode[alphabet[i]] = int64(i)
+	}
+}
+
+// MaxLen is the maximum length of a Base62-encoded int64.
+const MaxLen = 11
+
+// ErrInvalidBase62 is returned when decoding a string with invalid Base62 characters
+// or a string longer than MaxLen.
+var ErrInvalidBase62 = errors.New("usid: invalid base62 character")
+
+// ErrOverflow is returned when a Base62-encoded string exceeds the int64 range.
+var ErrOverflow = errors.New("usid: base62 value overflows int64")
+
+// Encode returns the Base62 encoding of the given int64.
+func Encode(id int64) string {
+	if id == 0 {
+		return "0"
+	}
+	var buf [MaxLen]byte
+	i := MaxLen - 1
+	for id > 0 {
+		buf[i] = alphabet[id%62]
+		id /= 62
+		i--
+	}
+	return string(buf[i+1:])
+}
+
+// Decode parses a Base62-encoded string and returns the int64 value.
+// Returns ErrInvalidBase62 if the string contains invalid characters or is longer
+// than MaxLen, and ErrOverflow if the value does not fit in an int64.
+func Decode(s string) (int64, error) {
+	if len(s) > MaxLen {
+		return 0, ErrInvalidBase62
+	}
+	var id int64
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 128 {
+			return 0, ErrInvalidBase62
+		}
+		v := decode[c]
+		if v < 0 {
+			return 0, ErrInvalidBase62
+		}
+		if id > (math.MaxInt64-v)/62 {
+			return 0, ErrOverflow
+		}
+		id = id*62 + v
+	}
+	return id, nil
+}
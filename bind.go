@@ -0,0 +1,43 @@
+package usid
+
+import "fmt"
+
+// ParamError is returned by UnmarshalParam when a request parameter is not a
+// valid ID. Web frameworks pass it through their binding errors, so handlers
+// can tell a malformed ID (400 Bad Request) from a missing record.
+type ParamError struct {
+	Value string
+	Err   error
+}
+
+func (e *ParamError) Error() string {
+	return fmt.Sprintf("usid: invalid ID parameter %q: %v", e.Value, e.Err)
+}
+
+func (e *ParamError) Unwrap() error { return e.Err }
+
+// UnmarshalParam implements the BindUnmarshaler interfaces of gin and echo,
+// so struct fields bound with `uri`, `form`, `query`, or `param` tags accept
+// every format Parse does. Without it, both frameworks bind ID as a plain
+// int64: encoded IDs fail with a strconv error and decimal ones bypass
+// DefaultObfuscator.
+func (id *ID) UnmarshalParam(param string) error {
+	parsed, err := Parse(param)
+	if err != nil {
+		return &ParamError{Value: param, Err: err}
+	}
+	*id = parsed
+	return nil
+}
+
+// UnmarshalParam implements the BindUnmarshaler interfaces of gin and echo.
+// An empty parameter binds as NULL.
+func (n *NullID) UnmarshalParam(param string) error {
+	if param == "" {
+		n.ID, n.Valid = Nil, false
+		return nil
+	}
+	err := n.ID.UnmarshalParam(param)
+	n.Valid = (err == nil)
+	return err
+}
@@ -0,0 +1,31 @@
+package usid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUnmarshalParam(t *testing.T) {
+	want := New()
+	var id ID
+	if err := id.UnmarshalParam(want.String()); err != nil || id != want {
+		t.Errorf("UnmarshalParam(%q) = %d, %v; want %d", want.String(), id, err, want)
+	}
+
+	err := id.UnmarshalParam("not-an-id!")
+	var perr *ParamError
+	if !errors.As(err, &perr) || perr.Value != "not-an-id!" || perr.Err == nil {
+		t.Errorf("UnmarshalParam(invalid) = %v, want *ParamError", err)
+	}
+
+	n := NullIDFrom(want)
+	if err := n.UnmarshalParam(""); err != nil || n.Valid {
+		t.Errorf("NullID.UnmarshalParam(\"\") = %+v, %v; want NULL", n, err)
+	}
+	if err := n.UnmarshalParam(want.String()); err != nil || n != NullIDFrom(want) {
+		t.Errorf("NullID.UnmarshalParam(%q) = %+v, %v", want.String(), n, err)
+	}
+	if err := n.UnmarshalParam("!"); !errors.As(err, &perr) || n.Valid {
+		t.Errorf("NullID.UnmarshalParam(invalid) = %+v, %v; want *ParamError", n, err)
+	}
+}
@@ -0,0 +1,59 @@
+package usid
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBlock(t *testing.T) {
+	g := NewGenerator(3)
+	ids := make([]ID, 1000)
+	for i := range ids {
+		ids[i] = g.Generate()
+	}
+	ids = append(ids, ids[10], Nil, ID(-5), Omni)
+	shuffled := slices.Clone(ids)
+	slices.Reverse(shuffled)
+
+	b := EncodeBlock(shuffled)
+	if len(b) > 4*len(ids) {
+		t.Errorf("EncodeBlock: %d bytes for %d IDs, want <= 4 per ID", len(b), len(ids))
+	}
+	got, err := DecodeBlock(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(ids)
+	if !slices.Equal(got, ids) {
+		t.Errorf("DecodeBlock(EncodeBlock(ids)) differs from sorted ids")
+	}
+	if !slices.Equal(shuffled[:3], []ID{Omni, ID(-5), Nil}) {
+		t.Errorf("EncodeBlock modified its argument")
+	}
+
+	if got, err := DecodeBlock(EncodeBlock(nil)); err != nil || len(got) != 0 {
+		t.Errorf("DecodeBlock(EncodeBlock(nil)) = %v, %v", got, err)
+	}
+	for _, bad := range [][]byte{
+		nil,
+		{0x05, 0x01},
+		append(slices.Clone(b), 0x00),
+		b[:len(b)-1],
+		{0x02, 0x01, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f},
+	} {
+		if _, err := DecodeBlock(bad); err == nil {
+			t.Errorf("DecodeBlock(%x): want err != nil", bad)
+		}
+	}
+}
+
+func BenchmarkEncodeBlock(b *testing.B) {
+	g := NewGenerator(1)
+	ids := make([]ID, 1000)
+	for i := range ids {
+		ids[i] = g.Generate()
+	}
+	for b.Loop() {
+		EncodeBlock(ids)
+	}
+}
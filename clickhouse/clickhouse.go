@@ -0,0 +1,62 @@
+// Package clickhouse provides ClickHouse helpers for USID columns.
+//
+// Store IDs in Int64 columns. usid.ID implements driver.Valuer and
+// sql.Scanner, so it binds and scans directly through clickhouse-go's
+// database/sql driver; with the native API, append id.Int64() and scan into
+// an int64. For UInt64 columns, convert with uint64(id) and usid.FromUint64.
+//
+// Because IDs are time-ordered, partitioning by a function of the ID column
+// lets ClickHouse prune partitions from plain ID range predicates:
+//
+//	CREATE TABLE events (id Int64, ...)
+//	ENGINE = MergeTree
+//	PARTITION BY <clickhouse.PartitionByMonth("id")>
+//	ORDER BY id
+//
+//	SELECT ... WHERE <clickhouse.Range("id", from, to)>
+package clickhouse
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+// TimestampExpr returns a ClickHouse expression that extracts the generation
+// time of the IDs in column as a DateTime64(6), using the current usid layout.
+// It works for both Int64 and UInt64 columns. Panics if the layout uses eras
+// (usid.EraBits), whose per-era epochs the expression cannot select.
+func TimestampExpr(column string) string {
+	cfg := usid.CurrentConfig()
+	if cfg.EraBits > 0 {
+		panic("usid: clickhouse expressions do not decode eras")
+	}
+	ticks := "bitShiftRight(" + column + ", " + strconv.Itoa(int(cfg.NodeBits+cfg.TenantBits+cfg.SeqBits)) + ")"
+	if p := cfg.Precision.Microseconds(); p > 1 {
+		ticks += " * " + strconv.FormatInt(p, 10)
+	}
+	return "fromUnixTimestamp64Micro(toInt64(" + ticks + " + " + strconv.FormatInt(cfg.Epoch, 10) + "))"
+}
+
+// PartitionByMonth returns a PARTITION BY expression bucketing column by the
+// month its IDs were generated, e.g. for toYYYYMM partitioning.
+func PartitionByMonth(column string) string {
+	return "toYYYYMM(" + TimestampExpr(column) + ")"
+}
+
+// PartitionByDay returns a PARTITION BY expression bucketing column by the
+// day its IDs were generated.
+func PartitionByDay(column string) string {
+	return "toYYYYMMDD(" + TimestampExpr(column) + ")"
+}
+
+// Range returns a predicate matching IDs in column generated in [from, to),
+// with the bounds inlined as integer literals so ClickHouse can prune
+// partitions and granules during query analysis. Use usid.Between for a
+// parameterized form.
+func Range(column string, from, to time.Time) string {
+	min, max := usid.Between(column, from, to).Bounds()
+	return column + " >= " + strconv.FormatInt(min.Int64(), 10) +
+		" AND " + column + " < " + strconv.FormatInt(max.Int64(), 10)
+}
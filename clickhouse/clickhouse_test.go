@@ -0,0 +1,52 @@
+package clickhouse_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/clickhouse"
+)
+
+func TestTimestampExpr(t *testing.T) {
+	want := "fromUnixTimestamp64Micro(toInt64(bitShiftRight(id, 12) + 1765947799213000))"
+	if got := clickhouse.TimestampExpr("id"); got != want {
+		t.Errorf("TimestampExpr = %s, want %s", got, want)
+	}
+	if got := clickhouse.PartitionByMonth("id"); got != "toYYYYMM("+want+")" {
+		t.Errorf("PartitionByMonth = %s", got)
+	}
+
+	if err := usid.Configure(usid.PrecisionMilli()); err != nil {
+		t.Fatal(err)
+	}
+	defer usid.Configure(usid.DefaultConfig())
+	if got := clickhouse.TimestampExpr("id"); !strings.Contains(got, "bitShiftRight(id, 20) * 1000 + ") {
+		t.Errorf("TimestampExpr with ms precision = %s", got)
+	}
+
+	cfg := usid.DefaultConfig()
+	cfg.EraBits = 1
+	if err := usid.Configure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("TimestampExpr with eras did not panic")
+		}
+	}()
+	clickhouse.TimestampExpr("id")
+}
+
+func TestRange(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	got := clickhouse.Range("id", from, to)
+	want := "id >= " + strconvID(usid.MinIDForTime(from)) + " AND id < " + strconvID(usid.MinIDForTime(to))
+	if got != want {
+		t.Errorf("Range = %s, want %s", got, want)
+	}
+}
+
+func strconvID(id usid.ID) string { return id.Format(usid.FormatDecimal) }
@@ -0,0 +1,97 @@
+package usid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ClockReference reports the current time according to an external source,
+// such as an NTP server or a database (see postgres.Clock).
+type ClockReference func(ctx context.Context) (time.Time, error)
+
+// MaxClockSkew is the largest difference from a ClockReference that
+// CheckClock tolerates.
+var MaxClockSkew = time.Second
+
+var (
+	// ErrClockBeforeEpoch is returned when the system clock is earlier than Epoch,
+	// which would produce negative timestamps.
+	ErrClockBeforeEpoch = errors.New("usid: system clock is before Epoch")
+
+	// ErrClockAfterLayout is returned when the system clock is past the last
+	// instant the configured layout can represent.
+	ErrClockAfterLayout = errors.New("usid: system clock is past the end of the layout")
+
+	// ErrClockSkew is returned when the system clock disagrees with a reference
+	// by more than MaxClockSkew.
+	ErrClockSkew = errors.New("usid: system clock skew exceeds MaxClockSkew")
+)
+
+// CheckClock validates the system clock against the configured layout and any
+// given references. Call it at startup: a misconfigured clock or Epoch
+// otherwise produces garbage IDs silently.
+func CheckClock(ctx context.Context, refs ...ClockReference) error {
+	now := time.Now()
+	cfg := CurrentConfig()
+	if now.UnixMicro() < cfg.Epoch {
+		return fmt.Errorf("%w: now=%s epoch=%s", ErrClockBeforeEpoch,
+			now.UTC().Format(time.RFC3339), time.UnixMicro(cfg.Epoch).UTC().Format(time.RFC3339))
+	}
+	if max := cfg.MaxTime(); now.After(max) {
+		return fmt.Errorf("%w: now=%s max=%s", ErrClockAfterLayout,
+			now.UTC().Format(time.RFC3339), max.UTC().Format(time.RFC3339))
+	}
+	for i, ref := range refs {
+		before := time.Now()
+		t, err := ref(ctx)
+		if err != nil {
+			return fmt.Errorf("usid: clock reference %d: %w", i, err)
+		}
+		// Compare against the midpoint of the round trip to cancel out latency.
+		local := before.Add(time.Since(before) / 2)
+		skew := local.Sub(t)
+		if skew < -MaxClockSkew || skew > MaxClockSkew {
+			return fmt.Errorf("%w: clock reference %d differs by %s", ErrClockSkew, i, skew)
+		}
+	}
+	return nil
+}
+
+// coarseClock is a wall clock refreshed by a background ticker, so reading it
+// costs an atomic load instead of a call to time.Now.
+type coarseClock struct {
+	micros atomic.Int64
+}
+
+var (
+	coarseMu     sync.Mutex
+	coarseClocks = make(map[time.Duration]*coarseClock)
+)
+
+// sharedCoarseClock returns the process-wide coarse clock for resolution,
+// starting its ticker on first use. Tickers run for the life of the process.
+func sharedCoarseClock(resolution time.Duration) *coarseClock {
+	coarseMu.Lock()
+	defer coarseMu.Unlock()
+	if c, ok := coarseClocks[resolution]; ok {
+		return c
+	}
+	c := &coarseClock{}
+	c.micros.Store(time.Now().UnixMicro())
+	go func() {
+		for range time.Tick(resolution) {
+			c.micros.Store(time.Now().UnixMicro())
+		}
+	}()
+	coarseClocks[resolution] = c
+	return c
+}
+
+// Now returns the time as of the last tick.
+func (c *coarseClock) Now() time.Time {
+	return time.UnixMicro(c.micros.Load())
+}
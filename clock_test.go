@@ -0,0 +1,36 @@
+package usid
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckClock(t *testing.T) {
+	ctx := context.Background()
+	fixed := func(offset time.Duration) ClockReference {
+		return func(context.Context) (time.Time, error) { return time.Now().Add(offset), nil }
+	}
+
+	if err := CheckClock(ctx, fixed(0), fixed(100*time.Millisecond)); err != nil {
+		t.Errorf("CheckClock(in sync) = %v, want nil", err)
+	}
+	if err := CheckClock(ctx, fixed(time.Minute)); !errors.Is(err, ErrClockSkew) {
+		t.Errorf("CheckClock(skewed) = %v, want ErrClockSkew", err)
+	}
+	failing := func(context.Context) (time.Time, error) { return time.Time{}, errors.New("unreachable") }
+	if err := CheckClock(ctx, failing); err == nil {
+		t.Error("CheckClock(failing reference): want err != nil")
+	}
+
+	cfg := DefaultConfig()
+	cfg.Epoch = time.Now().Add(time.Hour).UnixMicro()
+	if err := Configure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	defer Configure(DefaultConfig())
+	if err := CheckClock(ctx); !errors.Is(err, ErrClockBeforeEpoch) {
+		t.Errorf("CheckClock(future epoch) = %v, want ErrClockBeforeEpoch", err)
+	}
+}
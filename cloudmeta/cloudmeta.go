@@ -0,0 +1,105 @@
+// Package cloudmeta derives node IDs from cloud instance identity, for
+// deployments on EC2 or GCE without a coordination service.
+//
+// Instance IDs are hashed with usid.HashNode, so the same caveats apply:
+// nodes are stable per instance but can collide across a large fleet.
+package cloudmeta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+// ErrNoMetadata is returned when no supported metadata service answers.
+var ErrNoMetadata = errors.New("usid: no cloud metadata service found")
+
+// Metadata service endpoints, overridden in tests.
+var (
+	ec2Endpoint = "http://169.254.169.254"
+	gceEndpoint = "http://metadata.google.internal"
+)
+
+// probeTimeout bounds each metadata request, so hosts outside a cloud fail
+// fast instead of waiting on an unroutable link-local address.
+const probeTimeout = time.Second
+
+// InstanceID returns the EC2 or GCE instance ID of the current host, trying
+// EC2's IMDSv2 first and then the GCE metadata server.
+func InstanceID(ctx context.Context) (string, error) {
+	var errs []error
+	for _, lookup := range []func(context.Context) (string, error){ec2InstanceID, gceInstanceID} {
+		id, err := lookup(ctx)
+		if err == nil && id != "" {
+			return id, nil
+		}
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		errs = append(errs, err)
+	}
+	return "", fmt.Errorf("%w: %w", ErrNoMetadata, errors.Join(errs...))
+}
+
+// NodeFromCloudMetadata hashes the current instance ID to a node ID in
+// [1, 2^bits-1] with usid.HashNode.
+func NodeFromCloudMetadata(ctx context.Context, bits uint8) (int64, error) {
+	id, err := InstanceID(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return usid.HashNode(id, bits)
+}
+
+func ec2InstanceID(ctx context.Context) (string, error) {
+	token, err := get(ctx, http.MethodPut, ec2Endpoint+"/latest/api/token",
+		"X-aws-ec2-metadata-token-ttl-seconds", "60")
+	if err != nil {
+		return "", fmt.Errorf("ec2: %w", err)
+	}
+	id, err := get(ctx, http.MethodGet, ec2Endpoint+"/latest/meta-data/instance-id",
+		"X-aws-ec2-metadata-token", token)
+	if err != nil {
+		return "", fmt.Errorf("ec2: %w", err)
+	}
+	return id, nil
+}
+
+func gceInstanceID(ctx context.Context) (string, error) {
+	id, err := get(ctx, http.MethodGet, gceEndpoint+"/computeMetadata/v1/instance/id",
+		"Metadata-Flavor", "Google")
+	if err != nil {
+		return "", fmt.Errorf("gce: %w", err)
+	}
+	return id, nil
+}
+
+// get performs a metadata request with one header and returns the trimmed body.
+func get(ctx context.Context, method, url, header, value string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set(header, value)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s %s: %s", method, url, resp.Status)
+	}
+	return strings.TrimSpace(string(body)), nil
+}
@@ -0,0 +1,53 @@
+package cloudmeta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNodeFromCloudMetadata(t *testing.T) {
+	ec2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/latest/api/token":
+			w.Write([]byte("tok"))
+		case r.URL.Path == "/latest/meta-data/instance-id" && r.Header.Get("X-aws-ec2-metadata-token") == "tok":
+			w.Write([]byte("i-0abc123\n"))
+		default:
+			http.Error(w, "forbidden", http.StatusForbidden)
+		}
+	}))
+	defer ec2.Close()
+	gce := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		w.Write([]byte("4520031799277581759"))
+	}))
+	defer gce.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	defer down.Close()
+
+	defer func(e, g string) { ec2Endpoint, gceEndpoint = e, g }(ec2Endpoint, gceEndpoint)
+	ctx := context.Background()
+
+	ec2Endpoint, gceEndpoint = ec2.URL, down.URL
+	if id, err := InstanceID(ctx); err != nil || id != "i-0abc123" {
+		t.Errorf("EC2 InstanceID() = %q, %v", id, err)
+	}
+	ec2Endpoint, gceEndpoint = down.URL, gce.URL
+	if id, err := InstanceID(ctx); err != nil || id != "4520031799277581759" {
+		t.Errorf("GCE InstanceID() = %q, %v", id, err)
+	}
+	node, err := NodeFromCloudMetadata(ctx, 6)
+	if err != nil || node < 1 || node > 63 {
+		t.Errorf("NodeFromCloudMetadata() = %d, %v", node, err)
+	}
+	ec2Endpoint, gceEndpoint = down.URL, down.URL
+	if _, err := NodeFromCloudMetadata(ctx, 6); !errors.Is(err, ErrNoMetadata) {
+		t.Errorf("no metadata: err = %v, want ErrNoMetadata", err)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/analyze"
+)
+
+func runAnalyze(args []string) error {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	from := fs.String("from", string(usid.FormatDecimal), "input ID format")
+	key := fs.String("key", os.Getenv("USID_KEY"), "obfuscation key of the input IDs (default $USID_KEY; empty for raw)")
+	bucket := fs.Duration("bucket", time.Minute, "width of generation rate buckets")
+	shards := fs.Int("shards", 1, "shards of the generators that issued the IDs (see usid.WithShards)")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: usid analyze [flags] < ids.txt")
+		fmt.Fprintln(fs.Output(), "\nReads one ID per line and reports node distribution, generation rate,")
+		fmt.Fprintln(fs.Output(), "sequence saturation, out-of-order IDs, and duplicates.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if !slices.Contains(usid.Formats(), usid.Format(*from)) {
+		return fmt.Errorf("unknown format %q", *from)
+	}
+	if *bucket <= 0 {
+		return fmt.Errorf("bucket must be positive")
+	}
+	obf, err := parseKey(*key)
+	if err != nil {
+		return err
+	}
+	report, err := analyzeStream(os.Stdin, usid.Format(*from), obf, *bucket, analyze.WithShards(*shards))
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	_, err = fmt.Print(report)
+	return err
+}
+
+// analyzeStream reads one ID per line from in. Blank lines are skipped.
+func analyzeStream(in io.Reader, from usid.Format, key *usid.Obfuscator, bucket time.Duration, opts ...analyze.Option) (analyze.Report, error) {
+	a := analyze.New(bucket, opts...)
+	sc := bufio.NewScanner(in)
+	for line := 1; sc.Scan(); line++ {
+		s := strings.TrimSpace(sc.Text())
+		if s == "" {
+			continue
+		}
+		id, err := usid.ParseFormat(s, from)
+		if err != nil {
+			return analyze.Report{}, fmt.Errorf("line %d: %q: %w", line, s, err)
+		}
+		if key != nil {
+			id = key.Deobfuscate(id)
+		}
+		a.Add(id)
+	}
+	return a.Report(), sc.Err()
+}
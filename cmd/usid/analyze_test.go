@@ -0,0 +1,25 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+func TestAnalyzeStream(t *testing.T) {
+	in := "64\n\n 128\n64\n"
+	r, err := analyzeStream(strings.NewReader(in), usid.FormatDecimal, nil, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Count != 3 || r.Nodes[1] != 2 || r.Nodes[2] != 1 || len(r.Duplicates) != 1 {
+		t.Errorf("report = %+v", r)
+	}
+
+	_, err = analyzeStream(strings.NewReader("1\nnope\n"), usid.FormatDecimal, nil, time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("bad value error = %v, want line number", err)
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"testing"
+	"text/tabwriter"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+// benchResult is one line of the bench report.
+type benchResult struct {
+	Name        string  `json:"name"`
+	NsPerOp     float64 `json:"ns_per_op"`
+	OpsPerSec   float64 `json:"ops_per_sec"`
+	AllocsPerOp int64   `json:"allocs_per_op"`
+	BytesPerOp  int64   `json:"bytes_per_op"`
+}
+
+// benchmark is a named benchmark function run by the bench command.
+type benchmark struct {
+	name string
+	fn   func(*testing.B)
+}
+
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	configure := layoutFlags(fs)
+	benchtime := fs.String("benchtime", "1s", "run time per benchmark, or Nx for N iterations")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: usid bench [flags]")
+		fmt.Fprintln(fs.Output(), "\nMeasures Generate throughput on one goroutine and on all CPUs, and the")
+		fmt.Fprintln(fs.Output(), "cost and allocations of Format and Parse in every format, in the chosen layout.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if err := configure(); err != nil {
+		return err
+	}
+	results, err := bench(*benchtime)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+	return printBench(os.Stdout, results)
+}
+
+// bench runs every benchmark for benchtime, in the syntax of go test's
+// -benchtime flag.
+func bench(benchtime string) ([]benchResult, error) {
+	testing.Init()
+	if err := flag.CommandLine.Set("test.benchtime", benchtime); err != nil {
+		return nil, fmt.Errorf("invalid -benchtime %q", benchtime)
+	}
+
+	procs := runtime.GOMAXPROCS(0)
+	benchmarks := []benchmark{
+		{"Generate", func(b *testing.B) {
+			g := usid.NewGenerator(1)
+			for b.Loop() {
+				g.Generate()
+			}
+		}},
+		{fmt.Sprintf("Generate/parallel-%d", procs), func(b *testing.B) {
+			g := usid.NewGenerator(1)
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					g.Generate()
+				}
+			})
+		}},
+		{fmt.Sprintf("Generate/shards-%d", procs), func(b *testing.B) {
+			g := usid.NewGenerator(1, usid.WithShards(procs))
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					g.Generate()
+				}
+			})
+		}},
+	}
+	id := usid.New()
+	for _, f := range usid.Formats() {
+		s := id.Format(f)
+		benchmarks = append(benchmarks,
+			benchmark{"Format/" + string(f), func(b *testing.B) {
+				for b.Loop() {
+					id.Format(f)
+				}
+			}},
+			benchmark{"Parse/" + string(f), func(b *testing.B) {
+				for b.Loop() {
+					usid.ParseFormat(s, f)
+				}
+			}},
+		)
+	}
+
+	results := make([]benchResult, 0, len(benchmarks))
+	for _, bm := range benchmarks {
+		r := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			bm.fn(b)
+		})
+		if r.N == 0 {
+			return nil, fmt.Errorf("benchmark %s failed", bm.name)
+		}
+		ns := float64(r.T.Nanoseconds()) / float64(r.N)
+		results = append(results, benchResult{
+			Name:        bm.name,
+			NsPerOp:     ns,
+			OpsPerSec:   float64(time.Second) / ns,
+			AllocsPerOp: r.AllocsPerOp(),
+			BytesPerOp:  r.AllocedBytesPerOp(),
+		})
+	}
+	return results, nil
+}
+
+func printBench(w io.Writer, results []benchResult) error {
+	cfg := usid.CurrentConfig()
+	fmt.Fprintf(w, "layout: precision=%s node bits=%d seq bits=%d rand bits=%d, GOMAXPROCS=%d\n\n",
+		cfg.Precision, cfg.NodeBits, cfg.SeqBits, cfg.RandBits, runtime.GOMAXPROCS(0))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "benchmark\tns/op\tops/s\tallocs/op\tB/op\t")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%.1f\t%.0f\t%d\t%d\t\n", r.Name, r.NsPerOp, r.OpsPerSec, r.AllocsPerOp, r.BytesPerOp)
+	}
+	return tw.Flush()
+}
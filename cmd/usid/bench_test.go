@@ -0,0 +1,39 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+func TestBench(t *testing.T) {
+	results, err := bench("10x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make(map[string]bool)
+	for _, r := range results {
+		names[r.Name] = true
+		if r.NsPerOp <= 0 {
+			t.Errorf("%s: %v ns/op", r.Name, r.NsPerOp)
+		}
+	}
+	for _, want := range []string{"Generate", "Format/" + string(usid.FormatBase58), "Parse/" + string(usid.FormatDecimal)} {
+		if !names[want] {
+			t.Errorf("no %s benchmark in %v", want, names)
+		}
+	}
+
+	var b strings.Builder
+	if err := printBench(&b, results); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "allocs/op") {
+		t.Errorf("printBench output:\n%s", b.String())
+	}
+
+	if _, err := bench("soon"); err == nil {
+		t.Error("bench with invalid benchtime: want err != nil")
+	}
+}
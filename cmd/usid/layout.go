@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+// layouts are the presets selectable with -layout.
+var layouts = map[string]func() usid.Config{
+	"default": usid.DefaultConfig,
+	"jssafe":  usid.JSSafeConfig,
+	"milli":   usid.PrecisionMilli,
+	"second":  usid.PrecisionSecond,
+	"random":  usid.RandomConfig,
+	"edge":    usid.EdgeConfig,
+}
+
+// layoutFlags registers -layout, -node-bits, and -seq-bits on fs. The
+// returned function applies the chosen layout with usid.Configure once fs
+// has been parsed.
+func layoutFlags(fs *flag.FlagSet) func() error {
+	layout := fs.String("layout", "default", "layout preset: default, jssafe, milli, second, random, or edge")
+	nodeBits := fs.Int("node-bits", -1, "override the preset's node bits")
+	seqBits := fs.Int("seq-bits", -1, "override the preset's sequence bits")
+	return func() error {
+		preset, ok := layouts[*layout]
+		if !ok {
+			return fmt.Errorf("unknown layout %q", *layout)
+		}
+		cfg := preset()
+		if *nodeBits >= 0 {
+			cfg.NodeBits = uint8(*nodeBits)
+		}
+		if *seqBits >= 0 {
+			cfg.SeqBits = uint8(*seqBits)
+		}
+		return usid.Configure(cfg)
+	}
+}
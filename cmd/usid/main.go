@@ -0,0 +1,52 @@
+// Command usid provides maintenance tools for USIDs.
+//
+// Usage:
+//
+//	usid <command> [flags]
+//
+// Commands:
+//
+//	analyze   report statistics over a stream of IDs
+//	bench     measure generation, formatting, and parsing speed
+//	recode    convert ID columns in CSV or JSONL between formats and keys
+//	sim       simulate a fleet of generators for capacity planning
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+var commands = map[string]func(args []string) error{
+	"analyze": runAnalyze,
+	"bench":   runBench,
+	"recode":  runRecode,
+	"sim":     runSim,
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: usid <command> [flags]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	fmt.Fprintln(os.Stderr, "  analyze   report statistics over a stream of IDs")
+	fmt.Fprintln(os.Stderr, "  bench     measure generation, formatting, and parsing speed")
+	fmt.Fprintln(os.Stderr, "  recode    convert ID columns in CSV or JSONL between formats and keys")
+	fmt.Fprintln(os.Stderr, "  sim       simulate a fleet of generators for capacity planning")
+	fmt.Fprintln(os.Stderr, "\nRun 'usid <command> -h' for command flags.")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "usid: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "usid %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+// recoder converts IDs from one format and obfuscation key to another.
+type recoder struct {
+	columns  []string
+	from, to usid.Format
+	fromKey  *usid.Obfuscator // nil for raw input
+	toKey    *usid.Obfuscator // nil for raw output
+}
+
+func runRecode(args []string) error {
+	fs := flag.NewFlagSet("recode", flag.ExitOnError)
+	in := fs.String("in", "csv", "input encoding: csv or jsonl")
+	cols := fs.String("cols", "id", "comma-separated columns (CSV header names or JSON keys) holding IDs")
+	from := fs.String("from", string(usid.FormatDecimal), "input ID format")
+	to := fs.String("to", string(usid.DefaultFormat), "output ID format")
+	fromKey := fs.String("from-key", os.Getenv("USID_FROM_KEY"), "obfuscation key of the input IDs (default $USID_FROM_KEY; empty for raw)")
+	toKey := fs.String("to-key", os.Getenv("USID_TO_KEY"), "obfuscation key for the output IDs (default $USID_TO_KEY; empty for raw)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: usid recode [flags] < input > output")
+		fmt.Fprintln(fs.Output(), "\nExample: usid recode -cols id,user_id -from decimal -to base58 < ids.csv")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	r, err := newRecoder(strings.Split(*cols, ","), usid.Format(*from), usid.Format(*to), *fromKey, *toKey)
+	if err != nil {
+		return err
+	}
+	// The recoder applies keys itself; keep the global obfuscator out of the way.
+	usid.DefaultObfuscator = nil
+
+	w := bufio.NewWriterSize(os.Stdout, 1<<16)
+	switch *in {
+	case "csv":
+		err = r.csv(bufio.NewReaderSize(os.Stdin, 1<<16), w)
+	case "jsonl":
+		err = r.jsonl(os.Stdin, w)
+	default:
+		err = fmt.Errorf("unknown input encoding %q", *in)
+	}
+	if ferr := w.Flush(); err == nil {
+		err = ferr
+	}
+	return err
+}
+
+func newRecoder(columns []string, from, to usid.Format, fromKey, toKey string) (*recoder, error) {
+	formats := usid.Formats()
+	for _, f := range []usid.Format{from, to} {
+		if !slices.Contains(formats, f) {
+			return nil, fmt.Errorf("unknown format %q", f)
+		}
+	}
+	r := &recoder{columns: columns, from: from, to: to}
+	var err error
+	if r.fromKey, err = parseKey(fromKey); err != nil {
+		return nil, err
+	}
+	if r.toKey, err = parseKey(toKey); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func parseKey(s string) (*usid.Obfuscator, error) {
+	if s == "" {
+		return nil, nil
+	}
+	key, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("obfuscation key must be a decimal int64: %w", err)
+	}
+	return usid.NewObfuscator(key), nil
+}
+
+// recode converts a single ID string. Empty strings pass through.
+func (r *recoder) recode(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	id, err := usid.ParseFormat(s, r.from)
+	if err != nil {
+		return "", fmt.Errorf("%q: %w", s, err)
+	}
+	if r.fromKey != nil {
+		id = r.fromKey.Deobfuscate(id)
+	}
+	if r.toKey != nil {
+		id = r.toKey.Obfuscate(id)
+	}
+	return id.Format(r.to), nil
+}
+
+// csv recodes the named columns of a CSV stream with a header row.
+func (r *recoder) csv(in io.Reader, out io.Writer) error {
+	cr := csv.NewReader(in)
+	cr.ReuseRecord = true
+	cw := csv.NewWriter(out)
+
+	header, err := cr.Read()
+	if err != nil {
+		return err
+	}
+	var idx []int
+	for _, col := range r.columns {
+		i := slices.Index(header, col)
+		if i < 0 {
+			return fmt.Errorf("column %q not in header", col)
+		}
+		idx = append(idx, i)
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		for _, i := range idx {
+			if rec[i], err = r.recode(rec[i]); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// jsonl recodes the named top-level keys of each JSON object in a JSONL
+// stream, preserving key order and all other values byte for byte. String
+// values stay strings; numeric values stay numbers when the output format is
+// decimal. Nulls pass through.
+func (r *recoder) jsonl(in io.Reader, out io.Writer) error {
+	sc := bufio.NewScanner(in)
+	sc.Buffer(make([]byte, 0, 1<<16), 64<<20)
+	var buf bytes.Buffer
+	for line := 1; sc.Scan(); line++ {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		buf.Reset()
+		if err := r.object(sc.Bytes(), &buf); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		buf.WriteByte('\n')
+		if _, err := out.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// object rewrites one JSON object into buf. It scans only the top level,
+// copying everything except the values of the named keys byte for byte.
+func (r *recoder) object(data []byte, buf *bytes.Buffer) error {
+	i := skipSpace(data, 0)
+	if i == len(data) || data[i] != '{' {
+		return errors.New("expected a JSON object")
+	}
+	depth, expectKey := 0, false
+	for i < len(data) {
+		switch c := data[i]; c {
+		case '"':
+			end, err := stringEnd(data, i)
+			if err != nil {
+				return err
+			}
+			if depth != 1 || !expectKey {
+				buf.Write(data[i:end])
+				i = end
+				continue
+			}
+			key := data[i:end]
+			buf.Write(key)
+			expectKey = false
+			j := skipSpace(data, end)
+			if j == len(data) || data[j] != ':' {
+				return errors.New("expected ':' after object key")
+			}
+			j = skipSpace(data, j+1)
+			buf.Write(data[end:j])
+			if !r.matches(key) {
+				i = j
+				continue
+			}
+			vend, err := valueEnd(data, j)
+			if err != nil {
+				return err
+			}
+			v, err := r.value(data[j:vend])
+			if err != nil {
+				return fmt.Errorf("key %s: %w", key, err)
+			}
+			buf.Write(v)
+			i = vend
+		case '{', '[':
+			depth++
+			expectKey = depth == 1
+			buf.WriteByte(c)
+			i++
+		case '}', ']':
+			depth--
+			buf.WriteByte(c)
+			i++
+		case ',':
+			expectKey = depth == 1
+			buf.WriteByte(c)
+			i++
+		default:
+			buf.WriteByte(c)
+			i++
+		}
+	}
+	if depth != 0 {
+		return errors.New("unterminated JSON object")
+	}
+	return nil
+}
+
+// matches reports whether the quoted JSON key names one of r's columns.
+func (r *recoder) matches(quoted []byte) bool {
+	key := string(quoted[1 : len(quoted)-1])
+	if bytes.IndexByte(quoted, '\\') >= 0 {
+		if err := json.Unmarshal(quoted, &key); err != nil {
+			return false
+		}
+	}
+	return slices.Contains(r.columns, key)
+}
+
+func skipSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\r' || data[i] == '\n') {
+		i++
+	}
+	return i
+}
+
+// stringEnd returns the index just past the JSON string starting at data[i].
+func stringEnd(data []byte, i int) (int, error) {
+	for j := i + 1; j < len(data); j++ {
+		switch data[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1, nil
+		}
+	}
+	return 0, errors.New("unterminated JSON string")
+}
+
+// valueEnd returns the index just past the scalar JSON value at data[i].
+func valueEnd(data []byte, i int) (int, error) {
+	if i < len(data) && data[i] == '"' {
+		return stringEnd(data, i)
+	}
+	j := i
+	for j < len(data) && !strings.ContainsRune(",}] \t\r\n", rune(data[j])) {
+		j++
+	}
+	if j == i {
+		return 0, errors.New("expected a value")
+	}
+	return j, nil
+}
+
+// value recodes a raw JSON string or number.
+func (r *recoder) value(raw json.RawMessage) (json.RawMessage, error) {
+	switch {
+	case string(raw) == "null":
+		return raw, nil
+	case len(raw) > 0 && raw[0] == '"':
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+		out, err := r.recode(s)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(out)
+	default:
+		out, err := r.recode(string(raw))
+		if err != nil {
+			return nil, err
+		}
+		if r.to == usid.FormatDecimal {
+			return json.RawMessage(out), nil
+		}
+		return json.Marshal(out)
+	}
+}
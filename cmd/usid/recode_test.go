@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+func TestRecodeCSV(t *testing.T) {
+	r, err := newRecoder([]string{"id", "parent"}, usid.FormatDecimal, usid.FormatBase58, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := "name,id,parent\nalice,57,\nbob,58,1\n"
+	var out bytes.Buffer
+	if err := r.csv(strings.NewReader(in), &out); err != nil {
+		t.Fatal(err)
+	}
+	if want := "name,id,parent\nalice,z,\nbob,21,2\n"; out.String() != want {
+		t.Errorf("csv output:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	err = r.csv(strings.NewReader("id,parent\nnope,1\n"), &out)
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("bad value error = %v, want line number", err)
+	}
+}
+
+func TestRecodeJSONL(t *testing.T) {
+	// Deobfuscate with key 3 and emit raw decimal
+	r, err := newRecoder([]string{"id", "ref"}, usid.FormatDecimal, usid.FormatDecimal, "3", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := `{"z":1,"id":"1","ref":2,"nested":{"id":"keep"}}` + "\n\n" + `{"id":null, "i\u0064" : 3}` + "\n"
+	var out bytes.Buffer
+	if err := r.jsonl(strings.NewReader(in), &out); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"z":1,"id":"2","ref":1,"nested":{"id":"keep"}}` + "\n" + `{"id":null, "i\u0064" : 0}` + "\n"
+	if out.String() != want {
+		t.Errorf("jsonl output:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	for _, bad := range []string{`[1]`, `{"id":"x"}`, `{"id":"1"`, `{"id":`} {
+		if err := r.jsonl(strings.NewReader(bad), &out); err == nil {
+			t.Errorf("jsonl(%s): want error", bad)
+		}
+	}
+}
+
+func TestRecodeKeys(t *testing.T) {
+	id := usid.New()
+	from := usid.NewObfuscator(11).Obfuscate(id)
+	r, err := newRecoder(nil, usid.FormatCrockford, usid.FormatBase58, "11", "22")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := r.recode(from.Format(usid.FormatCrockford))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := usid.NewObfuscator(22).Obfuscate(id).Format(usid.FormatBase58); got != want {
+		t.Errorf("recode = %s, want %s", got, want)
+	}
+	if _, err := newRecoder(nil, "nope", usid.FormatBase58, "", ""); err == nil {
+		t.Error("unknown format: want error")
+	}
+}
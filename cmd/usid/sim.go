@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/sim"
+)
+
+func runSim(args []string) error {
+	fs := flag.NewFlagSet("sim", flag.ExitOnError)
+	configure := layoutFlags(fs)
+	nodes := fs.Int("nodes", 4, "number of nodes")
+	rate := fs.Float64("rate", 1000, "IDs per second per node")
+	duration := fs.Duration("duration", 10*time.Second, "simulated time")
+	skew := fs.Duration("skew", 0, "spread node clocks evenly across ±skew")
+	regress := fs.Duration("regress", 0, "step every node's clock back by this much halfway through")
+	shared := fs.Int("shared", 0, "give this many nodes the same node ID as node 1")
+	monotonic := fs.Bool("monotonic", false, "create generators with WithMonotonic")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: usid sim [flags]")
+		fmt.Fprintln(fs.Output(), "\nSimulates a fleet of generators and reports collisions, out-of-order IDs,")
+		fmt.Fprintln(fs.Output(), "and stalls from sequence exhaustion.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if err := configure(); err != nil {
+		return err
+	}
+	if *nodes <= 0 || *shared >= *nodes {
+		return fmt.Errorf("need more nodes than -shared")
+	}
+
+	report, err := sim.Run(scenario(*nodes, *rate, *duration, *skew, *regress, *shared, *monotonic))
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	_, err = fmt.Print(report)
+	return err
+}
+
+// scenario builds the simulation described by the sim command's flags.
+func scenario(nodes int, rate float64, duration, skew, regress time.Duration, shared int, monotonic bool) sim.Scenario {
+	s := sim.Scenario{Duration: duration}
+	for i := range nodes {
+		n := sim.Node{ID: int64(i) + 1, Rate: rate}
+		if i <= shared {
+			n.ID = 1
+		}
+		if nodes > 1 {
+			n.Skew = -skew + 2*skew*time.Duration(i)/time.Duration(nodes-1)
+		}
+		if regress > 0 {
+			n.Regressions = []sim.Regression{{At: duration / 2, By: regress}}
+		}
+		if monotonic {
+			n.Options = []usid.Option{usid.WithMonotonic()}
+		}
+		s.Nodes = append(s.Nodes, n)
+	}
+	return s
+}
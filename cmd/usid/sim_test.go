@@ -0,0 +1,22 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScenario(t *testing.T) {
+	s := scenario(3, 100, time.Second, 10*time.Millisecond, time.Millisecond, 1, true)
+	if len(s.Nodes) != 3 || s.Duration != time.Second {
+		t.Fatalf("scenario = %+v", s)
+	}
+	for i, want := range []struct {
+		id   int64
+		skew time.Duration
+	}{{1, -10 * time.Millisecond}, {1, 0}, {3, 10 * time.Millisecond}} {
+		n := s.Nodes[i]
+		if n.ID != want.id || n.Skew != want.skew || len(n.Regressions) != 1 || len(n.Options) != 1 {
+			t.Errorf("node %d = %+v, want ID %d, skew %s", i, n, want.id, want.skew)
+		}
+	}
+}
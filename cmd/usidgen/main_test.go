@@ -0,0 +1,47 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestParseEntity(t *testing.T) {
+	e, err := parseEntity("OrderLine:ol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Type() != "OrderLineID" || e.Prefix != "ol" || e.Domain() != "order_line_id" {
+		t.Errorf("parseEntity = %+v (type %s, domain %s)", e, e.Type(), e.Domain())
+	}
+	for _, bad := range []string{"user", "User:US", "User-x", ""} {
+		if _, err := parseEntity(bad); err == nil {
+			t.Errorf("parseEntity(%q): want error", bad)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	src, err := generate("models", []entity{{Name: "User", Prefix: "usr"}, {Name: "Order"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), "ids_gen.go", src, 0)
+	if err != nil {
+		t.Fatalf("generated code does not parse: %v", err)
+	}
+	if f.Name.Name != "models" {
+		t.Errorf("package = %s, want models", f.Name.Name)
+	}
+	for _, want := range []string{"type UserID usid.ID", `UserIDPrefix = "usr_"`, "type OrderID usid.ID", "func (id OrderID) MarshalJSON()", "func (id *UserID) UnmarshalParam("} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated code lacks %q", want)
+		}
+	}
+
+	sql := generateSQL([]entity{{Name: "User"}})
+	if !strings.Contains(sql, "CREATE DOMAIN user_id AS bigint") {
+		t.Errorf("generateSQL = %s", sql)
+	}
+}
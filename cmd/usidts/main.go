@@ -0,0 +1,185 @@
+// Command usidts emits a TypeScript (or JavaScript) module that encodes,
+// decodes, and inspects IDs exactly as this package does, using the alphabets
+// and bit layout compiled into the Go source of truth.
+//
+// Usage:
+//
+//	usidts [-preset name] [-lang ts|js] [-o file]
+//
+// The preset is one of default, jssafe, milli, second, or random, matching
+// usid.DefaultConfig, usid.JSSafeConfig, usid.PrecisionMilli,
+// usid.PrecisionSecond, and usid.RandomConfig. IDs are represented as bigint.
+// Obfuscated IDs are not supported; the obfuscation key must stay server-side.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/base58"
+	"github.com/paraglidehq/usid/v2/crockford"
+)
+
+var presets = map[string]func() usid.Config{
+	"default": usid.DefaultConfig,
+	"jssafe":  usid.JSSafeConfig,
+	"milli":   usid.PrecisionMilli,
+	"second":  usid.PrecisionSecond,
+	"random":  usid.RandomConfig,
+}
+
+func main() {
+	preset := flag.String("preset", "default", "layout preset: default, jssafe, milli, second, or random")
+	lang := flag.String("lang", "ts", "output language: ts or js")
+	out := flag.String("o", "", "output file (default: stdout)")
+	flag.Parse()
+
+	cfg, ok := presets[*preset]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "usidts: unknown preset %q\n", *preset)
+		os.Exit(2)
+	}
+	if *lang != "ts" && *lang != "js" {
+		fmt.Fprintf(os.Stderr, "usidts: unknown language %q\n", *lang)
+		os.Exit(2)
+	}
+	src, err := generate(cfg(), *lang == "ts")
+	if err == nil {
+		if *out == "" {
+			_, err = os.Stdout.Write(src)
+		} else {
+			err = os.WriteFile(*out, src, 0o644)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "usidts:", err)
+		os.Exit(1)
+	}
+}
+
+// alphabet recovers an encoding's digits by encoding each digit value, so the
+// emitted module can never drift from the Go encoders.
+func alphabet(encode func(int64) string, base int) string {
+	var b strings.Builder
+	for i := range int64(base) {
+		b.WriteString(encode(i))
+	}
+	return b.String()
+}
+
+// generate returns the module source for cfg, with type annotations when ts
+// is true.
+func generate(cfg usid.Config, ts bool) ([]byte, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err := template.Must(template.New("module").Funcs(template.FuncMap{
+		// t emits a type annotation only for TypeScript.
+		"t": func(s string) string {
+			if ts {
+				return s
+			}
+			return ""
+		},
+	}).Parse(moduleTemplate)).Execute(&buf, struct {
+		Config          usid.Config
+		PrecisionMicros int64
+		Base58          string
+		Crockford       string
+		Base58Len       int
+		CrockfordLen    int
+	}{
+		Config:          cfg,
+		PrecisionMicros: max(cfg.Precision.Microseconds(), 1),
+		Base58:          alphabet(base58.Encode, 58),
+		Crockford:       alphabet(crockford.Encode, 32),
+		Base58Len:       base58.MaxLen,
+		CrockfordLen:    crockford.MaxLen,
+	})
+	return buf.Bytes(), err
+}
+
+const moduleTemplate = `// Code generated by usidts. DO NOT EDIT.
+
+export const EPOCH_MICROS = {{.Config.Epoch}}n;
+export const PRECISION_MICROS = {{.PrecisionMicros}}n;
+export const NODE_BITS = {{.Config.NodeBits}}n;
+export const SEQ_BITS = {{.Config.SeqBits}}n;
+
+const TIME_SHIFT = NODE_BITS + SEQ_BITS;
+const MAX_INT64 = (1n << 63n) - 1n;
+const BASE58 = "{{.Base58}}";
+const CROCKFORD = "{{.Crockford}}";
+
+export function encodeBase58(id{{t ": bigint"}}){{t ": string"}} {
+  if (id < 0n) return "";
+  if (id === 0n) return "1";
+  let s = "";
+  for (; id > 0n; id /= 58n) s = BASE58[Number(id % 58n)] + s;
+  return s;
+}
+
+export function encodeBase58Fixed(id{{t ": bigint"}}){{t ": string"}} {
+  return id < 0n ? "" : encodeBase58(id).padStart({{.Base58Len}}, "1");
+}
+
+export function decodeBase58(s{{t ": string"}}){{t ": bigint"}} {
+  if (s.length > {{.Base58Len}}) throw new Error("usid: invalid base58 character");
+  let id = 0n;
+  for (const c of s) {
+    const v = BASE58.indexOf(c);
+    if (v < 0) throw new Error("usid: invalid base58 character");
+    id = id * 58n + BigInt(v);
+    if (id > MAX_INT64) throw new Error("usid: base58 value overflows int64");
+  }
+  return id;
+}
+
+export function encodeCrockford(id{{t ": bigint"}}){{t ": string"}} {
+  if (id < 0n) return "";
+  if (id === 0n) return "0";
+  let s = "";
+  for (; id > 0n; id >>= 5n) s = CROCKFORD[Number(id & 31n)] + s;
+  return s;
+}
+
+export function encodeCrockfordFixed(id{{t ": bigint"}}){{t ": string"}} {
+  return id < 0n ? "" : encodeCrockford(id).padStart({{.CrockfordLen}}, "0");
+}
+
+// Case-insensitive; I and L read as 1, O as 0, and hyphens are ignored.
+export function decodeCrockford(s{{t ": string"}}){{t ": bigint"}} {
+  let id = 0n;
+  let n = 0;
+  for (const c of s.toLowerCase()) {
+    if (c === "-") continue;
+    const v = c === "i" || c === "l" ? 1 : c === "o" ? 0 : CROCKFORD.indexOf(c);
+    if (v < 0 || ++n > {{.CrockfordLen}}) throw new Error("usid: invalid crockford character");
+    id = (id << 5n) | BigInt(v);
+    if (id > MAX_INT64) throw new Error("usid: crockford value overflows int64");
+  }
+  return id;
+}
+
+export function timestampMicros(id{{t ": bigint"}}){{t ": bigint"}} {
+  return (id >> TIME_SHIFT) * PRECISION_MICROS + EPOCH_MICROS;
+}
+
+export function timestamp(id{{t ": bigint"}}){{t ": Date"}} {
+  return new Date(Number(timestampMicros(id) / 1000n));
+}
+
+export function node(id{{t ": bigint"}}){{t ": number"}} {
+  return Number((id >> SEQ_BITS) & ((1n << NODE_BITS) - 1n));
+}
+
+export function seq(id{{t ": bigint"}}){{t ": number"}} {
+  return Number(id & ((1n << SEQ_BITS) - 1n));
+}
+`
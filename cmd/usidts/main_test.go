@@ -0,0 +1,34 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+func TestGenerate(t *testing.T) {
+	ts, err := generate(usid.PrecisionMilli(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"export const PRECISION_MICROS = 1000n;",
+		"export const NODE_BITS = 10n;",
+		`const BASE58 = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz";`,
+		`const CROCKFORD = "0123456789abcdefghjkmnpqrstvwxyz";`,
+		"export function decodeCrockford(s: string): bigint {",
+	} {
+		if !strings.Contains(string(ts), want) {
+			t.Errorf("TypeScript output lacks %q", want)
+		}
+	}
+
+	js, err := generate(usid.DefaultConfig(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(js), ": bigint") {
+		t.Error("JavaScript output contains type annotations")
+	}
+}
@@ -0,0 +1,151 @@
+//go:build js && wasm
+
+// Command usidwasm exposes the usid codec to JavaScript when built for
+// js/wasm, so browsers and Node use the exact Go implementation, including
+// obfuscation, rather than a reimplementation.
+//
+// Build it with:
+//
+//	GOOS=js GOARCH=wasm go build -o usid.wasm ./cmd/usidwasm
+//
+// and load it through usid.js alongside Go's wasm_exec.js. The module sets
+// globalThis.usid to an object with parse, format, inspect, configure, and
+// setObfuscationKey. IDs cross the boundary as decimal strings, and every
+// function returns {value} or {error}; usid.js converts these to bigint
+// values and exceptions.
+package main
+
+import (
+	"errors"
+	"strconv"
+	"syscall/js"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+func main() {
+	js.Global().Set("usid", js.ValueOf(map[string]any{
+		"parse":             export(parse),
+		"format":            export(format),
+		"inspect":           export(inspect),
+		"configure":         export(configure),
+		"setObfuscationKey": export(setObfuscationKey),
+	}))
+	select {}
+}
+
+// export wraps fn as a JavaScript function returning {value} or {error}.
+func export(fn func(args []js.Value) (any, error)) js.Func {
+	return js.FuncOf(func(_ js.Value, args []js.Value) any {
+		v, err := fn(args)
+		if err != nil {
+			return map[string]any{"error": err.Error()}
+		}
+		return map[string]any{"value": v}
+	})
+}
+
+// arg returns args[i] as a string, or "" if it is missing or undefined.
+func arg(args []js.Value, i int) string {
+	if i >= len(args) || args[i].IsUndefined() || args[i].IsNull() {
+		return ""
+	}
+	return args[i].String()
+}
+
+// parseArg parses args[0] in the format named by args[1], or
+// usid.DefaultFormat if none is given.
+func parseArg(args []js.Value) (usid.ID, error) {
+	if f := usid.Format(arg(args, 1)); f != "" {
+		return usid.ParseFormat(arg(args, 0), f)
+	}
+	return usid.Parse(arg(args, 0))
+}
+
+// parse(s, format?) returns the decimal value of s.
+func parse(args []js.Value) (any, error) {
+	id, err := parseArg(args)
+	if err != nil {
+		return nil, err
+	}
+	return strconv.FormatInt(id.Int64(), 10), nil
+}
+
+// format(decimal, format?) encodes an ID given as a decimal string.
+func format(args []js.Value) (any, error) {
+	n, err := strconv.ParseInt(arg(args, 0), 10, 64)
+	if err != nil {
+		return nil, errors.New("usid: id must be a decimal string")
+	}
+	if f := usid.Format(arg(args, 1)); f != "" {
+		return usid.ID(n).Format(f), nil
+	}
+	return usid.ID(n).String(), nil
+}
+
+// inspect(s, format?) returns the components of s.
+func inspect(args []js.Value) (any, error) {
+	id, err := parseArg(args)
+	if err != nil {
+		return nil, err
+	}
+	c := id.Components()
+	formats := make(map[string]any, len(c.Formats))
+	for f, s := range c.Formats {
+		formats[string(f)] = s
+	}
+	return map[string]any{
+		"timestamp": c.Timestamp.UTC().Format(time.RFC3339Nano),
+		"node":      c.Node,
+		"seq":       c.Seq,
+		"raw":       strconv.FormatInt(c.Raw, 10),
+		"formats":   formats,
+	}, nil
+}
+
+// configure({epoch, precisionMicros, timeBits, nodeBits, seqBits, randBits})
+// applies a layout; omitted fields keep their defaults.
+func configure(args []js.Value) (any, error) {
+	cfg := usid.DefaultConfig()
+	if len(args) > 0 && args[0].Type() == js.TypeObject {
+		o := args[0]
+		if v := o.Get("epoch"); !v.IsUndefined() {
+			epoch, err := strconv.ParseInt(v.String(), 10, 64)
+			if err != nil {
+				return nil, errors.New("usid: epoch must be an integer number of microseconds")
+			}
+			cfg.Epoch = epoch
+		}
+		if v := o.Get("precisionMicros"); !v.IsUndefined() {
+			cfg.Precision = time.Duration(v.Int()) * time.Microsecond
+		}
+		for name, dst := range map[string]*uint8{
+			"timeBits": &cfg.TimeBits,
+			"nodeBits": &cfg.NodeBits,
+			"seqBits":  &cfg.SeqBits,
+			"randBits": &cfg.RandBits,
+		} {
+			if v := o.Get(name); !v.IsUndefined() {
+				*dst = uint8(v.Int())
+			}
+		}
+	}
+	return nil, usid.Configure(cfg)
+}
+
+// setObfuscationKey(decimal) sets usid.DefaultObfuscator; an empty key
+// disables obfuscation.
+func setObfuscationKey(args []js.Value) (any, error) {
+	key := arg(args, 0)
+	if key == "" {
+		usid.DefaultObfuscator = nil
+		return nil, nil
+	}
+	n, err := strconv.ParseInt(key, 10, 64)
+	if err != nil {
+		return nil, errors.New("usid: obfuscation key must be a decimal string")
+	}
+	usid.DefaultObfuscator = usid.NewObfuscator(n)
+	return nil, nil
+}
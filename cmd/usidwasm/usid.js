@@ -0,0 +1,32 @@
+// Loader for usid.wasm. Load Go's wasm_exec.js first (it defines
+// globalThis.Go), then:
+//
+//   import { load } from "./usid.js";
+//   const usid = await load(fetch("usid.wasm"));
+//   usid.parse("gb61dv03w20");         // 18408072896704576n
+//   usid.format(18408072896704576n);   // "gb61dv03w20"
+//
+// source may be a Response (or a promise of one) or the module's bytes.
+export async function load(source) {
+  source = await source;
+  const bytes = source instanceof Response ? await source.arrayBuffer() : source;
+  const go = new globalThis.Go();
+  const { instance } = await WebAssembly.instantiate(bytes, go.importObject);
+  go.run(instance); // registers globalThis.usid before returning
+
+  const api = globalThis.usid;
+  const unwrap = (r) => {
+    if (r.error) throw new Error(r.error);
+    return r.value;
+  };
+  return {
+    parse: (s, format = "") => BigInt(unwrap(api.parse(s, format))),
+    format: (id, format = "") => unwrap(api.format(String(id), format)),
+    inspect: (s, format = "") => {
+      const c = unwrap(api.inspect(s, format));
+      return { ...c, timestamp: new Date(c.timestamp), raw: BigInt(c.raw) };
+    },
+    configure: (cfg) => unwrap(api.configure({ ...cfg, epoch: cfg.epoch == null ? undefined : String(cfg.epoch) })),
+    setObfuscationKey: (key) => unwrap(api.setObfuscationKey(key == null ? "" : String(key))),
+  };
+}
@@ -3,7 +3,15 @@ package usid
 import (
 	"bytes"
 	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"math/rand/v2"
+	"strings"
 	"testing"
+
+	"github.com/paraglidehq/usid/v2/crockford"
 )
 
 // codecTestID is a sample ID for codec testing
@@ -64,6 +72,19 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParseFormat(t *testing.T) {
+	for _, f := range Formats() {
+		got, err := ParseFormat(codecTestID.Format(f), f)
+		if err != nil {
+			t.Errorf("ParseFormat(%s): %v", f, err)
+			continue
+		}
+		if got != codecTestID {
+			t.Errorf("ParseFormat(%s): got %v, want %v", f, got, codecTestID)
+		}
+	}
+}
+
 func TestParseCrockford(t *testing.T) {
 	s := codecTestID.Format(FormatCrockford)
 	got, err := ParseCrockford(s)
@@ -75,6 +96,63 @@ func TestParseCrockford(t *testing.T) {
 	}
 }
 
+func TestParseCrockfordStrict(t *testing.T) {
+	for _, id := range []ID{Nil, 1, 31, 32, codecTestID, Omni} {
+		for _, f := range []Format{FormatCrockford, FormatCrockfordFixed} {
+			s := id.Format(f)
+			if got, err := ParseCrockfordStrict(s); err != nil || got != id {
+				t.Errorf("ParseCrockfordStrict(%q) = %v, %v; want %v", s, got, err, id)
+			}
+		}
+	}
+
+	s := ID(123456789).Format(FormatCrockford)
+	for _, bad := range []string{strings.ToUpper(s), s[:3] + "-" + s[3:], "0" + s, "1o", "i", "L0"} {
+		if _, err := ParseCrockfordStrict(bad); !errors.Is(err, crockford.ErrNotCanonical) {
+			t.Errorf("ParseCrockfordStrict(%q): err = %v, want ErrNotCanonical", bad, err)
+		}
+		if _, err := ParseCrockford(bad); err != nil {
+			t.Errorf("ParseCrockford(%q) = %v, want lenient success", bad, err)
+		}
+	}
+	for _, bad := range []string{"", "u", "!", "00000000000000", "zzzzzzzzzzzzz"} {
+		if _, err := ParseCrockfordStrict(bad); err == nil || errors.Is(err, crockford.ErrNotCanonical) {
+			t.Errorf("ParseCrockfordStrict(%q): err = %v, want invalid or overflow", bad, err)
+		}
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	want := codecTestID.String()
+	for _, tc := range []struct {
+		s    string
+		from []Format
+	}{
+		{want, nil},
+		{strings.ToUpper(want), nil},
+		{want[:4] + "-" + want[4:], nil},
+		{codecTestID.Format(FormatCrockfordFixed), nil},
+		{codecTestID.Format(FormatBase58), []Format{FormatBase58}},
+		{codecTestID.Format(FormatDecimal), []Format{FormatDecimal, FormatCrockford}},
+	} {
+		if got, err := Canonicalize(tc.s, tc.from...); err != nil || got != want {
+			t.Errorf("Canonicalize(%q, %v) = %q, %v; want %q", tc.s, tc.from, got, err, want)
+		}
+	}
+	if _, err := Canonicalize("!!", FormatBase58, FormatDecimal); err == nil {
+		t.Error("Canonicalize(invalid): want err != nil")
+	}
+
+	if !IsCanonical(want) {
+		t.Errorf("IsCanonical(%q) = false", want)
+	}
+	for _, s := range []string{strings.ToUpper(want), "0" + want, "", "!"} {
+		if IsCanonical(s) {
+			t.Errorf("IsCanonical(%q) = true", s)
+		}
+	}
+}
+
 func TestParseBase58(t *testing.T) {
 	s := codecTestID.Format(FormatBase58)
 	got, err := ParseBase58(s)
@@ -86,6 +164,17 @@ func TestParseBase58(t *testing.T) {
 	}
 }
 
+func TestParseBase62(t *testing.T) {
+	s := codecTestID.Format(FormatBase62)
+	got, err := ParseBase62(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != codecTestID {
+		t.Errorf("ParseBase62(%q): got %v, want %v", s, got, codecTestID)
+	}
+}
+
 func TestParseBase64(t *testing.T) {
 	s := codecTestID.Format(FormatBase64)
 	got, err := ParseBase64(s)
@@ -97,6 +186,20 @@ func TestParseBase64(t *testing.T) {
 	}
 }
 
+func TestParseBase64URL(t *testing.T) {
+	s := codecTestID.Format(FormatBase64URL)
+	if strings.ContainsAny(s, "+/=") {
+		t.Errorf("Format(FormatBase64URL) = %q, want no '+', '/' or '='", s)
+	}
+	got, err := ParseBase64URL(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != codecTestID {
+		t.Errorf("ParseBase64URL(%q): got %v, want %v", s, got, codecTestID)
+	}
+}
+
 func TestParseHash(t *testing.T) {
 	s := codecTestID.Format(FormatHash)
 	got, err := ParseHash(s)
@@ -126,7 +229,9 @@ func TestParseEmpty(t *testing.T) {
 	}{
 		{"ParseCrockford", ParseCrockford},
 		{"ParseBase58", ParseBase58},
+		{"ParseBase62", ParseBase62},
 		{"ParseBase64", ParseBase64},
+		{"ParseBase64URL", ParseBase64URL},
 		{"ParseHash", ParseHash},
 		{"ParseDecimal", ParseDecimal},
 	}
@@ -140,6 +245,45 @@ func TestParseEmpty(t *testing.T) {
 	}
 }
 
+func TestParseOverflow(t *testing.T) {
+	t.Run("Base58", func(t *testing.T) {
+		valid := Omni.Format(FormatBase58)
+		got, err := ParseBase58(valid)
+		if err != nil || got != Omni {
+			t.Fatalf("ParseBase58(%q) = %v, %v; want %v", valid, got, err, Omni)
+		}
+		for _, s := range []string{"NQm6nKp8qFD", "zzzzzzzzzzz", "111111111111"} {
+			if _, err := ParseBase58(s); err == nil {
+				t.Errorf("ParseBase58(%q): want err != nil", s)
+			}
+		}
+	})
+	t.Run("Base62", func(t *testing.T) {
+		valid := Omni.Format(FormatBase62)
+		got, err := ParseBase62(valid)
+		if err != nil || got != Omni {
+			t.Fatalf("ParseBase62(%q) = %v, %v; want %v", valid, got, err, Omni)
+		}
+		for _, s := range []string{"AzL8n0Y58m8", "zzzzzzzzzzz", "000000000000", "abc-def"} {
+			if _, err := ParseBase62(s); err == nil {
+				t.Errorf("ParseBase62(%q): want err != nil", s)
+			}
+		}
+	})
+	t.Run("Crockford", func(t *testing.T) {
+		valid := Omni.Format(FormatCrockford)
+		got, err := ParseCrockford(valid)
+		if err != nil || got != Omni {
+			t.Fatalf("ParseCrockford(%q) = %v, %v; want %v", valid, got, err, Omni)
+		}
+		for _, s := range []string{"8000000000000", "zzzzzzzzzzzzz", "00000000000000"} {
+			if _, err := ParseCrockford(s); err == nil {
+				t.Errorf("ParseCrockford(%q): want err != nil", s)
+			}
+		}
+	})
+}
+
 func TestFromStringOrNil(t *testing.T) {
 	t.Run("Invalid", func(t *testing.T) {
 		got := FromStringOrNil("invalid!!!")
@@ -163,6 +307,19 @@ func TestFromInt64(t *testing.T) {
 	}
 }
 
+func TestFromUint64(t *testing.T) {
+	got, err := FromUint64(1234567890123456789)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != codecTestID {
+		t.Errorf("FromUint64: got %v, want %v", got, codecTestID)
+	}
+	if _, err := FromUint64(1 << 63); err == nil {
+		t.Error("FromUint64(1<<63): want err != nil")
+	}
+}
+
 func TestIDParseMethod(t *testing.T) {
 	t.Run("Valid", func(t *testing.T) {
 		var id ID
@@ -183,6 +340,56 @@ func TestIDParseMethod(t *testing.T) {
 	})
 }
 
+func TestAppendFormat(t *testing.T) {
+	ids := []ID{Nil, 1, 57, 58, 58*58 - 1, 58 * 58, MaxSafeInteger, Omni, codecTestID, -1, math.MinInt64}
+	for range 1000 {
+		ids = append(ids, ID(rand.Int64()))
+	}
+	prefix := []byte("id=")
+	for _, f := range append(Formats(), "") {
+		for _, id := range ids {
+			want := "id=" + id.Format(f)
+			if f == "" {
+				want = "id=" + id.String()
+			}
+			if got := string(id.AppendFormat(prefix, f)); got != want {
+				t.Fatalf("%d.AppendFormat(%q) = %q, want %q", id, f, got, want)
+			}
+		}
+	}
+
+	// Check Base58 against plain big-integer conversion.
+	const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	for _, id := range ids {
+		if id < 0 {
+			continue
+		}
+		var want []byte
+		n := big.NewInt(id.Int64())
+		for m := new(big.Int); n.Sign() > 0; {
+			n.DivMod(n, big.NewInt(58), m)
+			want = append([]byte{alphabet[m.Int64()]}, want...)
+		}
+		if id == 0 {
+			want = []byte("1")
+		}
+		if got := id.Format(FormatBase58); got != string(want) {
+			t.Fatalf("%d.Format(FormatBase58) = %q, want %q", id, got, want)
+		}
+		fixed := strings.Repeat("1", 11-len(want)) + string(want)
+		if got := id.Format(FormatBase58Fixed); got != fixed {
+			t.Fatalf("%d.Format(FormatBase58Fixed) = %q, want %q", id, got, fixed)
+		}
+	}
+
+	buf := make([]byte, 0, 32)
+	for _, f := range []Format{FormatBase58, FormatBase58Fixed, FormatDecimal, FormatHash, FormatBase64} {
+		if n := testing.AllocsPerRun(100, func() { codecTestID.AppendFormat(buf, f) }); n != 0 {
+			t.Errorf("AppendFormat(%q) allocates %v times, want 0", f, n)
+		}
+	}
+}
+
 func TestMarshalBinary(t *testing.T) {
 	got, err := codecTestID.MarshalBinary()
 	if err != nil {
@@ -193,6 +400,48 @@ func TestMarshalBinary(t *testing.T) {
 	}
 }
 
+func TestVarint(t *testing.T) {
+	for _, tc := range []struct {
+		id  ID
+		len int
+	}{
+		{Nil, 1},
+		{ID(127), 1},
+		{ID(128), 2},
+		{ID(1<<49 - 1), 7},
+		{ID(1<<56 - 1), 8},
+		{codecTestID, len(codecTestID.MarshalVarint())},
+		{Omni, 9},
+		{ID(-1), MaxVarintLen},
+	} {
+		b := tc.id.MarshalVarint()
+		if len(b) != tc.len {
+			t.Errorf("%d.MarshalVarint() is %d bytes, want %d", tc.id, len(b), tc.len)
+		}
+		var got ID
+		if err := got.UnmarshalVarint(b); err != nil || got != tc.id {
+			t.Errorf("UnmarshalVarint(%x) = %d, %v; want %d", b, got, err, tc.id)
+		}
+	}
+
+	var id ID
+	for _, b := range [][]byte{nil, {0x80}, {0x01, 0x02}, bytes.Repeat([]byte{0xff}, 11)} {
+		if err := id.UnmarshalVarint(b); err == nil {
+			t.Errorf("UnmarshalVarint(%x): want err != nil", b)
+		}
+	}
+
+	BinaryVarint = true
+	defer func() { BinaryVarint = false }()
+	b, err := codecTestID.MarshalBinary()
+	if err != nil || !bytes.Equal(b, codecTestID.MarshalVarint()) {
+		t.Fatalf("MarshalBinary() with BinaryVarint = %x, %v", b, err)
+	}
+	if err := id.UnmarshalBinary(b); err != nil || id != codecTestID {
+		t.Errorf("UnmarshalBinary(%x) with BinaryVarint = %d, %v", b, id, err)
+	}
+}
+
 func TestUnmarshalBinary(t *testing.T) {
 	var got ID
 	err := got.UnmarshalBinary(codecTestBytes)
@@ -256,6 +505,44 @@ func TestMarshalJSON(t *testing.T) {
 	}
 }
 
+func TestMarshalJSONNumeric(t *testing.T) {
+	JSONNumeric = true
+	defer func() { JSONNumeric = false }()
+
+	got, err := json.Marshal(codecTestID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "1234567890123456789"; string(got) != want {
+		t.Errorf("MarshalJSON: got %s, want %s", got, want)
+	}
+	var parsed ID
+	if err := json.Unmarshal(got, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed != codecTestID {
+		t.Errorf("roundtrip: got %v, want %v", parsed, codecTestID)
+	}
+}
+
+func TestJSSafe(t *testing.T) {
+	tests := []struct {
+		id   ID
+		want bool
+	}{
+		{Nil, true},
+		{MaxSafeInteger, true},
+		{MaxSafeInteger + 1, false},
+		{-MaxSafeInteger, true},
+		{Omni, false},
+	}
+	for _, tt := range tests {
+		if got := tt.id.JSSafe(); got != tt.want {
+			t.Errorf("ID(%d).JSSafe() = %v, want %v", tt.id.Int64(), got, tt.want)
+		}
+	}
+}
+
 func TestUnmarshalJSON(t *testing.T) {
 	t.Run("String", func(t *testing.T) {
 		var got ID
@@ -303,10 +590,14 @@ func TestIDFormat(t *testing.T) {
 		parse  func(string) (ID, error)
 	}{
 		{FormatCrockford, "Crockford", ParseCrockford},
+		{FormatCrockfordFixed, "CrockfordFixed", ParseCrockford},
 		{FormatBase58, "Base58", ParseBase58},
+		{FormatBase58Fixed, "Base58Fixed", ParseBase58},
+		{FormatBase62, "Base62", ParseBase62},
 		{FormatDecimal, "Decimal", ParseDecimal},
 		{FormatHash, "Hash", ParseHash},
 		{FormatBase64, "Base64", ParseBase64},
+		{FormatBase64URL, "Base64URL", ParseBase64URL},
 	}
 
 	for _, tt := range tests {
@@ -327,6 +618,24 @@ func TestIDFormat(t *testing.T) {
 	}
 }
 
+func TestFixedFormatSortOrder(t *testing.T) {
+	ids := []ID{Nil, 1, 57, 58, 1 << 20, codecTestID, Omni}
+	for _, f := range []Format{FormatBase58Fixed, FormatCrockfordFixed} {
+		t.Run(string(f), func(t *testing.T) {
+			width := len(Omni.Format(f))
+			for i := 1; i < len(ids); i++ {
+				prev, cur := ids[i-1].Format(f), ids[i].Format(f)
+				if len(cur) != width {
+					t.Errorf("Format(%v) = %q, want width %d", ids[i], cur, width)
+				}
+				if prev >= cur {
+					t.Errorf("%q >= %q, want string order to match numeric order", prev, cur)
+				}
+			}
+		})
+	}
+}
+
 func TestMust(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		got := Must(FromString(codecTestID.Format(FormatCrockford)))
@@ -387,6 +696,20 @@ func BenchmarkFormat(b *testing.B) {
 	})
 }
 
+func BenchmarkAppendFormat(b *testing.B) {
+	buf := make([]byte, 0, 32)
+	b.Run("Base58", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			codecTestID.AppendFormat(buf, FormatBase58)
+		}
+	})
+	b.Run("Decimal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			codecTestID.AppendFormat(buf, FormatDecimal)
+		}
+	})
+}
+
 func BenchmarkMarshalBinary(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		codecTestID.MarshalBinary()
@@ -0,0 +1,53 @@
+package usid
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Components is a breakdown of an ID for inspection and debugging.
+type Components struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Node      int64             `json:"node"`
+	Tenant    int64             `json:"tenant,omitempty"`
+	Seq       int64             `json:"seq"`
+	Raw       int64             `json:"raw"`
+	Formats   map[Format]string `json:"formats"`
+}
+
+// Components returns the ID's timestamp, node, tenant, sequence, raw value, and its
+// encoding in every built-in and registered format.
+func (id ID) Components() Components {
+	all := Formats()
+	c := Components{
+		Timestamp: id.Timestamp(),
+		Node:      id.Node(),
+		Tenant:    id.Tenant(),
+		Seq:       id.Seq(),
+		Raw:       id.Int64(),
+		Formats:   make(map[Format]string, len(all)),
+	}
+	for _, f := range all {
+		c.Formats[f] = id.Format(f)
+	}
+	return c
+}
+
+// String returns a multi-line dump of the components, one per line.
+func (c Components) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "timestamp: %s\n", c.Timestamp.UTC().Format(time.RFC3339Nano))
+	fmt.Fprintf(&b, "node:      %d\n", c.Node)
+	if TenantBits > 0 {
+		fmt.Fprintf(&b, "tenant:    %d\n", c.Tenant)
+	}
+	fmt.Fprintf(&b, "seq:       %d\n", c.Seq)
+	fmt.Fprintf(&b, "raw:       %d\n", c.Raw)
+	for _, f := range Formats() {
+		if s, ok := c.Formats[f]; ok {
+			fmt.Fprintf(&b, "%-16s %s\n", string(f)+":", s)
+		}
+	}
+	return b.String()
+}
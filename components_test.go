@@ -0,0 +1,29 @@
+package usid
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComponents(t *testing.T) {
+	SetNodeID(9)
+	defer SetNodeID(1)
+	id := New()
+
+	c := id.Components()
+	if !c.Timestamp.Equal(id.Timestamp()) || c.Node != 9 || c.Seq != id.Seq() || c.Raw != id.Int64() {
+		t.Errorf("Components() = %+v, want fields matching %v", c, id)
+	}
+	for _, f := range Formats() {
+		if got, want := c.Formats[f], id.Format(f); got != want {
+			t.Errorf("Formats[%s] = %q, want %q", f, got, want)
+		}
+	}
+
+	s := c.String()
+	for _, want := range []string{"node:      9\n", "crockford:", id.Format(FormatBase58)} {
+		if !strings.Contains(s, want) {
+			t.Errorf("String() = %q, want it to contain %q", s, want)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package usid
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Config describes a USID bit layout. It mirrors the package-level
+// configuration variables so layouts can be passed around as values.
+type Config struct {
+	Epoch      int64         // Custom epoch in microseconds
+	Precision  time.Duration // Timestamp resolution; zero means time.Microsecond
+	TimeBits   uint8         // Bits for the timestamp; zero means all remaining bits
+	NodeBits   uint8         // Bits allocated for node ID
+	SeqBits    uint8         // Bits allocated for sequence number
+	TenantBits uint8         // Bits between node and sequence for a tenant; zero disables tenants
+	RandBits   uint8         // Low sequence bits filled randomly per ID; at most SeqBits
+	EraBits    uint8         // High bits holding the era; zero disables eras
+	Era        int64         // Era of new IDs, whose timestamps count from Epoch
+
+	DBNodes        NodeRange // Nodes reserved for the database (see DBNodes)
+	StaticNodes    NodeRange // Nodes for assignment by configuration (see StaticNodes)
+	EphemeralNodes NodeRange // Nodes for NewEphemeralGenerator (see EphemeralNodes)
+}
+
+// DefaultConfig returns the default layout: microsecond precision,
+// 51 timestamp bits, 6 node bits, and 6 sequence bits.
+func DefaultConfig() Config {
+	return Config{
+		Epoch:     1765947799213000, // 2025-12-16 in µs
+		Precision: time.Microsecond,
+		NodeBits:  6,
+		SeqBits:   6,
+	}
+}
+
+// JSSafeConfig returns a layout whose IDs never exceed MaxSafeInteger, so they
+// survive JSON.parse as plain JavaScript numbers. It trades precision for range:
+// millisecond timestamps in 41 bits (about 69 years from Epoch) with the default
+// 64 nodes and 64 IDs per millisecond per node.
+func JSSafeConfig() Config {
+	cfg := DefaultConfig()
+	cfg.Precision = time.Millisecond
+	cfg.TimeBits = 41
+	return cfg
+}
+
+// PrecisionMilli returns a Snowflake-like layout with millisecond timestamps in
+// 43 bits (about 278 years from Epoch), 1024 nodes, and 1024 IDs per
+// millisecond per node.
+func PrecisionMilli() Config {
+	cfg := DefaultConfig()
+	cfg.Precision = time.Millisecond
+	cfg.NodeBits = 10
+	cfg.SeqBits = 10
+	return cfg
+}
+
+// PrecisionSecond returns a layout with second timestamps in 33 bits (about
+// 272 years from Epoch), 16384 nodes, and 65536 IDs per second per node.
+func PrecisionSecond() Config {
+	cfg := DefaultConfig()
+	cfg.Precision = time.Second
+	cfg.NodeBits = 14
+	cfg.SeqBits = 16
+	return cfg
+}
+
+// RandomConfig returns a layout whose IDs are not enumerable without an
+// Obfuscator: millisecond timestamps in 41 bits (about 69 years from Epoch),
+// 64 nodes, and a 16-bit sequence field of which the low 12 bits are random,
+// leaving 16 IDs per millisecond per node.
+func RandomConfig() Config {
+	cfg := DefaultConfig()
+	cfg.Precision = time.Millisecond
+	cfg.TimeBits = 41
+	cfg.SeqBits = 16
+	cfg.RandBits = 12
+	return cfg
+}
+
+// CurrentConfig returns the layout described by the package-level variables.
+func CurrentConfig() Config {
+	return Config{
+		Epoch:      Epoch,
+		Precision:  Precision,
+		TimeBits:   TimeBits,
+		NodeBits:   NodeBits,
+		SeqBits:    SeqBits,
+		TenantBits: TenantBits,
+		RandBits:   RandBits,
+		EraBits:    EraBits,
+		Era:        Era,
+
+		DBNodes:        DBNodes,
+		StaticNodes:    StaticNodes,
+		EphemeralNodes: EphemeralNodes,
+	}
+}
+
+// Configure validates cfg, applies it to the package-level variables, and
+// rebuilds DefaultGenerator for the new layout, keeping its node ID and options.
+// Call once at startup before generating any IDs.
+func Configure(cfg Config) error {
+	if cfg.Precision == 0 {
+		cfg.Precision = time.Microsecond
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	node := int64(1)
+	var opts []Option
+	if DefaultGenerator != nil {
+		node, opts = DefaultGenerator.node, DefaultGenerator.opts
+	}
+	return configure(cfg, node, opts)
+}
+
+// configure applies a validated cfg and rebuilds DefaultGenerator for node.
+func configure(cfg Config, node int64, opts []Option) error {
+	if node > cfg.MaxNode() {
+		return fmt.Errorf("usid: node ID %d does not fit in %d node bits", node, cfg.NodeBits)
+	}
+	if cfg.DBNodes.Contains(node) || cfg.EphemeralNodes.Contains(node) {
+		return fmt.Errorf("usid: node ID %d is reserved", node)
+	}
+	if err := checkEras(cfg.EraBits); err != nil {
+		return err
+	}
+	Epoch = cfg.Epoch
+	Precision = cfg.Precision
+	TimeBits = cfg.TimeBits
+	NodeBits = cfg.NodeBits
+	SeqBits = cfg.SeqBits
+	TenantBits = cfg.TenantBits
+	RandBits = cfg.RandBits
+	EraBits = cfg.EraBits
+	Era = cfg.Era
+	DBNodes = cfg.DBNodes
+	StaticNodes = cfg.StaticNodes
+	EphemeralNodes = cfg.EphemeralNodes
+	if EraBits > 0 {
+		RegisterEra(Era, Epoch)
+	}
+	DefaultGenerator = NewGenerator(node, opts...)
+	return nil
+}
+
+// Validate reports whether the layout is usable.
+func (c Config) Validate() error {
+	fixed := int(c.EraBits) + int(c.NodeBits) + int(c.TenantBits) + int(c.SeqBits)
+	if fixed >= 63 || fixed+int(c.TimeBits) > 63 {
+		return fmt.Errorf("usid: layout of %d era, %d time, %d node, %d tenant, and %d sequence bits exceeds 63 bits",
+			c.EraBits, c.TimeBits, c.NodeBits, c.TenantBits, c.SeqBits)
+	}
+	if c.Era < 0 || c.Era >= 1<<c.EraBits {
+		return fmt.Errorf("usid: era %d does not fit in %d era bits", c.Era, c.EraBits)
+	}
+	if c.RandBits > c.SeqBits {
+		return fmt.Errorf("usid: %d random bits exceed %d sequence bits", c.RandBits, c.SeqBits)
+	}
+	if c.Precision < 0 || c.Precision%time.Microsecond != 0 {
+		return errors.New("usid: precision must be a positive whole number of microseconds")
+	}
+	ranges := []struct {
+		name string
+		r    NodeRange
+	}{{"DBNodes", c.DBNodes}, {"StaticNodes", c.StaticNodes}, {"EphemeralNodes", c.EphemeralNodes}}
+	for i, a := range ranges {
+		if a.r.IsZero() {
+			continue
+		}
+		if a.r.Min < 0 || a.r.Min > a.r.Max || a.r.Max > c.MaxNode() {
+			return fmt.Errorf("usid: %s [%d, %d] outside nodes [0, %d]", a.name, a.r.Min, a.r.Max, c.MaxNode())
+		}
+		for _, b := range ranges[:i] {
+			if !b.r.IsZero() && a.r.Min <= b.r.Max && b.r.Min <= a.r.Max {
+				return fmt.Errorf("usid: %s [%d, %d] overlaps %s [%d, %d]", a.name, a.r.Min, a.r.Max, b.name, b.r.Min, b.r.Max)
+			}
+		}
+	}
+	return nil
+}
+
+// MaxNode returns the maximum node ID value.
+func (c Config) MaxNode() int64 { return (1 << c.NodeBits) - 1 }
+
+// MaxSeq returns the maximum sequence number value.
+func (c Config) MaxSeq() int64 { return (1 << c.SeqBits) - 1 }
+
+// MaxTenant returns the maximum tenant value.
+func (c Config) MaxTenant() int64 { return (1 << c.TenantBits) - 1 }
+
+// CollisionProbability returns the probability that at least two of n IDs
+// collide when n generators sharing a node each issue one ID in the same tick.
+// Generators with distinct nodes never collide, and a single generator never
+// repeats itself; only the random bits separate generators sharing a node.
+func (c Config) CollisionProbability(n int) float64 {
+	d := math.Exp2(float64(c.RandBits))
+	if float64(n) > d {
+		return 1
+	}
+	// Birthday problem: 1 - prod(1 - i/d) over the first n draws.
+	unique := 1.0
+	for i := 1; i < n; i++ {
+		unique *= 1 - float64(i)/d
+	}
+	return 1 - unique
+}
+
+// MaxTime returns the last instant the layout can represent.
+func (c Config) MaxTime() time.Time {
+	bits := c.TimeBits
+	if bits == 0 {
+		bits = 63 - c.EraBits - c.NodeBits - c.TenantBits - c.SeqBits
+	}
+	ticks := int64(1)<<bits - 1
+	return time.UnixMicro(c.Epoch + ticks*precisionMicros(c.Precision))
+}
+
+// precisionMicros returns the number of microseconds per timestamp tick.
+func precisionMicros(p time.Duration) int64 {
+	if p <= time.Microsecond {
+		return 1
+	}
+	return p.Microseconds()
+}
@@ -0,0 +1,210 @@
+package usid
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigure(t *testing.T) {
+	t.Run("JSSafe", func(t *testing.T) {
+		if err := Configure(JSSafeConfig()); err != nil {
+			t.Fatal(err)
+		}
+		defer Configure(DefaultConfig())
+
+		before := time.Now().Truncate(time.Millisecond)
+		id := New()
+		after := time.Now()
+
+		if !id.JSSafe() {
+			t.Errorf("New() = %d, want <= MaxSafeInteger", id.Int64())
+		}
+		if ts := id.Timestamp(); ts.Before(before) || ts.After(after) {
+			t.Errorf("Timestamp() = %v, want between %v and %v", ts, before, after)
+		}
+		if got := CurrentConfig(); got != JSSafeConfig() {
+			t.Errorf("CurrentConfig() = %+v, want %+v", got, JSSafeConfig())
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		invalid := []Config{
+			{NodeBits: 40, SeqBits: 30},
+			{TimeBits: 60, NodeBits: 6, SeqBits: 6},
+			{Precision: 1500 * time.Nanosecond, NodeBits: 6, SeqBits: 6},
+			{NodeBits: 6, SeqBits: 6, RandBits: 7},
+		}
+		for _, cfg := range invalid {
+			if err := Configure(cfg); err == nil {
+				Configure(DefaultConfig())
+				t.Errorf("Configure(%+v): want err != nil", cfg)
+			}
+		}
+		if got := CurrentConfig(); got != DefaultConfig() {
+			t.Errorf("CurrentConfig() = %+v after failed Configure, want %+v", got, DefaultConfig())
+		}
+	})
+}
+
+func TestPrecisionPresets(t *testing.T) {
+	for _, cfg := range []Config{PrecisionMilli(), PrecisionSecond()} {
+		if err := Configure(cfg); err != nil {
+			t.Fatalf("Configure(%+v): %v", cfg, err)
+		}
+		// Ticks are aligned to Epoch, not to the wall clock
+		before := time.Now().Add(-cfg.Precision)
+		id := New()
+		if ts := id.Timestamp(); !ts.After(before) || ts.After(time.Now()) {
+			t.Errorf("precision %s: Timestamp() = %v, want ~%v", cfg.Precision, ts, before)
+		}
+		if years := cfg.MaxTime().Sub(time.UnixMicro(cfg.Epoch)).Hours() / 24 / 365; years < 250 {
+			t.Errorf("precision %s: lifetime %.0f years, want > 250", cfg.Precision, years)
+		}
+	}
+	Configure(DefaultConfig())
+}
+
+func TestRandomConfig(t *testing.T) {
+	cfg := RandomConfig()
+	if err := Configure(cfg); err != nil {
+		t.Fatalf("Configure(%+v): %v", cfg, err)
+	}
+	defer Configure(DefaultConfig())
+
+	g := NewGenerator(1)
+	seen := make(map[ID]bool)
+	var prev ID
+	randMask := int64(1)<<cfg.RandBits - 1
+	var randOr int64
+	for i := 0; i < 1000; i++ {
+		id := g.Generate()
+		if seen[id] {
+			t.Fatalf("duplicate ID %d", id)
+		}
+		seen[id] = true
+		if id <= prev {
+			t.Fatalf("ID %d not greater than previous %d", id, prev)
+		}
+		prev = id
+		if id.Node() != 1 {
+			t.Fatalf("Node() = %d, want 1", id.Node())
+		}
+		randOr |= id.Seq() & randMask
+	}
+	if randOr != randMask {
+		t.Errorf("random bits never set: %b", randOr)
+	}
+}
+
+func TestCollisionProbability(t *testing.T) {
+	cfg := RandomConfig()
+	if p := cfg.CollisionProbability(1); p != 0 {
+		t.Errorf("CollisionProbability(1) = %v, want 0", p)
+	}
+	if p := DefaultConfig().CollisionProbability(2); p != 1 {
+		t.Errorf("no random bits: CollisionProbability(2) = %v, want 1", p)
+	}
+	// 2 generators, 12 random bits: ~1/4096
+	if p := cfg.CollisionProbability(2); math.Abs(p-1.0/4096) > 1e-7 {
+		t.Errorf("CollisionProbability(2) = %v, want ~%v", p, 1.0/4096)
+	}
+}
+
+func TestConfigMaxTime(t *testing.T) {
+	cfg := JSSafeConfig()
+	max := cfg.MaxTime()
+	if years := max.Sub(time.UnixMicro(cfg.Epoch)).Hours() / 24 / 365; years < 69 || years > 70 {
+		t.Errorf("JSSafeConfig().MaxTime() is %.1f years after Epoch, want ~69", years)
+	}
+	id := ID((int64(1)<<cfg.TimeBits-1)<<(cfg.NodeBits+cfg.SeqBits) | cfg.MaxNode()<<cfg.SeqBits | cfg.MaxSeq())
+	if id > MaxSafeInteger {
+		t.Errorf("largest JSSafeConfig ID %d exceeds MaxSafeInteger", id)
+	}
+}
+
+func TestTenantBits(t *testing.T) {
+	cfg := PrecisionMilli()
+	cfg.TenantBits = 8
+	if err := Configure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	defer Configure(DefaultConfig())
+
+	before := time.Now().Truncate(time.Millisecond)
+	g := NewGenerator(7, WithTenant(200), WithShards(4))
+	var prev ID
+	for range 100 {
+		id := g.Generate()
+		if id.Node() != 7 || id.Tenant() != 200 {
+			t.Fatalf("Node(), Tenant() = %d, %d, want 7, 200", id.Node(), id.Tenant())
+		}
+		if ts := id.Timestamp(); ts.Before(before) || ts.After(time.Now()) {
+			t.Fatalf("Timestamp() = %v, want ~%v", ts, before)
+		}
+		if id == prev {
+			t.Fatalf("duplicate ID %d", id)
+		}
+		prev = id
+	}
+	if c := prev.Components(); c.Tenant != 200 || !strings.Contains(c.String(), "tenant:    200\n") {
+		t.Errorf("Components() = %+v, want tenant 200", c)
+	}
+	if id := NewGenerator(7).Generate(); id.Tenant() != 0 {
+		t.Errorf("Tenant() = %d without WithTenant, want 0", id.Tenant())
+	}
+	if years := cfg.MaxTime().Sub(time.UnixMicro(cfg.Epoch)).Hours() / 24 / 365; years > 2 {
+		t.Errorf("MaxTime() is %.0f years after Epoch, want ~1 with 35 time bits", years)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("WithTenant(256) with 8 tenant bits: want panic")
+		}
+	}()
+	NewGenerator(7, WithTenant(256))
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	prevGen := DefaultGenerator
+	defer func() {
+		Configure(DefaultConfig())
+		DefaultGenerator, DefaultFormat, DefaultObfuscator = prevGen, FormatCrockford, nil
+	}()
+
+	t.Setenv(EnvSeqBits, "70")
+	if err := ConfigFromEnv(); err == nil || CurrentConfig() != DefaultConfig() {
+		t.Errorf("ConfigFromEnv with invalid %s = %v, want error and unchanged config", EnvSeqBits, err)
+	}
+	t.Setenv(EnvSeqBits, "10")
+	t.Setenv(EnvFormat, "nope")
+	if err := ConfigFromEnv(); err == nil || CurrentConfig() != DefaultConfig() {
+		t.Errorf("ConfigFromEnv with unknown format = %v, want error and unchanged config", err)
+	}
+
+	t.Setenv(EnvEpoch, "2026-01-01T00:00:00Z")
+	t.Setenv(EnvNodeBits, "10")
+	t.Setenv(EnvNode, "900")
+	t.Setenv(EnvFormat, string(FormatBase58))
+	t.Setenv(EnvObfuscationKey, "12345")
+	if err := ConfigFromEnv(); err != nil {
+		t.Fatal(err)
+	}
+	want := DefaultConfig()
+	want.Epoch = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).UnixMicro()
+	want.NodeBits, want.SeqBits = 10, 10
+	if got := CurrentConfig(); got != want {
+		t.Errorf("CurrentConfig() = %+v, want %+v", got, want)
+	}
+	if node := DefaultGenerator.Generate().Node(); node != 900 {
+		t.Errorf("Node() = %d, want 900", node)
+	}
+	if DefaultFormat != FormatBase58 || DefaultObfuscator == nil {
+		t.Errorf("DefaultFormat = %q, DefaultObfuscator = %v", DefaultFormat, DefaultObfuscator)
+	}
+
+	t.Setenv(EnvNodeBits, "6")
+	if err := ConfigFromEnv(); err == nil {
+		t.Error("ConfigFromEnv with node 900 in 6 node bits: want error")
+	}
+}
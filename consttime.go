@@ -0,0 +1,86 @@
+package usid
+
+import "errors"
+
+// Constant-time decoding for the formats that carry raw ID bits (hex and
+// base64). Every character is examined with arithmetic masks rather than
+// branches or table lookups, and failures are reported once after the whole
+// input has been read, so timing depends only on the input length. This
+// matters when IDs are obfuscated: with early exits, response times could
+// reveal how long a prefix of a guessed string is valid.
+//
+// Obfuscate and Deobfuscate are a single XOR and are constant-time too. The
+// Crockford, Base58, Base62, and decimal decoders are not.
+
+var (
+	errInvalidHex    = errors.New("usid: invalid hex string")
+	errInvalidBase64 = errors.New("usid: invalid base64")
+)
+
+// ctInRange returns all ones if lo <= c <= hi, and zero otherwise.
+func ctInRange(c, lo, hi byte) uint64 {
+	return uint64(^((int64(c) - int64(lo)) | (int64(hi) - int64(c))) >> 63)
+}
+
+// ctHexDigit returns the value of hex digit c and an all-ones mask if c is
+// valid (zero otherwise).
+func ctHexDigit(c byte) (v, ok uint64) {
+	digit := ctInRange(c, '0', '9')
+	letter := ctInRange(c|0x20, 'a', 'f')
+	v = uint64(c-'0')&digit | uint64(c|0x20-'a'+10)&letter
+	return v, digit | letter
+}
+
+// ctBase64Digit returns the value of base64 digit c, using c62 and c63 for
+// the last two symbols, and an all-ones mask if c is valid.
+func ctBase64Digit(c, c62, c63 byte) (v, ok uint64) {
+	upper := ctInRange(c, 'A', 'Z')
+	lower := ctInRange(c, 'a', 'z')
+	digit := ctInRange(c, '0', '9')
+	s62 := ctInRange(c, c62, c62)
+	s63 := ctInRange(c, c63, c63)
+	v = uint64(c-'A')&upper | uint64(c-'a'+26)&lower | uint64(c-'0'+52)&digit | 62&s62 | 63&s63
+	return v, upper | lower | digit | s62 | s63
+}
+
+// ctDecodeHex decodes 1-16 hex digits.
+func ctDecodeHex(s string) (ID, error) {
+	if len(s) == 0 || len(s) > 16 {
+		return Nil, errors.New("usid: hex string must be 1-16 characters")
+	}
+	var n uint64
+	valid := ^uint64(0)
+	for i := 0; i < len(s); i++ {
+		v, ok := ctHexDigit(s[i])
+		n = n<<4 | v
+		valid &= ok
+	}
+	if valid == 0 {
+		return Nil, errInvalidHex
+	}
+	return ID(n), nil
+}
+
+// ctDecodeBase64 decodes the 11 significant digits of a base64-encoded ID.
+// The low two bits of the last digit are padding and ignored, as in
+// encoding/base64.
+func ctDecodeBase64(s string, c62, c63 byte) (ID, error) {
+	if len(s) != 11 {
+		return Nil, errInvalidBase64
+	}
+	var n uint64
+	valid := ^uint64(0)
+	for i := 0; i < len(s); i++ {
+		v, ok := ctBase64Digit(s[i], c62, c63)
+		if i < 10 {
+			n = n<<6 | v
+		} else {
+			n = n<<4 | v>>2
+		}
+		valid &= ok
+	}
+	if valid == 0 {
+		return Nil, errInvalidBase64
+	}
+	return ID(n), nil
+}
@@ -0,0 +1,56 @@
+package usid
+
+import (
+	"encoding/base64"
+	"math/rand/v2"
+	"strconv"
+	"testing"
+)
+
+// TestConstantTimeDecoders checks the constant-time decoders against the
+// standard library on valid IDs and every single-byte corruption of them.
+func TestConstantTimeDecoders(t *testing.T) {
+	std := func(enc *base64.Encoding) func(string) (ID, bool) {
+		return func(s string) (ID, bool) {
+			b, err := enc.DecodeString(s)
+			if err != nil {
+				return Nil, false
+			}
+			id, err := FromBytes(b)
+			return id, err == nil
+		}
+	}
+	hex := func(s string) (ID, bool) {
+		n, err := strconv.ParseUint(s, 16, 64)
+		return ID(n), err == nil && len(s) <= 16
+	}
+	tests := []struct {
+		f     Format
+		parse func(string) (ID, error)
+		ref   func(string) (ID, bool)
+	}{
+		{FormatBase64, ParseBase64, std(base64.StdEncoding)},
+		{FormatBase64URL, ParseBase64URL, std(base64.RawURLEncoding)},
+		{FormatHash, ParseHash, hex},
+	}
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, tt := range tests {
+		for range 200 {
+			s := ID(r.Int64()).Format(tt.f)
+			for i := range len(s) {
+				for c := range 256 {
+					if c == '\r' || c == '\n' {
+						continue // encoding/base64 skips newlines
+					}
+					b := []byte(s)
+					b[i] = byte(c)
+					want, ok := tt.ref(string(b))
+					got, err := tt.parse(string(b))
+					if (err == nil) != ok || (ok && got != want) {
+						t.Fatalf("%s(%q) = %d, %v; want %d, ok=%v", tt.f, b, got, err, want, ok)
+					}
+				}
+			}
+		}
+	}
+}
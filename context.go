@@ -0,0 +1,61 @@
+package usid
+
+import "context"
+
+type generatorKey struct{}
+
+// WithGenerator returns a copy of ctx carrying g, so code that receives the
+// context (HTTP handlers, jobs, tests) generates IDs with g instead of
+// DefaultGenerator.
+func WithGenerator(ctx context.Context, g IDGenerator) context.Context {
+	return context.WithValue(ctx, generatorKey{}, g)
+}
+
+// FromContext returns the generator stored in ctx by WithGenerator, or
+// DefaultGenerator if there is none.
+func FromContext(ctx context.Context) IDGenerator {
+	if g, ok := ctx.Value(generatorKey{}).(IDGenerator); ok && g != nil {
+		return g
+	}
+	return DefaultGenerator
+}
+
+// NewContext generates an ID using the generator in ctx (see FromContext).
+func NewContext(ctx context.Context) ID {
+	return FromContext(ctx).Generate()
+}
+
+type formatKey struct{}
+
+// WithFormat returns a copy of ctx carrying f, so handlers that receive the
+// context encode IDs in f instead of DefaultFormat; one service can then
+// emit Base58 to public APIs and decimal to internal endpoints. Encode with
+// FormatContext or MarshalJSONContext, or wrap IDs in a FormattedID.
+func WithFormat(ctx context.Context, f Format) context.Context {
+	return context.WithValue(ctx, formatKey{}, f)
+}
+
+// FormatFromContext returns the format stored in ctx by WithFormat, or
+// DefaultFormat if there is none.
+func FormatFromContext(ctx context.Context) Format {
+	if f, ok := ctx.Value(formatKey{}).(Format); ok && f != "" {
+		return f
+	}
+	return DefaultFormat
+}
+
+// FormatContext returns the ID as a string in the format of ctx (see
+// FormatFromContext).
+func (id ID) FormatContext(ctx context.Context) string {
+	return id.Format(FormatFromContext(ctx))
+}
+
+// MarshalJSONContext is MarshalJSON in the format of ctx. Without a format
+// in ctx it is MarshalJSON, numbers included when JSONNumeric is set.
+func (id ID) MarshalJSONContext(ctx context.Context) ([]byte, error) {
+	f, ok := ctx.Value(formatKey{}).(Format)
+	if !ok || f == "" {
+		return id.MarshalJSON()
+	}
+	return id.In(f).MarshalJSON()
+}
@@ -0,0 +1,72 @@
+package usid
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestContextGenerator(t *testing.T) {
+	ctx := context.Background()
+	if FromContext(ctx) != DefaultGenerator {
+		t.Error("FromContext without a generator should return DefaultGenerator")
+	}
+
+	gen := NewGenerator(7)
+	ctx = WithGenerator(ctx, gen)
+	if FromContext(ctx) != gen {
+		t.Error("FromContext did not return the stored generator")
+	}
+	if id := NewContext(ctx); id.Node() != 7 {
+		t.Errorf("NewContext().Node() = %d, want 7", id.Node())
+	}
+}
+
+func TestContextGeneratorFunc(t *testing.T) {
+	var next ID
+	fake := GeneratorFunc(func() ID { next++; return next })
+	ctx := WithGenerator(context.Background(), fake)
+	if a, b := NewContext(ctx), NewContext(ctx); a != 1 || b != 2 {
+		t.Errorf("NewContext with GeneratorFunc = %d, %d; want 1, 2", a, b)
+	}
+}
+
+func TestContextFormat(t *testing.T) {
+	id := FromInt64(10151254716672)
+	ctx := context.Background()
+	if f := FormatFromContext(ctx); f != DefaultFormat {
+		t.Errorf("FormatFromContext without a format = %q, want DefaultFormat", f)
+	}
+	if b, _ := id.MarshalJSONContext(ctx); string(b) != `"`+id.String()+`"` {
+		t.Errorf("MarshalJSONContext without a format = %s, want %q", b, id.String())
+	}
+
+	ctx = WithFormat(ctx, FormatDecimal)
+	if got := id.FormatContext(ctx); got != "10151254716672" {
+		t.Errorf("FormatContext = %q, want decimal", got)
+	}
+	if b, _ := id.MarshalJSONContext(ctx); string(b) != `"10151254716672"` {
+		t.Errorf("MarshalJSONContext = %s, want decimal string", b)
+	}
+
+	b, err := json.Marshal(struct {
+		ID FormattedID `json:"id"`
+	}{id.InContext(WithFormat(ctx, FormatBase58))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"id":"` + id.Format(FormatBase58) + `"}`; string(b) != want {
+		t.Errorf("json.Marshal(FormattedID) = %s, want %s", b, want)
+	}
+
+	for _, in := range []string{`"` + id.Format(FormatBase58) + `"`, `10151254716672`} {
+		got := FormattedID{Format: FormatBase58}
+		if err := json.Unmarshal([]byte(in), &got); err != nil || got.ID != id {
+			t.Errorf("Unmarshal(%s) = %v, %v; want %v", in, got.ID, err, id)
+		}
+	}
+	got := FormattedID{Format: FormatDecimal}
+	if err := json.Unmarshal([]byte(`"not-a-number"`), &got); err == nil {
+		t.Error("Unmarshal of invalid decimal: want err != nil")
+	}
+}
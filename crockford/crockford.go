@@ -3,7 +3,10 @@
 // Decoding is case-insensitive.
 package crockford
 
-import "errors"
+import (
+	"errors"
+	"math"
+)
 
 var encode = [32]byte{
 	'0', '1', '2', '3', '4', '5', '6', '7', '8', '9',
@@ -14,12 +17,17 @@ var encode = [32]byte{
 
 var decode [128]int64
 
+// strictDecode maps only the characters Encode emits.
+var strictDecode [128]int64
+
 func init() {
 	for i := range decode {
 		decode[i] = -1
+		strictDecode[i] = -1
 	}
 	for i, c := range encode {
 		decode[c] = int64(i)
+		strictDecode[c] = int64(i)
 		// Case-insensitive: map uppercase to same value
 		if c >= 'a' && c <= 'z' {
 			decode[c-32] = int64(i)
@@ -34,29 +42,93 @@ func init() {
 	decode['o'] = 0
 }
 
-// ErrInvalid is returned when decoding a string with invalid characters.
+// MaxLen is the maximum number of digits in a Crockford-encoded int64,
+// not counting hyphen separators.
+const MaxLen = 13
+
+// ErrInvalid is returned when decoding a string with invalid characters
+// or more than MaxLen digits.
 var ErrInvalid = errors.New("usid: invalid crockford character")
 
+// ErrNotCanonical is returned by DecodeStrict for strings Decode would accept
+// but Encode and EncodeFixed never produce.
+var ErrNotCanonical = errors.New("usid: crockford string is not canonical")
+
+// ErrOverflow is returned when a Crockford-encoded string exceeds the int64 range.
+var ErrOverflow = errors.New("usid: crockford value overflows int64")
+
 // Encode returns the Crockford Base32 encoding of the given int64.
+// Negative values return an empty string.
 func Encode(id int64) string {
 	if id == 0 {
 		return "0"
 	}
-	var buf [13]byte // max 13 chars for int64
-	i := 12
+	var buf [MaxLen]byte
+	i := MaxLen
 	for id > 0 {
+		i--
 		buf[i] = encode[id&0x1f]
 		id >>= 5
+	}
+	return string(buf[i:])
+}
+
+// AppendEncode appends the Crockford Base32 encoding of the given int64 to
+// dst and returns the extended slice. Negative values append nothing, as
+// with Encode.
+func AppendEncode(dst []byte, id int64) []byte {
+	if id < 0 {
+		return dst
+	}
+	if id == 0 {
+		return append(dst, '0')
+	}
+	var buf [MaxLen]byte
+	i := MaxLen
+	for id > 0 {
 		i--
+		buf[i] = encode[id&0x1f]
+		id >>= 5
+	}
+	return append(dst, buf[i:]...)
+}
+
+// EncodeFixed returns the Crockford Base32 encoding of the given int64 left-padded
+// with '0' to MaxLen characters, so encoded strings sort lexicographically in the
+// same order as their values. Negative values return an empty string, as with Encode.
+func EncodeFixed(id int64) string {
+	if id < 0 {
+		return ""
+	}
+	var buf [MaxLen]byte
+	for i := MaxLen - 1; i >= 0; i-- {
+		buf[i] = encode[id&0x1f]
+		id >>= 5
 	}
-	return string(buf[i+1:])
+	return string(buf[:])
+}
+
+// AppendEncodeFixed appends the EncodeFixed encoding of the given int64 to
+// dst and returns the extended slice. Negative values append nothing.
+func AppendEncodeFixed(dst []byte, id int64) []byte {
+	if id < 0 {
+		return dst
+	}
+	var buf [MaxLen]byte
+	for i := MaxLen - 1; i >= 0; i-- {
+		buf[i] = encode[id&0x1f]
+		id >>= 5
+	}
+	return append(dst, buf[:]...)
 }
 
 // Decode parses a Crockford Base32-encoded string and returns the int64 value.
 // Decoding is case-insensitive. I and L are treated as 1, O is treated as 0.
-// Returns ErrInvalid if the string contains invalid characters.
+// Returns ErrInvalid if the string contains invalid characters or more than
+// MaxLen digits, and ErrOverflow if the value does not fit in an int64.
 func Decode(s string) (int64, error) {
 	var id int64
+	var n int
 	for i := 0; i < len(s); i++ {
 		c := s[i]
 		if c == '-' {
@@ -69,7 +141,84 @@ func Decode(s string) (int64, error) {
 		if v < 0 {
 			return 0, ErrInvalid
 		}
+		if n++; n > MaxLen {
+			return 0, ErrInvalid
+		}
+		if id > math.MaxInt64>>5 {
+			return 0, ErrOverflow
+		}
+		id = (id << 5) | v
+	}
+	return id, nil
+}
+
+// Encoding is a Crockford Base32 codec, satisfying usid.Codec.
+type Encoding struct {
+	fixed bool
+}
+
+var (
+	// StdEncoding encodes like Encode.
+	StdEncoding = Encoding{}
+
+	// FixedEncoding encodes like EncodeFixed.
+	FixedEncoding = Encoding{fixed: true}
+)
+
+// AppendEncode appends the encoding of id to dst.
+func (e Encoding) AppendEncode(dst []byte, id int64) []byte {
+	if e.fixed {
+		return AppendEncodeFixed(dst, id)
+	}
+	return AppendEncode(dst, id)
+}
+
+// Encode returns the encoding of id as a string.
+func (e Encoding) Encode(id int64) string {
+	if e.fixed {
+		return EncodeFixed(id)
+	}
+	return Encode(id)
+}
+
+// Decode decodes s like Decode. Both encodings accept both forms.
+func (e Encoding) Decode(s string) (int64, error) {
+	return Decode(s)
+}
+
+// DecodeStrict parses s only in the exact form Encode or EncodeFixed
+// produces, for systems that need one representation per value: lowercase,
+// without hyphens or the I, L, and O substitutions, and without leading
+// zeros unless s is MaxLen characters long. It returns ErrNotCanonical for
+// other strings Decode accepts, ErrInvalid for strings Decode rejects, and
+// ErrOverflow if the value does not fit in an int64.
+func DecodeStrict(s string) (int64, error) {
+	if len(s) == 0 {
+		return 0, ErrInvalid
+	}
+	var id int64
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 128 {
+			return 0, ErrInvalid
+		}
+		v := strictDecode[c]
+		if v < 0 {
+			if c == '-' || decode[c] >= 0 {
+				return 0, ErrNotCanonical
+			}
+			return 0, ErrInvalid
+		}
+		if i >= MaxLen {
+			return 0, ErrInvalid
+		}
+		if id > math.MaxInt64>>5 {
+			return 0, ErrOverflow
+		}
 		id = (id << 5) | v
 	}
+	if s[0] == '0' && len(s) > 1 && len(s) < MaxLen {
+		return 0, ErrNotCanonical
+	}
 	return id, nil
 }
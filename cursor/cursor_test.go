@@ -0,0 +1,57 @@
+package cursor_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/cursor"
+)
+
+func TestCursorRoundtrip(t *testing.T) {
+	for _, key := range [][]byte{nil, []byte("secret")} {
+		codec := cursor.New(key)
+		want := cursor.Cursor{ID: usid.New(), Direction: cursor.Backward, Filters: cursor.HashFilters("a", "b")}
+		s := codec.Encode(want)
+		got, err := codec.DecodeFor(s, want.Filters)
+		if err != nil {
+			t.Fatalf("DecodeFor(key=%q): %v", key, err)
+		}
+		if got != want {
+			t.Errorf("DecodeFor(key=%q) = %+v, want %+v", key, got, want)
+		}
+		if _, err := codec.DecodeFor(s, cursor.HashFilters("a")); !errors.Is(err, cursor.ErrFilterMismatch) {
+			t.Errorf("DecodeFor(other filters) err = %v, want ErrFilterMismatch", err)
+		}
+	}
+}
+
+func TestCursorSignature(t *testing.T) {
+	signed := cursor.New([]byte("secret"))
+	s := signed.Encode(cursor.Cursor{ID: 42})
+
+	tampered := []byte(s)
+	tampered[5] ^= 1
+	if _, err := signed.Decode(string(tampered)); err == nil {
+		t.Error("Decode(tampered): want err != nil")
+	}
+	if _, err := cursor.New([]byte("other")).Decode(s); !errors.Is(err, cursor.ErrSignature) {
+		t.Errorf("Decode(wrong key) err = %v, want ErrSignature", err)
+	}
+	unsigned := cursor.New(nil).Encode(cursor.Cursor{ID: 42})
+	if _, err := signed.Decode(unsigned); !errors.Is(err, cursor.ErrInvalid) {
+		t.Errorf("Decode(unsigned) err = %v, want ErrInvalid", err)
+	}
+	if _, err := signed.Decode("!!"); !errors.Is(err, cursor.ErrInvalid) {
+		t.Errorf("Decode(garbage) err = %v, want ErrInvalid", err)
+	}
+}
+
+func TestDirection(t *testing.T) {
+	if cursor.Forward.Op() != ">" || cursor.Forward.Order() != "ASC" {
+		t.Error("Forward should page with > and ASC")
+	}
+	if cursor.Backward.Op() != "<" || cursor.Backward.Order() != "DESC" {
+		t.Error("Backward should page with < and DESC")
+	}
+}
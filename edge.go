@@ -0,0 +1,131 @@
+package usid
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// EdgeConfig returns a layout for fleets of frequently offline devices that
+// mint IDs without coordination: millisecond timestamps in 41 bits (about 69
+// years from Epoch), 65536 nodes so every device can own one, and 64 IDs per
+// millisecond per device. Pair it with WithMonotonic and SaveState/LoadState
+// so restarts after a clock rollback continue where the device left off.
+func EdgeConfig() Config {
+	cfg := DefaultConfig()
+	cfg.Precision = time.Millisecond
+	cfg.TimeBits = 41
+	cfg.NodeBits = 16
+	return cfg
+}
+
+const stateVersion = 2
+
+var errState = errors.New("usid: invalid generator state")
+
+// State returns a compact checkpoint of the generator: the last-issued tick
+// and sequence counter across every node it has generated for, tagged with
+// the layout, epoch, precision, and era they belong to. Pass it to
+// RestoreState after a restart. Safe for concurrent use.
+func (g *Generator) State() []byte {
+	last := g.lastState()
+	b := []byte{stateVersion, g.nodeShift, g.timeShift}
+	b = binary.AppendVarint(b, Epoch)
+	b = binary.AppendUvarint(b, uint64(precisionMicros(Precision)))
+	b = binary.AppendUvarint(b, uint64(g.era))
+	b = binary.AppendUvarint(b, last>>g.nodeShift)
+	return binary.AppendUvarint(b, last&uint64(g.nodeMask))
+}
+
+// RestoreState moves the generator past the position recorded by State, so
+// every ID it issues sorts after those issued before the checkpoint, even if
+// the clock has since been set back. The generator resumes in the tick after
+// the checkpoint. Returns an error if state is malformed or was taken under a
+// different layout, Epoch, Precision, or Era, whose ticks would not line up.
+//
+// Use a generator created with WithMonotonic: otherwise it blocks until the
+// clock reaches the restored position.
+func (g *Generator) RestoreState(state []byte) error {
+	return g.unmarshalState(state, 0)
+}
+
+// unmarshalState moves the generator past the tick recorded by State plus
+// skip ticks.
+func (g *Generator) unmarshalState(b []byte, skip int64) error {
+	if len(b) < 3 || b[0] != stateVersion {
+		return errState
+	}
+	if b[1] != g.nodeShift || b[2] != g.timeShift {
+		return fmt.Errorf("usid: generator state is for a different layout (%d sequence and tenant bits, %d node bits)",
+			b[1], b[2]-b[1])
+	}
+	epoch, n := binary.Varint(b[3:])
+	if n <= 0 {
+		return errState
+	}
+	b = b[3+n:]
+	var fields [4]uint64 // precision, era prefix, tick, counter
+	for i := range fields {
+		v, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errState
+		}
+		fields[i], b = v, b[n:]
+	}
+	if len(b) != 0 {
+		return errState
+	}
+	precision, era, tick := int64(fields[0]), ID(fields[1]), int64(fields[2])
+	switch {
+	case epoch != Epoch:
+		return fmt.Errorf("usid: generator state is for epoch %d, not %d", epoch, Epoch)
+	case precision != precisionMicros(Precision):
+		return fmt.Errorf("usid: generator state is for %v ticks, not %v",
+			time.Duration(precision)*time.Microsecond, time.Duration(precisionMicros(Precision))*time.Microsecond)
+	case era != g.era:
+		return fmt.Errorf("usid: generator state is for a different era (prefix %#x, not %#x)", uint64(era), uint64(g.era))
+	}
+	g.advancePast(tick + skip)
+	return nil
+}
+
+// SaveState atomically writes State to path, for LoadState after a restart.
+// Call it periodically and on shutdown.
+func (g *Generator) SaveState(path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(g.State()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadState restores the state saved at path like RestoreState. IDs issued
+// after the last SaveState are covered by interval, the longest time between
+// saves: the generator also skips that much time past the saved position. A
+// missing file is not an error, so devices can call LoadState unconditionally
+// at boot.
+func (g *Generator) LoadState(path string, interval time.Duration) error {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return g.unmarshalState(b, int64(interval/time.Microsecond)/precisionMicros(Precision))
+}
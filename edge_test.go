@@ -0,0 +1,131 @@
+package usid
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEdgeConfig(t *testing.T) {
+	cfg := EdgeConfig()
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MaxNode() != 65535 {
+		t.Errorf("MaxNode() = %d, want 65535", cfg.MaxNode())
+	}
+	if years := cfg.MaxTime().Sub(time.UnixMicro(cfg.Epoch)).Hours() / 24 / 365; years < 69 {
+		t.Errorf("lifetime %.0f years, want >= 69", years)
+	}
+}
+
+func TestSaveLoadState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usid.state")
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	g := NewGenerator(1, WithMonotonic(), WithClock(clock))
+	if err := g.LoadState(path, time.Minute); err != nil {
+		t.Fatalf("LoadState(missing): %v", err)
+	}
+	g.Generate()
+	if err := g.SaveState(path); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(30 * time.Second)
+	last := g.Generate() // issued after the save
+
+	// Reboot with the clock an hour behind.
+	now = now.Add(-time.Hour)
+	g = NewGenerator(1, WithMonotonic(), WithClock(clock))
+	if err := g.LoadState(path, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if id := g.Generate(); id <= last {
+		t.Errorf("after restore Generate() = %v, want > %v", id, last)
+	}
+
+	b, _ := os.ReadFile(path)
+	b[1]++ // sequence and tenant bits
+	os.WriteFile(path, b, 0o600)
+	if err := g.LoadState(path, 0); err == nil {
+		t.Error("LoadState accepted state from a different layout")
+	}
+	os.WriteFile(path, []byte{stateVersion, 6, 12, 0x80}, 0o600)
+	if err := g.LoadState(path, 0); err == nil {
+		t.Error("LoadState accepted truncated state")
+	}
+}
+
+func TestRestoreState(t *testing.T) {
+	now := time.Now()
+	clock := func() time.Time { return now }
+	g := NewGenerator(1, WithMonotonic(), WithClock(clock))
+	var last ID
+	for range 50 {
+		last = g.Generate()
+	}
+	state := g.State()
+	if len(state) > 24 {
+		t.Errorf("len(State()) = %d, want a compact encoding", len(state))
+	}
+
+	now = now.Add(-10 * time.Second)
+	restored := NewGenerator(1, WithMonotonic(), WithClock(clock))
+	if err := restored.RestoreState(state); err != nil {
+		t.Fatal(err)
+	}
+	if id := restored.Generate(); id <= last || id.Timestamp().Before(last.Timestamp()) {
+		t.Errorf("after RestoreState Generate() = %v, want > %v", id, last)
+	}
+	for _, bad := range [][]byte{nil, {0, 6, 12, 1, 1}, state[:len(state)-1], append(state, 0)} {
+		if err := restored.RestoreState(bad); err == nil {
+			t.Errorf("RestoreState(%x): want err != nil", bad)
+		}
+	}
+}
+
+func TestRestoreStateNodes(t *testing.T) {
+	now := time.Now()
+	clock := func() time.Time { return now }
+	g := NewGenerator(1, WithMonotonic(), WithClock(clock))
+	g.Generate()
+	now = now.Add(time.Second)
+	last, err := g.GenerateForNode(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := g.State()
+
+	now = now.Add(-10 * time.Second)
+	restored := NewGenerator(1, WithMonotonic(), WithClock(clock))
+	if err := restored.RestoreState(state); err != nil {
+		t.Fatal(err)
+	}
+	if id := restored.Generate(); id <= last {
+		t.Errorf("after RestoreState Generate() = %v, want > %v", id, last)
+	}
+	if id, _ := restored.GenerateForNode(2); id <= last {
+		t.Errorf("after RestoreState GenerateForNode(2) = %v, want > %v", id, last)
+	}
+}
+
+func TestRestoreStateConfig(t *testing.T) {
+	defer Configure(DefaultConfig())
+	state := NewGenerator(1).State()
+	for name, change := range map[string]func(*Config){
+		"Epoch":     func(c *Config) { c.Epoch -= 1e6 },
+		"Precision": func(c *Config) { c.Precision = time.Millisecond },
+		"Era":       func(c *Config) { c.EraBits, c.Era = 1, 1 },
+	} {
+		cfg := DefaultConfig()
+		change(&cfg)
+		if err := Configure(cfg); err != nil {
+			t.Fatal(err)
+		}
+		if err := NewGenerator(1).RestoreState(state); err == nil {
+			t.Errorf("RestoreState accepted state taken under a different %s", name)
+		}
+	}
+}
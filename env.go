@@ -0,0 +1,103 @@
+package usid
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Environment variables read by ConfigFromEnv.
+const (
+	EnvEpoch          = "USID_EPOCH"           // Microseconds since the Unix epoch, or an RFC 3339 time
+	EnvNodeBits       = "USID_NODE_BITS"       // Bits allocated for node ID
+	EnvSeqBits        = "USID_SEQ_BITS"        // Bits allocated for sequence number
+	EnvNode           = "USID_NODE"            // Node ID of DefaultGenerator
+	EnvFormat         = "USID_FORMAT"          // DefaultFormat; a built-in or registered format
+	EnvObfuscationKey = "USID_OBFUSCATION_KEY" // Decimal int64 key for SetObfuscator
+)
+
+// ConfigFromEnv configures the package from the USID_* environment variables
+// (see EnvEpoch and the other Env constants), for deployments that keep
+// configuration out of code. Unset variables leave the current setting
+// alone. Every variable is validated before any is applied, so on error the
+// package is unchanged. Call once at startup, after registering custom
+// formats.
+func ConfigFromEnv() error {
+	cfg := CurrentConfig()
+	if s, ok := os.LookupEnv(EnvEpoch); ok {
+		epoch, err := parseEnvEpoch(s)
+		if err != nil {
+			return err
+		}
+		cfg.Epoch = epoch
+	}
+	for _, v := range []struct {
+		name string
+		bits *uint8
+	}{{EnvNodeBits, &cfg.NodeBits}, {EnvSeqBits, &cfg.SeqBits}} {
+		if s, ok := os.LookupEnv(v.name); ok {
+			n, err := strconv.ParseUint(s, 10, 8)
+			if err != nil || n > 62 {
+				return fmt.Errorf("usid: %s: invalid bit count %q", v.name, s)
+			}
+			*v.bits = uint8(n)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("usid: environment: %w", err)
+	}
+
+	node := int64(1)
+	var opts []Option
+	if DefaultGenerator != nil {
+		node, opts = DefaultGenerator.node, DefaultGenerator.opts
+	}
+	if s, ok := os.LookupEnv(EnvNode); ok {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("usid: %s: invalid node ID %q", EnvNode, s)
+		}
+		node = n
+	}
+	if maxNode := cfg.MaxNode(); node < 0 || node > maxNode {
+		return fmt.Errorf("usid: node ID %d out of range [0, %d]", node, maxNode)
+	}
+
+	format := DefaultFormat
+	if s, ok := os.LookupEnv(EnvFormat); ok {
+		format = Format(s)
+		if _, ok := lookupFormat(format); !ok {
+			return fmt.Errorf("usid: %s: unknown format %q", EnvFormat, s)
+		}
+	}
+
+	s, setKey := os.LookupEnv(EnvObfuscationKey)
+	var key int64
+	if setKey {
+		var err error
+		if key, err = strconv.ParseInt(s, 10, 64); err != nil || key == 0 {
+			return fmt.Errorf("usid: %s: obfuscation key must be a nonzero decimal int64", EnvObfuscationKey)
+		}
+	}
+
+	if err := configure(cfg, node, opts); err != nil {
+		return err
+	}
+	DefaultFormat = format
+	if setKey {
+		SetObfuscator(key)
+	}
+	return nil
+}
+
+func parseEnvEpoch(s string) (int64, error) {
+	if epoch, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return epoch, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, fmt.Errorf("usid: %s: want microseconds or an RFC 3339 time, got %q", EnvEpoch, s)
+	}
+	return t.UnixMicro(), nil
+}
@@ -0,0 +1,99 @@
+package usid
+
+import (
+	"fmt"
+	"maps"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	erasMu sync.Mutex
+	eras   atomic.Pointer[map[int64]int64] // era -> epoch, copied on write
+)
+
+// RegisterEra records the epoch, in microseconds, of IDs generated in era,
+// so Timestamp decodes them correctly after Era has moved on. Configure
+// registers the epoch of the era it selects; register the others, such as
+// those of the pre-rotation epoch or of merged systems, at startup, before
+// or after Configure. Configure returns an error if a registered era does
+// not fit in its EraBits. Panics if era is negative, or if eras are already
+// configured and era does not fit in EraBits.
+func RegisterEra(era, epoch int64) {
+	if era < 0 || EraBits > 0 && era >= 1<<EraBits {
+		panic(fmt.Sprintf("usid: era %d does not fit in %d era bits", era, EraBits))
+	}
+	erasMu.Lock()
+	defer erasMu.Unlock()
+	m := make(map[int64]int64)
+	if old := eras.Load(); old != nil {
+		maps.Copy(m, *old)
+	}
+	m[era] = epoch
+	eras.Store(&m)
+}
+
+// EraEpoch returns the epoch registered for era, if any.
+func EraEpoch(era int64) (epoch int64, ok bool) {
+	if m := eras.Load(); m != nil {
+		epoch, ok = (*m)[era]
+	}
+	return epoch, ok
+}
+
+// checkEras returns an error if an era registered with RegisterEra does not
+// fit in eraBits.
+func checkEras(eraBits uint8) error {
+	if eraBits == 0 {
+		return nil
+	}
+	if m := eras.Load(); m != nil {
+		for era := range *m {
+			if era >= 1<<eraBits {
+				return fmt.Errorf("usid: registered era %d does not fit in %d era bits", era, eraBits)
+			}
+		}
+	}
+	return nil
+}
+
+// Era extracts the era component of the ID: 0 unless EraBits is set.
+func (id ID) Era() int64 {
+	if EraBits == 0 {
+		return 0
+	}
+	return int64(id) >> (63 - EraBits)
+}
+
+// epochOf returns the epoch of id's era. IDs of the current era, and of eras
+// never registered, use Epoch.
+func epochOf(id ID) int64 {
+	if EraBits == 0 {
+		return Epoch
+	}
+	era := id.Era()
+	if era == Era {
+		return Epoch
+	}
+	if epoch, ok := EraEpoch(era); ok {
+		return epoch
+	}
+	return Epoch
+}
+
+// timeFieldBits returns the number of bits the timestamp may occupy in the
+// current layout.
+func timeFieldBits() uint8 {
+	if TimeBits > 0 {
+		return TimeBits
+	}
+	return 63 - EraBits - timeShift()
+}
+
+// eraPrefix returns the era bits of IDs generated in the current era.
+func eraPrefix() ID {
+	if EraBits == 0 {
+		return 0
+	}
+	return ID(Era << (63 - EraBits))
+}
@@ -0,0 +1,116 @@
+package usid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEras(t *testing.T) {
+	defer Configure(DefaultConfig())
+
+	old := PrecisionMilli()
+	old.EraBits = 2
+	if err := Configure(old); err != nil {
+		t.Fatal(err)
+	}
+	oldTime := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	a := NewGenerator(1, WithClock(func() time.Time { return oldTime })).Generate()
+
+	// Rotate to a new epoch; IDs of era 0 must keep their timestamps.
+	rotated := old
+	rotated.Era = 1
+	rotated.Epoch = time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC).UnixMicro()
+	if err := Configure(rotated); err != nil {
+		t.Fatal(err)
+	}
+	newTime := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	b := NewGenerator(1, WithClock(func() time.Time { return newTime })).Generate()
+
+	if a.Era() != 0 || b.Era() != 1 {
+		t.Errorf("Era() = %d, %d, want 0, 1", a.Era(), b.Era())
+	}
+	if got := a.Timestamp(); !got.Equal(oldTime) {
+		t.Errorf("era 0 Timestamp() = %v, want %v", got, oldTime)
+	}
+	if got := b.Timestamp(); !got.Equal(newTime) {
+		t.Errorf("era 1 Timestamp() = %v, want %v", got, newTime)
+	}
+	if b <= a {
+		t.Errorf("era 1 ID %d does not sort after era 0 ID %d", b, a)
+	}
+	if b.Node() != 1 {
+		t.Errorf("Node() = %d, want 1", b.Node())
+	}
+	if min := MinIDForTime(newTime); min.Era() != 1 || min > b || MaxIDForTime(newTime) < b {
+		t.Errorf("MinIDForTime/MaxIDForTime(%v) do not bracket %d", newTime, b)
+	}
+	day := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	if got := a.Truncate(24 * time.Hour); got.Era() != 0 || !got.Timestamp().Equal(day) {
+		t.Errorf("era 0 Truncate(24h) = era %d at %v, want era 0 at %v", got.Era(), got.Timestamp(), day)
+	}
+	if got := b.Truncate(time.Hour); got.Era() != 1 || !got.Timestamp().Equal(newTime) || got > b {
+		t.Errorf("era 1 Truncate(1h) = era %d at %v, want era 1 at %v", got.Era(), got.Timestamp(), newTime)
+	}
+	if epoch, ok := EraEpoch(0); !ok || epoch != old.Epoch {
+		t.Errorf("EraEpoch(0) = %d, %v, want %d, true", epoch, ok, old.Epoch)
+	}
+
+	if got := CurrentConfig(); got != rotated {
+		t.Errorf("CurrentConfig() = %+v, want %+v", got, rotated)
+	}
+	if years := rotated.MaxTime().Sub(time.UnixMicro(rotated.Epoch)).Hours() / 24 / 365; years < 60 || years > 80 {
+		t.Errorf("MaxTime() is %.0f years after Epoch, want ~69", years)
+	}
+
+	for _, cfg := range []Config{
+		{EraBits: 2, Era: 4, NodeBits: 6, SeqBits: 6},
+		{EraBits: 2, Era: -1, NodeBits: 6, SeqBits: 6},
+		{EraBits: 4, TimeBits: 50, NodeBits: 6, SeqBits: 6},
+	} {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Validate(%+v): want err != nil", cfg)
+		}
+	}
+}
+
+func TestEraObserve(t *testing.T) {
+	defer Configure(DefaultConfig())
+	cfg := DefaultConfig()
+	cfg.EraBits = 1
+	cfg.Era = 1
+	if err := Configure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	a := NewGenerator(1, WithClock(func() time.Time { return now })).Generate()
+	g := NewGenerator(2, WithHLC(), WithClock(func() time.Time { return now.Add(-time.Second) }))
+	g.Observe(a)
+	if b := g.Generate(); b <= a || b.Timestamp().After(now.Add(time.Millisecond)) {
+		t.Errorf("after Observe(%d): Generate() = %d at %v, want just after %v", a, b, b.Timestamp(), now)
+	}
+}
+
+func TestRegisterEraBeforeConfigure(t *testing.T) {
+	defer Configure(DefaultConfig())
+	defer eras.Store(eras.Load())
+
+	// As from an init function, before EraBits is set
+	cfg := PrecisionMilli()
+	RegisterEra(2, cfg.Epoch-1_000_000)
+	cfg.EraBits = 1
+	if err := Configure(cfg); err == nil {
+		t.Error("Configure with a registered era outside EraBits: want err != nil")
+	}
+	cfg.EraBits, cfg.Era = 2, 1
+	if err := Configure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if epoch, ok := EraEpoch(2); !ok || epoch != cfg.Epoch-1_000_000 {
+		t.Errorf("EraEpoch(2) = %d, %v, want the registered epoch", epoch, ok)
+	}
+
+	// Just before Epoch, the tick rounds to zero but is still out of range
+	if id := MinIDForTime(time.UnixMicro(cfg.Epoch - 1)); id != Nil {
+		t.Errorf("MinIDForTime(Epoch - 1µs) = %d, want Nil", id)
+	}
+}
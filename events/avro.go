@@ -0,0 +1,62 @@
+package events
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+// AvroField returns an Avro record field named name holding an ID. Nullable
+// fields, for NullID, are a union with null that defaults to null.
+func AvroField(name string, nullable bool) string {
+	if nullable {
+		return `{"name":` + strconv.Quote(name) + `,"type":["null",` + AvroSchema + `],"default":null}`
+	}
+	return `{"name":` + strconv.Quote(name) + `,"type":` + AvroSchema + `}`
+}
+
+// ToAvro returns id in goavro's native form for an AvroSchema field.
+func ToAvro(id usid.ID) any {
+	return id.Int64()
+}
+
+// FromAvro returns the ID in a native value decoded by goavro from an
+// AvroSchema field.
+func FromAvro(native any) (usid.ID, error) {
+	switch v := native.(type) {
+	case int64:
+		return usid.ID(v), nil
+	case int32:
+		return usid.ID(v), nil
+	case int:
+		return usid.ID(v), nil
+	}
+	return usid.Nil, fmt.Errorf("usid: cannot decode Avro %T as ID", native)
+}
+
+// NullToAvro returns n in goavro's native form for a nullable AvroField:
+// nil, or a union map keyed by the "long" branch.
+func NullToAvro(n usid.NullID) any {
+	if !n.Valid {
+		return nil
+	}
+	return map[string]any{"long": n.ID.Int64()}
+}
+
+// NullFromAvro returns the NullID in a native value decoded by goavro from a
+// nullable AvroField.
+func NullFromAvro(native any) (usid.NullID, error) {
+	if native == nil {
+		return usid.NullID{}, nil
+	}
+	union, ok := native.(map[string]any)
+	if !ok || len(union) != 1 {
+		return usid.NullID{}, fmt.Errorf("usid: cannot decode Avro %T as NullID", native)
+	}
+	for _, v := range union {
+		id, err := FromAvro(v)
+		return usid.NullID{ID: id, Valid: err == nil}, err
+	}
+	return usid.NullID{}, nil
+}
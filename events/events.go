@@ -0,0 +1,70 @@
+// Package events provides helpers for carrying IDs through event pipelines
+// such as Kafka and into data-lake exports: partition-stable message keys,
+// header values, schema fragments for schema registries, and Avro and Parquet
+// adapters.
+package events
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+// AvroSchema is the Avro type for an ID: a long annotated with the "usid"
+// logical type. Readers that don't know the logical type see a plain long.
+const AvroSchema = `{"type":"long","logicalType":"usid"}`
+
+// ErrKeyLength is returned by ParseKey for keys that are not 8 bytes.
+var ErrKeyLength = errors.New("usid: event key must be 8 bytes")
+
+// Key returns the 8-byte big-endian message key for id. Key bytes do not
+// depend on DefaultFormat or obfuscation, so every event for an ID hashes to
+// the same partition across producers.
+func Key(id usid.ID) []byte {
+	return id.PartitionKey()
+}
+
+// ParseKey returns the ID encoded by Key.
+func ParseKey(b []byte) (usid.ID, error) {
+	if len(b) != 8 {
+		return usid.Nil, ErrKeyLength
+	}
+	return usid.FromBytes(b)
+}
+
+// HeaderValue returns id as a message header value: its string form in
+// DefaultFormat, so headers stay readable in tooling.
+func HeaderValue(id usid.ID) []byte {
+	return []byte(id.String())
+}
+
+// ParseHeader returns the ID in a header value written by HeaderValue.
+func ParseHeader(b []byte) (usid.ID, error) {
+	return usid.Parse(string(b))
+}
+
+// patterns describe the strings each built-in format produces.
+var patterns = map[usid.Format]string{
+	usid.FormatCrockford:      "^[0-9a-hjkmnp-tv-z]{1,13}$",
+	usid.FormatCrockfordFixed: "^[0-9a-hjkmnp-tv-z]{13}$",
+	usid.FormatBase58:         "^[1-9A-HJ-NP-Za-km-z]{1,11}$",
+	usid.FormatBase58Fixed:    "^[1-9A-HJ-NP-Za-km-z]{11}$",
+	usid.FormatBase62:         "^[0-9A-Za-z]{1,11}$",
+	usid.FormatDecimal:        "^[0-9]{1,19}$",
+	usid.FormatHash:           "^[0-9a-f]{1,16}$",
+}
+
+// JSONSchema returns the JSON Schema for an ID as the JSON encoder currently
+// writes it: an integer when usid.JSONNumeric is set, otherwise a string in
+// usid.DefaultFormat, constrained by a pattern for built-in formats. Both
+// carry "format": "usid".
+func JSONSchema() string {
+	if usid.JSONNumeric {
+		return `{"type":"integer","format":"usid","minimum":0,"maximum":9223372036854775807}`
+	}
+	if p, ok := patterns[usid.DefaultFormat]; ok {
+		return `{"type":"string","format":"usid","pattern":` + strconv.Quote(p) + `}`
+	}
+	return `{"type":"string","format":"usid"}`
+}
@@ -0,0 +1,59 @@
+package events_test
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/events"
+)
+
+func TestKey(t *testing.T) {
+	id := usid.New()
+	key := events.Key(id)
+	if len(key) != 8 {
+		t.Fatalf("len(Key) = %d, want 8", len(key))
+	}
+	if got, err := events.ParseKey(key); err != nil || got != id {
+		t.Errorf("ParseKey = %d, %v; want %d", got, err, id)
+	}
+	if _, err := events.ParseKey(key[:4]); err != events.ErrKeyLength {
+		t.Errorf("ParseKey(short) error = %v, want ErrKeyLength", err)
+	}
+}
+
+func TestHeader(t *testing.T) {
+	id := usid.New()
+	if got, err := events.ParseHeader(events.HeaderValue(id)); err != nil || got != id {
+		t.Errorf("ParseHeader = %d, %v; want %d", got, err, id)
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	for _, f := range []usid.Format{usid.FormatCrockford, usid.FormatBase58, usid.FormatBase62, usid.FormatDecimal, usid.FormatHash, usid.FormatCrockfordFixed, usid.FormatBase58Fixed} {
+		usid.DefaultFormat = f
+		var schema struct{ Type, Format, Pattern string }
+		if err := json.Unmarshal([]byte(events.JSONSchema()), &schema); err != nil {
+			t.Fatalf("%s: invalid schema: %v", f, err)
+		}
+		id := usid.New()
+		if !regexp.MustCompile(schema.Pattern).MatchString(id.String()) {
+			t.Errorf("%s: %q does not match %s", f, id.String(), schema.Pattern)
+		}
+		if !regexp.MustCompile(schema.Pattern).MatchString(usid.ID(1<<63 - 1).String()) {
+			t.Errorf("%s: max ID does not match %s", f, schema.Pattern)
+		}
+	}
+	usid.DefaultFormat = usid.FormatCrockford
+
+	usid.JSONNumeric = true
+	defer func() { usid.JSONNumeric = false }()
+	var schema struct{ Type string }
+	if err := json.Unmarshal([]byte(events.JSONSchema()), &schema); err != nil || schema.Type != "integer" {
+		t.Errorf("numeric schema = %s", events.JSONSchema())
+	}
+	if !json.Valid([]byte(events.AvroSchema)) {
+		t.Error("AvroSchema is not valid JSON")
+	}
+}
@@ -0,0 +1,52 @@
+package events_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/events"
+)
+
+func TestAvro(t *testing.T) {
+	for _, nullable := range []bool{false, true} {
+		if f := events.AvroField("id", nullable); !json.Valid([]byte(f)) {
+			t.Errorf("AvroField(nullable=%v) is not valid JSON: %s", nullable, f)
+		}
+	}
+
+	id := usid.New()
+	if got, err := events.FromAvro(events.ToAvro(id)); err != nil || got != id {
+		t.Errorf("FromAvro(ToAvro) = %d, %v; want %d", got, err, id)
+	}
+	if _, err := events.FromAvro("x"); err == nil {
+		t.Error("FromAvro(string): want error")
+	}
+
+	for _, n := range []usid.NullID{{}, {ID: id, Valid: true}} {
+		if got, err := events.NullFromAvro(events.NullToAvro(n)); err != nil || got != n {
+			t.Errorf("NullFromAvro(NullToAvro(%+v)) = %+v, %v", n, got, err)
+		}
+	}
+}
+
+func TestParquetMetadata(t *testing.T) {
+	defer usid.Configure(usid.DefaultConfig())
+	cfg := usid.DefaultConfig()
+	cfg.EraBits, cfg.Era = 2, 1
+	if err := usid.Configure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	key, value := events.ParquetMetadata("id", "parent_id")
+	if key != events.ParquetMetadataKey {
+		t.Errorf("key = %q", key)
+	}
+	columns, cfg, err := events.ParseParquetMetadata(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(columns, []string{"id", "parent_id"}) || cfg != usid.CurrentConfig() {
+		t.Errorf("ParseParquetMetadata = %v, %+v", columns, cfg)
+	}
+}
@@ -0,0 +1,71 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+// ParquetMetadataKey is the file key-value metadata key written by
+// ParquetMetadata.
+const ParquetMetadataKey = "usid"
+
+// parquetMetadata is the JSON value stored under ParquetMetadataKey.
+type parquetMetadata struct {
+	Columns     []string `json:"columns"`
+	Epoch       int64    `json:"epoch"`
+	PrecisionUS int64    `json:"precision_us"`
+	TimeBits    uint8    `json:"time_bits"`
+	NodeBits    uint8    `json:"node_bits"`
+	SeqBits     uint8    `json:"seq_bits"`
+	TenantBits  uint8    `json:"tenant_bits,omitempty"`
+	RandBits    uint8    `json:"rand_bits,omitempty"`
+	EraBits     uint8    `json:"era_bits,omitempty"`
+	Era         int64    `json:"era,omitempty"`
+}
+
+// ParquetMetadata returns a file key-value metadata pair recording which
+// columns hold IDs and the layout that generated them, so exports stay typed
+// and their timestamps decodable. With eras, Epoch is that of the current
+// Era; register the epochs of earlier eras to decode their IDs. Parquet has no extensible logical types;
+// usid.ID has int64 kind, so parquet-go already stores it as INT64, and the
+// pair can be attached with parquet.KeyValueMetadata(key, value).
+func ParquetMetadata(columns ...string) (key, value string) {
+	cfg := usid.CurrentConfig()
+	b, _ := json.Marshal(parquetMetadata{
+		Columns:     columns,
+		Epoch:       cfg.Epoch,
+		PrecisionUS: max(cfg.Precision.Microseconds(), 1),
+		TimeBits:    cfg.TimeBits,
+		NodeBits:    cfg.NodeBits,
+		SeqBits:     cfg.SeqBits,
+		TenantBits:  cfg.TenantBits,
+		RandBits:    cfg.RandBits,
+		EraBits:     cfg.EraBits,
+		Era:         cfg.Era,
+	})
+	return ParquetMetadataKey, string(b)
+}
+
+// ParseParquetMetadata returns the ID columns and layout recorded by
+// ParquetMetadata.
+func ParseParquetMetadata(value string) ([]string, usid.Config, error) {
+	var m parquetMetadata
+	if err := json.Unmarshal([]byte(value), &m); err != nil {
+		return nil, usid.Config{}, fmt.Errorf("usid: invalid parquet metadata: %w", err)
+	}
+	cfg := usid.Config{
+		Epoch:      m.Epoch,
+		Precision:  time.Duration(m.PrecisionUS) * time.Microsecond,
+		TimeBits:   m.TimeBits,
+		NodeBits:   m.NodeBits,
+		SeqBits:    m.SeqBits,
+		TenantBits: m.TenantBits,
+		RandBits:   m.RandBits,
+		EraBits:    m.EraBits,
+		Era:        m.Era,
+	}
+	return m.Columns, cfg, cfg.Validate()
+}
@@ -0,0 +1,37 @@
+package usid
+
+import "strings"
+
+// MaxFailureInput is the number of bytes of rejected input passed to
+// OnParseFailure, so oversized payloads cannot flood audit logs.
+const MaxFailureInput = 64
+
+// ParseFailure describes input rejected by ParseFormat (and so by Parse,
+// UnmarshalText, JSON decoding, Scan of strings, and request binding), by
+// Signer.Verify, or by CheckFuture.
+type ParseFailure struct {
+	Input     string // offending input, truncated to MaxFailureInput bytes
+	Truncated bool   // whether Input was truncated
+	Format    Format // format the input was parsed as; empty for an unregistered Signer
+	Err       error  // error returned to the caller
+}
+
+// OnParseFailure, when set, is called with every ParseFailure, so security
+// teams can watch for enumeration attempts against obfuscated or signed IDs
+// at the application edge. It runs synchronously on the parsing goroutine
+// and must be fast and safe for concurrent use; hand off to a logger or
+// counter. Set it once at startup.
+var OnParseFailure func(ParseFailure)
+
+func reportFailure(s string, f Format, err error) {
+	hook := OnParseFailure
+	if hook == nil {
+		return
+	}
+	pf := ParseFailure{Input: s, Format: f, Err: err}
+	if len(s) > MaxFailureInput {
+		pf.Input = strings.ToValidUTF8(s[:MaxFailureInput], "")
+		pf.Truncated = true
+	}
+	hook(pf)
+}
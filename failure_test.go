@@ -0,0 +1,55 @@
+package usid
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOnParseFailure(t *testing.T) {
+	var failures []ParseFailure
+	OnParseFailure = func(pf ParseFailure) { failures = append(failures, pf) }
+	defer func() { OnParseFailure = nil }()
+
+	id := New()
+	if _, err := Parse(id.String()); err != nil || len(failures) != 0 {
+		t.Fatalf("Parse of a valid ID reported %v", failures)
+	}
+
+	_, err := ParseFormat("!!", FormatBase58)
+	if len(failures) != 1 || failures[0].Input != "!!" || failures[0].Format != FormatBase58 || failures[0].Err != err {
+		t.Errorf("ParseFormat failure reported %+v", failures)
+	}
+
+	long := strings.Repeat("x", 1000)
+	var v struct{ ID ID }
+	json.Unmarshal([]byte(`{"ID":"`+long+`"}`), &v)
+	if pf := failures[len(failures)-1]; len(pf.Input) != MaxFailureInput || !pf.Truncated {
+		t.Errorf("JSON failure reported %d bytes of input, truncated %v", len(pf.Input), pf.Truncated)
+	}
+
+	n := len(failures)
+	if _, err := Canonicalize("!!", FormatDecimal, FormatCrockford); err == nil || len(failures) != n+1 {
+		t.Errorf("Canonicalize reported %d failures, want 1", len(failures)-n)
+	}
+	if _, err := Canonicalize("123", FormatHash, FormatDecimal); err != nil || len(failures) != n+1 {
+		t.Errorf("Canonicalize that succeeds in a later format reported a failure")
+	}
+
+	signer := NewSigner([]byte("failure-test-key"))
+	forged := signer.Sign(id)
+	forged = forged[:len(forged)-1] + "0"
+	if forged == signer.Sign(id) {
+		forged = forged[:len(forged)-1] + "1"
+	}
+	if _, err := signer.Verify(forged); len(failures) != n+2 || !errors.Is(failures[n+1].Err, ErrSignature) || err != failures[n+1].Err {
+		t.Errorf("Signer.Verify failure reported %+v", failures[n+1:])
+	}
+
+	MinIDForTime(time.Now().Add(time.Hour)).CheckFuture()
+	if pf := failures[len(failures)-1]; !errors.Is(pf.Err, ErrFutureID) {
+		t.Errorf("CheckFuture failure reported %+v", pf)
+	}
+}
@@ -0,0 +1,111 @@
+package usid
+
+// Hex, Decimal, and B64URL are IDs that encode in one format whatever
+// DefaultFormat and JSONNumeric say, for payloads whose fields have
+// contractual formats:
+//
+//	type Webhook struct {
+//	    ID      usid.ID      `json:"id"`       // DefaultFormat
+//	    Legacy  usid.Decimal `json:"legacy"`   // "10151254716672"
+//	    Trace   usid.Hex     `json:"trace_id"` // "93b85ee7100"
+//	}
+//
+// They embed ID, so every other method, including Scan and Value, is ID's.
+// JSON decoding also accepts numbers, as for ID.
+type (
+	Hex     struct{ ID }
+	Decimal struct{ ID }
+	B64URL  struct{ ID }
+)
+
+// String returns the ID in FormatHash.
+func (h Hex) String() string { return h.Format(FormatHash) }
+
+// MarshalText implements encoding.TextMarshaler.
+func (h Hex) MarshalText() ([]byte, error) { return h.In(FormatHash).MarshalText() }
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (h *Hex) UnmarshalText(b []byte) error { return unmarshalFixed(&h.ID, FormatHash, b, false) }
+
+// MarshalJSON implements json.Marshaler.
+func (h Hex) MarshalJSON() ([]byte, error) { return h.In(FormatHash).MarshalJSON() }
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (h *Hex) UnmarshalJSON(b []byte) error { return unmarshalFixed(&h.ID, FormatHash, b, true) }
+
+// UnmarshalParam implements the BindUnmarshaler interfaces of gin and echo.
+func (h *Hex) UnmarshalParam(param string) error { return unmarshalParam(&h.ID, FormatHash, param) }
+
+// String returns the ID in FormatDecimal.
+func (d Decimal) String() string { return d.Format(FormatDecimal) }
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Decimal) MarshalText() ([]byte, error) { return d.In(FormatDecimal).MarshalText() }
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Decimal) UnmarshalText(b []byte) error {
+	return unmarshalFixed(&d.ID, FormatDecimal, b, false)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Decimal) MarshalJSON() ([]byte, error) { return d.In(FormatDecimal).MarshalJSON() }
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	return unmarshalFixed(&d.ID, FormatDecimal, b, true)
+}
+
+// UnmarshalParam implements the BindUnmarshaler interfaces of gin and echo.
+func (d *Decimal) UnmarshalParam(param string) error {
+	return unmarshalParam(&d.ID, FormatDecimal, param)
+}
+
+// String returns the ID in FormatBase64URL.
+func (u B64URL) String() string { return u.Format(FormatBase64URL) }
+
+// MarshalText implements encoding.TextMarshaler.
+func (u B64URL) MarshalText() ([]byte, error) { return u.In(FormatBase64URL).MarshalText() }
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (u *B64URL) UnmarshalText(b []byte) error {
+	return unmarshalFixed(&u.ID, FormatBase64URL, b, false)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (u B64URL) MarshalJSON() ([]byte, error) { return u.In(FormatBase64URL).MarshalJSON() }
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (u *B64URL) UnmarshalJSON(b []byte) error {
+	return unmarshalFixed(&u.ID, FormatBase64URL, b, true)
+}
+
+// UnmarshalParam implements the BindUnmarshaler interfaces of gin and echo.
+func (u *B64URL) UnmarshalParam(param string) error {
+	return unmarshalParam(&u.ID, FormatBase64URL, param)
+}
+
+// unmarshalFixed decodes b as text or JSON in format f into id.
+func unmarshalFixed(id *ID, f Format, b []byte, isJSON bool) error {
+	fid := FormattedID{Format: f}
+	var err error
+	if isJSON {
+		err = fid.UnmarshalJSON(b)
+	} else {
+		err = fid.UnmarshalText(b)
+	}
+	if err != nil {
+		return err
+	}
+	*id = fid.ID
+	return nil
+}
+
+// unmarshalParam parses a request parameter in format f into id.
+func unmarshalParam(id *ID, f Format, param string) error {
+	parsed, err := ParseFormat(param, f)
+	if err != nil {
+		return &ParamError{Value: param, Err: err}
+	}
+	*id = parsed
+	return nil
+}
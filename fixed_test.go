@@ -0,0 +1,58 @@
+package usid
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFixedFormats(t *testing.T) {
+	type payload struct {
+		ID     ID      `json:"id"`
+		Hex    Hex     `json:"hex"`
+		Dec    Decimal `json:"dec"`
+		URL    B64URL  `json:"url"`
+		NilHex Hex     `json:"nil_hex,omitzero"`
+	}
+	id := FromInt64(10151254716672)
+	in := payload{ID: id, Hex: Hex{id}, Dec: Decimal{id}, URL: B64URL{id}}
+
+	for _, numeric := range []bool{false, true} {
+		JSONNumeric = numeric
+		b, err := json.Marshal(in)
+		JSONNumeric = false
+		if err != nil {
+			t.Fatal(err)
+		}
+		idJSON, _ := id.MarshalJSON()
+		if numeric {
+			idJSON = []byte("10151254716672")
+		}
+		want := `{"id":` + string(idJSON) + `,"hex":"93b85ee7100","dec":"10151254716672","url":"` + id.Format(FormatBase64URL) + `"}`
+		if string(b) != want {
+			t.Errorf("Marshal (JSONNumeric=%t) = %s, want %s", numeric, b, want)
+		}
+
+		var out payload
+		if err := json.Unmarshal(b, &out); err != nil {
+			t.Fatal(err)
+		}
+		if out != in {
+			t.Errorf("Unmarshal = %+v, want %+v", out, in)
+		}
+	}
+
+	if h := (Hex{id}); h.String() != "93b85ee7100" || h.Node() != id.Node() {
+		t.Errorf("Hex = %s node %d, want 93b85ee7100 node %d", h, h.Node(), id.Node())
+	}
+	var d Decimal
+	if err := d.UnmarshalText([]byte("10151254716672")); err != nil || d.ID != id {
+		t.Errorf("Decimal.UnmarshalText = %v, %v; want %v", d.ID, err, id)
+	}
+	if err := d.UnmarshalJSON([]byte(`"gb61dv03w20"`)); err == nil {
+		t.Error("Decimal.UnmarshalJSON accepted a Crockford string")
+	}
+	var u B64URL
+	if err := u.UnmarshalParam("!"); err == nil {
+		t.Error("B64URL.UnmarshalParam(\"!\"): want err != nil")
+	}
+}
@@ -0,0 +1,218 @@
+package usid
+
+import (
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/paraglidehq/usid/v2/base58"
+	"github.com/paraglidehq/usid/v2/base62"
+	"github.com/paraglidehq/usid/v2/crockford"
+)
+
+// Codec is an ID encoding that appends to a caller's buffer. The Encoding
+// values of the base58 and crockford packages satisfy it; register your own
+// with RegisterCodec.
+type Codec interface {
+	AppendEncode(dst []byte, id int64) []byte
+	Decode(s string) (int64, error)
+}
+
+// Compile-time interface checks for the bundled codecs
+var (
+	_ Codec = base58.StdEncoding
+	_ Codec = crockford.StdEncoding
+)
+
+// formatCodec holds the encode/decode pair for a Format. Every built-in
+// format has one too, so ID.Format, ID.AppendFormat, and ParseFormat dispatch
+// through the same registry for built-in and custom formats.
+type formatCodec struct {
+	enc func(ID) string
+	dec func(string) (ID, error)
+	app func([]byte, ID) []byte
+}
+
+var (
+	formatsMu sync.Mutex
+	formats   atomic.Pointer[map[Format]formatCodec] // copied on write
+)
+
+// builtinFormats are the formats registered by the package itself.
+var builtinFormats = map[Format]bool{}
+
+func init() {
+	for f, c := range map[Format]Codec{
+		FormatCrockford:      crockford.StdEncoding,
+		FormatCrockfordFixed: crockford.FixedEncoding,
+		FormatBase58:         base58.StdEncoding,
+		FormatBase58Fixed:    base58.FixedEncoding,
+		FormatBase62:         codecFuncs{base62.Encode, appendBase62, base62.Decode},
+		FormatDecimal:        codecFuncs{encodeDecimal, appendDecimal, decodeDecimal},
+		FormatBase64:         codecFuncs{encodeBase64, appendBase64, decodeBase64},
+		FormatBase64URL:      codecFuncs{encodeBase64URL, appendBase64URL, decodeBase64URL},
+		FormatHash:           codecFuncs{encodeHash, appendHash, decodeHash},
+	} {
+		registerFormat(string(f), codecFormat(c))
+		builtinFormats[f] = true
+	}
+}
+
+// stringEncoder is implemented by codecs that can also return a string
+// directly, which saves ID.Format a copy through an intermediate buffer.
+type stringEncoder interface {
+	Encode(id int64) string
+}
+
+// codecFuncs adapts encode, append, and decode functions to Codec.
+type codecFuncs struct {
+	enc func(int64) string
+	app func([]byte, int64) []byte
+	dec func(string) (int64, error)
+}
+
+func (c codecFuncs) Encode(id int64) string                   { return c.enc(id) }
+func (c codecFuncs) AppendEncode(dst []byte, id int64) []byte { return c.app(dst, id) }
+func (c codecFuncs) Decode(s string) (int64, error)           { return c.dec(s) }
+
+func appendBase62(b []byte, n int64) []byte { return append(b, base62.Encode(n)...) }
+
+func encodeDecimal(n int64) string { return strconv.FormatInt(n, 10) }
+
+func appendDecimal(b []byte, n int64) []byte { return strconv.AppendInt(b, n, 10) }
+
+func decodeDecimal(s string) (int64, error) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("usid: invalid decimal: %w", err)
+	}
+	return n, nil
+}
+
+func encodeBase64(n int64) string { return base64.StdEncoding.EncodeToString(ID(n).Bytes()) }
+
+func appendBase64(b []byte, n int64) []byte {
+	raw := ID(n).Hash()
+	return base64.StdEncoding.AppendEncode(b, raw[:])
+}
+
+func decodeBase64(s string) (int64, error) {
+	if len(s) != 12 || s[11] != '=' {
+		return 0, errInvalidBase64
+	}
+	id, err := ctDecodeBase64(s[:11], '+', '/')
+	return int64(id), err
+}
+
+func encodeBase64URL(n int64) string {
+	return base64.RawURLEncoding.EncodeToString(ID(n).Bytes())
+}
+
+func appendBase64URL(b []byte, n int64) []byte {
+	raw := ID(n).Hash()
+	return base64.RawURLEncoding.AppendEncode(b, raw[:])
+}
+
+func decodeBase64URL(s string) (int64, error) {
+	id, err := ctDecodeBase64(s, '-', '_')
+	return int64(id), err
+}
+
+func encodeHash(n int64) string { return strconv.FormatUint(uint64(n), 16) }
+
+func appendHash(b []byte, n int64) []byte { return strconv.AppendUint(b, uint64(n), 16) }
+
+func decodeHash(s string) (int64, error) {
+	id, err := ctDecodeHex(s)
+	return int64(id), err
+}
+
+// RegisterFormat makes a custom encoding available under the given name so it
+// works with ID.Format, Parse, DefaultFormat, and text/JSON marshaling.
+//
+// enc receives the ID after obfuscation and dec's result is deobfuscated, so
+// custom formats honor DefaultObfuscator like the built-in ones.
+// Call during initialization. Panics if name is empty, collides with a built-in
+// or already-registered format, or if enc or dec is nil.
+func RegisterFormat(name string, enc func(ID) string, dec func(string) (ID, error)) {
+	if enc == nil || dec == nil {
+		panic("usid: RegisterFormat with nil encoder or decoder")
+	}
+	registerFormat(name, formatCodec{enc: enc, dec: dec, app: func(b []byte, id ID) []byte {
+		return append(b, enc(id)...)
+	}})
+}
+
+// RegisterCodec registers c under the given name, as RegisterFormat does.
+// Unlike a RegisterFormat encoder, c also serves ID.AppendFormat without an
+// intermediate string. If c also has an Encode(int64) string method, ID.Format
+// uses it. c receives and returns obfuscated values, as with RegisterFormat.
+// The built-in formats are registered the same way.
+func RegisterCodec(name string, c Codec) {
+	if c == nil {
+		panic("usid: RegisterCodec with nil codec")
+	}
+	registerFormat(name, codecFormat(c))
+}
+
+// codecFormat returns the formatCodec for c.
+func codecFormat(c Codec) formatCodec {
+	enc := func(id ID) string { return string(c.AppendEncode(nil, int64(id))) }
+	if se, ok := c.(stringEncoder); ok {
+		enc = func(id ID) string { return se.Encode(int64(id)) }
+	}
+	return formatCodec{
+		enc: enc,
+		dec: func(s string) (ID, error) {
+			n, err := c.Decode(s)
+			return ID(n), err
+		},
+		app: func(b []byte, id ID) []byte { return c.AppendEncode(b, int64(id)) },
+	}
+}
+
+func registerFormat(name string, codec formatCodec) {
+	if name == "" {
+		panic("usid: RegisterFormat with empty name")
+	}
+	f := Format(name)
+	if builtinFormats[f] {
+		panic(fmt.Sprintf("usid: RegisterFormat called for built-in format %q", name))
+	}
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	m := make(map[Format]formatCodec)
+	if old := formats.Load(); old != nil {
+		maps.Copy(m, *old)
+	}
+	if _, dup := m[f]; dup {
+		panic(fmt.Sprintf("usid: RegisterFormat called twice for format %q", name))
+	}
+	m[f] = codec
+	formats.Store(&m)
+}
+
+// lookupFormat returns the codec for f, if it is built in or registered.
+func lookupFormat(f Format) (formatCodec, bool) {
+	c, ok := (*formats.Load())[f]
+	return c, ok
+}
+
+// codecFor returns the codec for f, falling back to Crockford for unknown
+// formats.
+func codecFor(f Format) formatCodec {
+	m := *formats.Load()
+	if c, ok := m[f]; ok {
+		return c
+	}
+	return m[FormatCrockford]
+}
+
+// Formats returns every built-in and registered format, sorted by name.
+func Formats() []Format {
+	return slices.Sorted(maps.Keys(*formats.Load()))
+}
@@ -0,0 +1,151 @@
+package usid
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func init() {
+	RegisterFormat("test-prefixed",
+		func(id ID) string { return "id_" + strconv.FormatInt(int64(id), 10) },
+		func(s string) (ID, error) {
+			if !strings.HasPrefix(s, "id_") {
+				return Nil, errors.New("missing prefix")
+			}
+			n, err := strconv.ParseInt(s[3:], 10, 64)
+			return ID(n), err
+		},
+	)
+	RegisterCodec("test-octal", octalCodec{})
+}
+
+// octalCodec is a minimal Codec for testing RegisterCodec.
+type octalCodec struct{}
+
+func (octalCodec) AppendEncode(dst []byte, id int64) []byte { return strconv.AppendInt(dst, id, 8) }
+func (octalCodec) Decode(s string) (int64, error)           { return strconv.ParseInt(s, 8, 64) }
+
+func TestRegisterFormat(t *testing.T) {
+	const f = Format("test-prefixed")
+
+	t.Run("Format", func(t *testing.T) {
+		got := codecTestID.Format(f)
+		if want := "id_1234567890123456789"; got != want {
+			t.Errorf("Format(%s) = %q, want %q", f, got, want)
+		}
+	})
+	t.Run("DefaultFormat", func(t *testing.T) {
+		DefaultFormat = f
+		defer func() { DefaultFormat = FormatCrockford }()
+
+		got, err := Parse(codecTestID.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != codecTestID {
+			t.Errorf("Parse: got %v, want %v", got, codecTestID)
+		}
+		if _, err := Parse("1234"); err == nil {
+			t.Error("Parse(unprefixed): want err != nil")
+		}
+
+		data, err := json.Marshal(codecTestID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `"id_1234567890123456789"`; string(data) != want {
+			t.Errorf("json.Marshal = %s, want %s", data, want)
+		}
+		var parsed ID
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			t.Fatal(err)
+		}
+		if parsed != codecTestID {
+			t.Errorf("json roundtrip: got %v, want %v", parsed, codecTestID)
+		}
+	})
+	t.Run("Obfuscated", func(t *testing.T) {
+		DefaultObfuscator = NewObfuscator(0x0F0F0F0F)
+		DefaultFormat = f
+		defer func() {
+			DefaultObfuscator = nil
+			DefaultFormat = FormatCrockford
+		}()
+
+		s := codecTestID.String()
+		if s == "id_1234567890123456789" {
+			t.Errorf("String() = %q, want obfuscated value", s)
+		}
+		got, err := Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != codecTestID {
+			t.Errorf("Parse: got %v, want %v", got, codecTestID)
+		}
+	})
+	t.Run("Panics", func(t *testing.T) {
+		enc := func(ID) string { return "" }
+		dec := func(string) (ID, error) { return Nil, nil }
+		cases := []struct {
+			name string
+			fn   func()
+		}{
+			{"Empty", func() { RegisterFormat("", enc, dec) }},
+			{"NilEncoder", func() { RegisterFormat("x", nil, dec) }},
+			{"BuiltIn", func() { RegisterFormat(string(FormatBase58), enc, dec) }},
+			{"Duplicate", func() { RegisterFormat(string(f), enc, dec) }},
+		}
+		for _, tt := range cases {
+			t.Run(tt.name, func(t *testing.T) {
+				defer func() {
+					if r := recover(); r == nil {
+						t.Errorf("RegisterFormat did not panic")
+					}
+				}()
+				tt.fn()
+			})
+		}
+	})
+}
+
+func TestRegisterCodec(t *testing.T) {
+	const f = Format("test-octal")
+	if got, want := codecTestID.Format(f), strconv.FormatInt(codecTestID.Int64(), 8); got != want {
+		t.Errorf("Format(%s) = %q, want %q", f, got, want)
+	}
+	if got, err := ParseFormat(codecTestID.Format(f), f); err != nil || got != codecTestID {
+		t.Errorf("ParseFormat = %v, %v; want %v", got, err, codecTestID)
+	}
+	buf := make([]byte, 0, 32)
+	for _, f := range []Format{f, FormatCrockford, FormatCrockfordFixed} {
+		if n := testing.AllocsPerRun(100, func() { codecTestID.AppendFormat(buf, f) }); n != 0 {
+			t.Errorf("AppendFormat(%q) allocates %v times, want 0", f, n)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterCodec(nil) did not panic")
+		}
+	}()
+	RegisterCodec("test-nil", nil)
+}
+
+func TestBuiltinCodecs(t *testing.T) {
+	for f := range builtinFormats {
+		c, ok := lookupFormat(f)
+		if !ok {
+			t.Errorf("built-in format %s is not registered", f)
+			continue
+		}
+		if got, want := string(c.app(nil, codecTestID)), c.enc(codecTestID); got != want {
+			t.Errorf("%s: AppendEncode = %q, Encode = %q", f, got, want)
+		}
+		if got, err := c.dec(c.enc(codecTestID)); err != nil || got != codecTestID {
+			t.Errorf("%s: Decode = %v, %v; want %v", f, got, err, codecTestID)
+		}
+	}
+}
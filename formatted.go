@@ -0,0 +1,81 @@
+package usid
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// FormattedID is an ID bound to the format it encodes in, for response types
+// built per request: its text and JSON encodings use Format instead of
+// DefaultFormat and ignore JSONNumeric. Decoding parses Format, which must
+// be set beforehand; numeric JSON is accepted as for ID.
+//
+//	type Order struct {
+//	    ID usid.FormattedID `json:"id"`
+//	}
+//	resp := Order{ID: order.ID.InContext(ctx)}
+type FormattedID struct {
+	ID     ID
+	Format Format
+}
+
+// In returns the ID bound to format f.
+func (id ID) In(f Format) FormattedID {
+	return FormattedID{ID: id, Format: f}
+}
+
+// InContext returns the ID bound to the format of ctx (see FormatFromContext).
+func (id ID) InContext(ctx context.Context) FormattedID {
+	return id.In(FormatFromContext(ctx))
+}
+
+// String returns the ID encoded in Format.
+func (f FormattedID) String() string {
+	return f.ID.Format(f.Format)
+}
+
+// IsZero reports whether the ID is Nil, so omitzero drops it.
+func (f FormattedID) IsZero() bool {
+	return f.ID.IsZero()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (f FormattedID) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (f *FormattedID) UnmarshalText(b []byte) error {
+	id, err := ParseFormat(string(b), f.Format)
+	if err != nil {
+		return err
+	}
+	f.ID = id
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (f FormattedID) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + f.String() + `"`), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (f *FormattedID) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		f.ID = Nil
+		return nil
+	}
+	if len(b) > 0 && b[0] != '"' {
+		n, err := strconv.ParseInt(string(b), 10, 64)
+		if err != nil {
+			return errors.New("usid: invalid JSON value")
+		}
+		f.ID = deobfuscate(ID(n))
+		return nil
+	}
+	if len(b) < 2 || b[len(b)-1] != '"' {
+		return errors.New("usid: invalid JSON string")
+	}
+	return f.UnmarshalText(b[1 : len(b)-1])
+}
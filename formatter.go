@@ -0,0 +1,42 @@
+package usid
+
+import (
+	"fmt"
+	"time"
+)
+
+var _ fmt.Formatter = Formatter(0)
+
+// Formatter wraps an ID to implement fmt.Formatter. ID cannot implement it
+// directly because its Format method already returns an encoded string.
+//
+//	fmt.Printf("%d", id.Fmt())  // raw decimal value
+//	fmt.Printf("%x", id.Fmt())  // raw hex value
+//	fmt.Printf("%s", id.Fmt())  // DefaultFormat string, same as id.String()
+//	fmt.Printf("%+v", id.Fmt()) // string with timestamp, node, and sequence
+type Formatter ID
+
+// Fmt returns the ID wrapped for use with fmt verbs.
+func (id ID) Fmt() Formatter {
+	return Formatter(id)
+}
+
+// Format implements fmt.Formatter.
+func (f Formatter) Format(s fmt.State, verb rune) {
+	id := ID(f)
+	switch verb {
+	case 'd', 'x', 'X', 'o', 'O', 'b':
+		fmt.Fprintf(s, fmt.FormatString(s, verb), int64(id))
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%s (ts=%s node=%d seq=%d)",
+				id.String(), id.Timestamp().UTC().Format(time.RFC3339Nano), id.Node(), id.Seq())
+			return
+		}
+		fmt.Fprintf(s, fmt.FormatString(s, 's'), id.String())
+	case 's', 'q':
+		fmt.Fprintf(s, fmt.FormatString(s, verb), id.String())
+	default:
+		fmt.Fprintf(s, "%%!%c(usid.ID=%s)", verb, id.String())
+	}
+}
@@ -0,0 +1,40 @@
+package usid
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFormatter(t *testing.T) {
+	id := testID
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"%d", "1234567890123456789"},
+		{"%x", "112210f47de98115"},
+		{"%#x", "0x112210f47de98115"},
+		{"%s", id.String()},
+		{"%v", id.String()},
+		{"%q", `"` + id.String() + `"`},
+		{"%20s", fmt.Sprintf("%20s", id.String())},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprintf(tt.format, id.Fmt()); got != tt.want {
+			t.Errorf("Sprintf(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+
+	got := fmt.Sprintf("%+v", id.Fmt())
+	for _, part := range []string{
+		id.String(),
+		"ts=" + id.Timestamp().UTC().Format("2006-01-02T15:04:05"),
+		fmt.Sprintf("node=%d", id.Node()),
+		fmt.Sprintf("seq=%d", id.Seq()),
+	} {
+		if !strings.Contains(got, part) {
+			t.Errorf("Sprintf(%%+v) = %q, want it to contain %q", got, part)
+		}
+	}
+}
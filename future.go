@@ -0,0 +1,44 @@
+package usid
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxFutureSkew is how far past the current time an ID's timestamp may lie
+// before CheckFuture rejects it. Leave room for clock skew between nodes and
+// for generators using WithHLC or WithMonotonic, which can run ahead of the
+// wall clock.
+var MaxFutureSkew = time.Minute
+
+// ErrFutureID is returned for IDs whose timestamp lies more than
+// MaxFutureSkew in the future. Such IDs were forged, corrupted, or issued by
+// a node with a misconfigured clock or layout.
+var ErrFutureID = errors.New("usid: ID timestamp is in the future")
+
+// CheckFuture returns an error wrapping ErrFutureID if the ID's timestamp is
+// more than MaxFutureSkew after now, and reports it to OnParseFailure. It is
+// a cheap integrity check for IDs arriving from clients: no genuine ID can be
+// dated ahead of its issuer.
+func (id ID) CheckFuture() error {
+	if ahead := time.Until(id.Timestamp()); ahead > MaxFutureSkew {
+		err := fmt.Errorf("%w: %s ahead", ErrFutureID, ahead.Round(time.Millisecond))
+		reportFailure(id.String(), DefaultFormat, err)
+		return err
+	}
+	return nil
+}
+
+// ParseNotFuture is like Parse but also rejects IDs dated in the future (see
+// CheckFuture).
+func ParseNotFuture(s string) (ID, error) {
+	id, err := Parse(s)
+	if err != nil {
+		return Nil, err
+	}
+	if err := id.CheckFuture(); err != nil {
+		return Nil, err
+	}
+	return id, nil
+}
@@ -0,0 +1,33 @@
+package usid
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckFuture(t *testing.T) {
+	if err := NewGenerator(1).Generate().CheckFuture(); err != nil {
+		t.Errorf("CheckFuture() of a fresh ID = %v", err)
+	}
+	if err := MinIDForTime(time.Now().Add(MaxFutureSkew / 2)).CheckFuture(); err != nil {
+		t.Errorf("CheckFuture() within MaxFutureSkew = %v", err)
+	}
+
+	future := MinIDForTime(time.Now().Add(time.Hour))
+	if err := future.CheckFuture(); !errors.Is(err, ErrFutureID) {
+		t.Errorf("CheckFuture() an hour ahead = %v, want ErrFutureID", err)
+	}
+	if _, err := ParseNotFuture(future.String()); !errors.Is(err, ErrFutureID) {
+		t.Errorf("ParseNotFuture() an hour ahead = %v, want ErrFutureID", err)
+	}
+	if _, err := ParseNotFuture("!"); err == nil || errors.Is(err, ErrFutureID) {
+		t.Errorf("ParseNotFuture(%q) = %v, want a parse error", "!", err)
+	}
+
+	defer func(skew time.Duration) { MaxFutureSkew = skew }(MaxFutureSkew)
+	MaxFutureSkew = 2 * time.Hour
+	if err := future.CheckFuture(); err != nil {
+		t.Errorf("CheckFuture() with MaxFutureSkew of 2h = %v", err)
+	}
+}
@@ -1,6 +1,15 @@
 package usid
 
-import "time"
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/binary"
+	"iter"
+	"math/bits"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
 
 // Configuration variables for USID generation.
 // Modify these before generating any IDs if you need custom bit layouts.
@@ -15,20 +24,77 @@ var (
 	// SeqBits is the number of bits allocated for the sequence number (default: 6, max 64 per µs).
 	SeqBits uint8 = 6
 
+	// TenantBits is the number of bits between the node and sequence fields
+	// that hold a tenant (default: 0, no tenant), so rows can be routed by
+	// tenant straight from the primary key. See WithTenant and ID.Tenant.
+	TenantBits uint8 = 0
+
+	// Precision is the timestamp resolution (default: time.Microsecond).
+	// Coarser precision extends the layout's lifetime at the cost of ordering granularity.
+	Precision = time.Microsecond
+
+	// EraBits is the number of high bits, below the sign bit, that hold the
+	// era of an ID (default: 0, no eras). Each era has its own epoch (see
+	// RegisterEra), so the epoch can be rotated, or systems with different
+	// epochs merged, without re-encoding existing IDs. IDs of later eras sort
+	// after those of earlier ones. The timestamp gets the remaining bits.
+	EraBits uint8 = 0
+
+	// Era is the era of newly generated IDs, whose timestamps count from Epoch.
+	// Must fit in EraBits.
+	Era int64 = 0
+
+	// TimeBits caps the number of timestamp bits (default: 0, all remaining bits).
+	// Generate panics once the timestamp no longer fits.
+	TimeBits uint8 = 0
+
+	// RandBits is the number of low sequence bits filled from crypto/rand for each
+	// ID (default: 0). The remaining SeqBits-RandBits bits still count up, so IDs
+	// from one generator stay unique and ordered across ticks, while the random
+	// bits make neighbouring IDs unguessable without an Obfuscator.
+	// Must not exceed SeqBits.
+	RandBits uint8 = 0
+
 	// DefaultFormat is the default string encoding format for IDs.
 	DefaultFormat Format = FormatCrockford
+
+	// SQLFormat makes Value return the ID encoded in this format instead of int64,
+	// for text columns in legacy schemas. Scan accepts both regardless.
+	// Leave empty (the default) for bigint columns.
+	SQLFormat Format
+
+	// JSONNumeric makes MarshalJSON emit IDs as JSON numbers instead of strings.
+	// Only enable it when every consumer parses 64-bit integers exactly:
+	// JavaScript's JSON.parse silently rounds values above MaxSafeInteger (see ID.JSSafe).
+	JSONNumeric bool
+
+	// BinaryVarint makes MarshalBinary, UnmarshalBinary, and gob use the
+	// variable-length encoding of MarshalVarint instead of 8 big-endian bytes.
+	// The two forms are not distinguishable on decode, so writers and readers
+	// must agree; leave it off (the default) for data stored before it was set.
+	BinaryVarint bool
 )
 
 // DefaultGenerator is used by New(). Set via SetNodeID().
 var DefaultGenerator = NewGenerator(1)
 
-// SetNodeID initializes the DefaultGenerator with the given node ID.
-// Call this once at startup before using New().
-func SetNodeID(node int64) {
-	DefaultGenerator = NewGenerator(node)
+// SetNodeID initializes the DefaultGenerator with the given node ID and options.
+// Call this once at startup before using New(). If the new generator is
+// monotonic (see WithMonotonic), it continues after the last ID issued by the
+// previous DefaultGenerator.
+func SetNodeID(node int64, opts ...Option) {
+	g := NewGenerator(node, opts...)
+	if prev := DefaultGenerator; g.monotonic && prev != nil && prev.timeShift == g.timeShift {
+		g.advancePast(prev.lastTick())
+	}
+	DefaultGenerator = g
 }
 
 // New generates an ID using the DefaultGenerator.
+// Successive calls return strictly increasing IDs. When the wall clock steps
+// backwards, New blocks until the clock catches up once the sequence for the
+// last-issued tick runs out; use SetNodeID(node, WithMonotonic()) to keep
+// issuing IDs instead.
 // Panics if SetNodeID() hasn't been called.
 func New() ID {
 	if DefaultGenerator == nil {
@@ -37,51 +103,321 @@ func New() ID {
 	return DefaultGenerator.Generate()
 }
 
+// IDGenerator is implemented by anything that produces IDs, such as
+// *Generator. Accept it instead of *Generator to let callers substitute a
+// fake in tests.
+type IDGenerator interface {
+	Generate() ID
+}
+
+// GeneratorFunc adapts an ordinary function to the IDGenerator interface.
+type GeneratorFunc func() ID
+
+// Generate returns f().
+func (f GeneratorFunc) Generate() ID { return f() }
+
+var _ IDGenerator = (*Generator)(nil)
+
+// Option configures a Generator.
+type Option func(*Generator)
+
+// WithHLC makes the generator behave as a hybrid logical clock: the timestamp
+// component is max(wall clock, last issued or observed timestamp), and when the
+// sequence is exhausted the timestamp advances logically instead of waiting for
+// the wall clock. Combined with Observe, this preserves causal order across
+// nodes with skewed clocks, at the cost of timestamps that may run slightly
+// ahead of real time.
+func WithHLC() Option {
+	return func(g *Generator) { g.hlc = true }
+}
+
+// WithMonotonic makes the generator keep IDs strictly increasing across wall
+// clock regressions without blocking: once the sequence for the last-issued
+// tick is exhausted, it borrows the next tick instead of waiting for the clock
+// to catch up. Timestamps may then run ahead of the wall clock by up to the
+// size of the regression.
+func WithMonotonic() Option {
+	return func(g *Generator) { g.monotonic = true }
+}
+
+// WithShards splits the generator's sequence space across n independently
+// updated shards (rounded up to a power of two), so concurrent callers rarely
+// contend on the same atomic. The shard index takes the top bits of the
+// sequence field, leaving each shard 2^(SeqBits-RandBits-log2(n)) IDs per tick.
+// IDs stay unique, but IDs from different shards within a tick are not ordered
+// by call time, so strict ordering (see WithMonotonic) only holds per shard.
+// Panics if the shards leave no sequence bits.
+func WithShards(n int) Option {
+	return func(g *Generator) {
+		if n > 1 {
+			g.shardBits = uint8(bits.Len(uint(n - 1)))
+		}
+	}
+}
+
+// WithCoarseClock makes the generator read a clock refreshed every resolution
+// by a background ticker instead of calling time.Now on every Generate.
+// Timestamps then lag the wall clock by up to resolution; when a tick's
+// sequence runs out before the clock refreshes, the timestamp advances
+// logically instead of waiting, so sustained bursts can also run ahead. Suited to bulk ingestion where throughput matters more than
+// timestamp accuracy. Panics if resolution is not positive.
+func WithCoarseClock(resolution time.Duration) Option {
+	if resolution <= 0 {
+		panic("usid: coarse clock resolution must be positive")
+	}
+	return func(g *Generator) {
+		g.now = sharedCoarseClock(resolution).Now
+		g.coarse = true
+	}
+}
+
+// WithClock makes the generator read the time from now instead of time.Now,
+// for deterministic tests. If now stops advancing, Generate blocks once the
+// sequence is exhausted (unless combined with WithHLC).
+func WithClock(now func() time.Time) Option {
+	return func(g *Generator) { g.now = now }
+}
+
+// WithTenant makes the generator stamp every ID with tenant t (see
+// TenantBits). NewGenerator panics if t does not fit in TenantBits.
+func WithTenant(t int64) Option {
+	return func(g *Generator) { g.tenant = t }
+}
+
 // NewGenerator creates a Generator for the given node ID.
-// The node ID must be in the range [0, 2^NodeBits - 1].
-// Panics if node is out of range.
-func NewGenerator(node int64) *Generator {
+// The node ID must be in the range [0, 2^NodeBits - 1] and outside DBNodes
+// and EphemeralNodes. Panics if node is out of range or reserved.
+func NewGenerator(node int64, opts ...Option) *Generator {
+	if nodeReserved(node) {
+		panic("usid: node ID reserved")
+	}
+	return newGenerator(node, opts...)
+}
+
+// newGenerator is NewGenerator without the reserved range check.
+func newGenerator(node int64, opts ...Option) *Generator {
 	nodeMax := int64((1 << NodeBits) - 1)
 	if node < 0 || node > nodeMax {
 		panic("usid: node ID out of range")
 	}
-	return &Generator{
+	if RandBits > SeqBits {
+		panic("usid: RandBits exceeds SeqBits")
+	}
+	g := &Generator{
 		node:      node,
-		seqMask:   (1 << SeqBits) - 1,
-		nodeShift: SeqBits,
-		timeShift: SeqBits + NodeBits,
+		nodeShift: SeqBits + TenantBits,
+		timeShift: timeShift(),
+		seqBits:   SeqBits,
+		randBits:  RandBits,
+		era:       eraPrefix(),
+		now:       time.Now,
+		opts:      opts,
 	}
+	if TimeBits > 0 || EraBits > 0 {
+		g.maxTick = 1 << timeFieldBits()
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.tenant < 0 || g.tenant >= 1<<TenantBits {
+		panic("usid: tenant out of range")
+	}
+	g.tenant <<= SeqBits
+	if int(RandBits)+int(g.shardBits) > int(SeqBits) {
+		panic("usid: shards exceed available sequence bits")
+	}
+	g.seqMask = (1 << (SeqBits - RandBits - g.shardBits)) - 1
+	g.nodeMask = (1 << (SeqBits - RandBits)) - 1
+	g.shards = make([]shardState, 1<<g.shardBits)
+	return g
+}
+
+// shardState is one shard of a generator's sequence state: the last-issued
+// tick and counter, packed as tick<<nodeShift | counter, and the shard's
+// statistics. Padding keeps shards on separate cache lines.
+type shardState struct {
+	atomic.Uint64
+	generated   atomic.Uint64
+	retries     atomic.Uint64
+	waits       atomic.Uint64
+	regressions atomic.Uint64
+	behind      atomic.Bool // clock last read behind the last-issued tick
+	_           [20]byte
 }
 
-// Generate produces a new unique ID.
+// Generate produces a new unique ID. With WithMaxRate, it sleeps while the
+// rate limit is exhausted.
 // Safe for concurrent use.
 func (g *Generator) Generate() ID {
+	if g.limit != nil {
+		if d, _ := g.limit.reserve(true); d > 0 {
+			time.Sleep(d)
+		}
+	}
+	return g.generate()
+}
+
+// generate produces a new unique ID, ignoring the rate limit.
+func (g *Generator) generate() ID {
+	var shard int64
+	if len(g.shards) > 1 {
+		shard = int64(rand.N(len(g.shards)))
+	}
+	return g.generateIn(&g.shards[shard], g.seqMask, shard, g.node)
+}
+
+// generateIn produces a new unique ID for node from the sequence state sh,
+// whose counter runs up to mask, stamping it with shard.
+func (g *Generator) generateIn(sh *shardState, mask, shard, node int64) ID {
+	state := &sh.Uint64
 	for {
-		now := time.Now().UnixMicro() - Epoch
+		now := (g.now().UnixMicro() - Epoch) / precisionMicros(Precision)
+		if g.maxTick > 0 && now >= g.maxTick {
+			panic("usid: timestamp exceeds TimeBits")
+		}
 
-		old := g.state.Load()
-		oldTime := int64(old >> SeqBits)
-		oldSeq := int64(old & uint64(g.seqMask))
+		old := state.Load()
+		oldTime := int64(old >> g.nodeShift)
+		oldSeq := int64(old & uint64(mask))
 
 		var newTime, seq int64
 		if now > oldTime {
 			// Time moved forward, reset sequence
 			newTime = now
 			seq = 0
+			if sh.behind.Load() {
+				sh.behind.Store(false)
+			}
 		} else {
-			// Time is same or went backward, increment sequence
-			seq = oldSeq + 1
-			if seq > g.seqMask {
-				// Sequence exhausted, spin until time advances
-				continue
+			// Time is same or went backward, increment sequence. Count a
+			// regression once, not on every call until the clock catches up.
+			if now < oldTime && sh.behind.CompareAndSwap(false, true) {
+				sh.regressions.Add(1)
 			}
+			seq = oldSeq + 1
 			newTime = oldTime
+			if seq > mask {
+				if !g.hlc && !g.coarse && !(g.monotonic && now < oldTime) {
+					// Sequence exhausted, spin until time advances
+					sh.waits.Add(1)
+					continue
+				}
+				// HLC, coarse clock, or clock regression: advance the logical clock instead of waiting
+				newTime, seq = oldTime+1, 0
+			}
+		}
+
+		if !state.CompareAndSwap(old, uint64(newTime<<g.nodeShift)|uint64(seq)) {
+			sh.retries.Add(1)
+			continue
+		}
+		sh.generated.Add(1)
+		if g.randBits > 0 {
+			seq = seq<<g.randBits | randomBits(g.randBits)
+		}
+		seq |= shard << (g.seqBits - g.shardBits)
+		return g.era | ID((newTime<<g.timeShift)|(node<<g.nodeShift)|g.tenant|seq)
+	}
+}
+
+// randomBits returns n bits from crypto/rand.
+func randomBits(n uint8) int64 {
+	var b [8]byte
+	crand.Read(b[:])
+	return int64(binary.LittleEndian.Uint64(b[:]) & (1<<n - 1))
+}
+
+// Observe merges a timestamp seen on another node (for example, the ID of an
+// incoming message) into the generator's clock, so every ID generated afterwards
+// sorts after remote. It only has an effect on generators created with WithHLC.
+// Safe for concurrent use.
+func (g *Generator) Observe(remote ID) {
+	if !g.hlc {
+		return
+	}
+	tick := int64(remote) >> g.timeShift
+	if g.maxTick > 0 {
+		tick &= g.maxTick - 1 // drop era bits
+	}
+	g.advancePast(tick)
+}
+
+// Iter returns an unbounded sequence of newly generated IDs, for ranging over
+// in pipelines:
+//
+//	for id := range g.Iter() {
+//		if !process(id) {
+//			break
+//		}
+//	}
+func (g *Generator) Iter() iter.Seq[ID] {
+	return func(yield func(ID) bool) {
+		for yield(g.Generate()) {
 		}
+	}
+}
 
-		if g.state.CompareAndSwap(old, uint64(newTime<<SeqBits)|uint64(seq)) {
-			return ID((newTime << g.timeShift) | (g.node << g.nodeShift) | seq)
+// Chan starts a goroutine that sends newly generated IDs on the returned
+// channel until ctx is done, then closes it. Generation blocks while the
+// channel's buffer is full, so buffered IDs carry the time they were
+// generated rather than the time they were received.
+func (g *Generator) Chan(ctx context.Context, buffer int) <-chan ID {
+	ch := make(chan ID, buffer)
+	go func() {
+		defer close(ch)
+		for {
+			select {
+			case ch <- g.Generate():
+			case <-ctx.Done():
+				return
+			}
 		}
+	}()
+	return ch
+}
+
+// advancePast marks tick as exhausted so the next ID moves past it
+// regardless of node ordering within the same tick.
+func (g *Generator) advancePast(tick int64) {
+	next := uint64(tick<<g.nodeShift) | uint64(g.seqMask)
+	for i := range g.shards {
+		raise(&g.shards[i].Uint64, next)
+	}
+	next = uint64(tick<<g.nodeShift) | uint64(g.nodeMask)
+	raise(&g.floor, next)
+	g.nodes.Range(func(_, sh any) bool {
+		raise(&sh.(*shardState).Uint64, next)
+		return true
+	})
+}
+
+// raise sets state to next unless it is already at or past it.
+func raise(state *atomic.Uint64, next uint64) {
+	for {
+		old := state.Load()
+		if old >= next || state.CompareAndSwap(old, next) {
+			return
+		}
+	}
+}
+
+// lastTick returns the latest tick any shard has issued an ID in.
+func (g *Generator) lastTick() int64 {
+	return int64(g.lastState() >> g.nodeShift)
+}
+
+// lastState returns the furthest sequence state of any shard, including
+// those of other nodes used through GenerateForNode.
+func (g *Generator) lastState() uint64 {
+	var last uint64
+	for i := range g.shards {
+		last = max(last, g.shards[i].Load())
 	}
+	g.nodes.Range(func(_, sh any) bool {
+		last = max(last, sh.(*shardState).Load())
+		return true
+	})
+	return last
 }
 
 // Deprecated: Use ID.Timestamp() instead
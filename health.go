@@ -0,0 +1,96 @@
+package usid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// ErrSaturated is returned by health checks when callers spend too long
+// waiting for exhausted sequences.
+var ErrSaturated = errors.New("usid: sequence saturated")
+
+// HealthCheck verifies that a generator can keep issuing valid IDs: the node
+// lease (if any) is still held, the clock is within the layout and close to
+// its references, and the sequence space is not saturated. Its Check method
+// has the func(context.Context) error shape most health check libraries
+// accept, and it serves HTTP for mounting at /healthz. The zero value checks
+// DefaultGenerator and the local clock.
+type HealthCheck struct {
+	// Generator to check; nil means DefaultGenerator.
+	Generator *Generator
+
+	// Clocks are compared with the system clock, as in CheckClock.
+	Clocks []ClockReference
+
+	// Lease reports whether the generator's node is still owned, such as
+	// (*localnode.Lease).Check. Nil skips the check.
+	Lease func(ctx context.Context) error
+
+	// MaxWaitsPerID is the largest number of spins waiting for an exhausted
+	// tick (GeneratorStats.ExhaustionWaits) per generated ID, measured since
+	// the previous Check. Spins are busy-loop iterations, so how many a
+	// saturated tick costs depends on the machine and the tick length: set
+	// it above the ratio observed under normal peak load. Zero skips the
+	// saturation check.
+	MaxWaitsPerID float64
+
+	mu   sync.Mutex
+	last GeneratorStats
+}
+
+// Check runs the checks and returns the first failure.
+func (h *HealthCheck) Check(ctx context.Context) error {
+	if h.Lease != nil {
+		if err := h.Lease(ctx); err != nil {
+			return fmt.Errorf("usid: node lease: %w", err)
+		}
+	}
+	if err := CheckClock(ctx, h.Clocks...); err != nil {
+		return err
+	}
+	g := h.Generator
+	if g == nil {
+		g = DefaultGenerator
+	}
+	if g == nil {
+		return errors.New("usid: no generator configured")
+	}
+
+	if h.MaxWaitsPerID == 0 {
+		return nil
+	}
+	st := g.Stats()
+	h.mu.Lock()
+	delta := st.Since(h.last)
+	h.last = st
+	h.mu.Unlock()
+	generated, waits := delta.Generated, delta.ExhaustionWaits
+	if waits > 0 && float64(waits) > h.MaxWaitsPerID*float64(max(generated, 1)) {
+		return fmt.Errorf("%w: %d waits for %d IDs", ErrSaturated, waits, generated)
+	}
+	return nil
+}
+
+// ServeHTTP runs Check with the request's context and responds 200 OK, or
+// 503 Service Unavailable with the error, so a service can mount it at
+// /healthz:
+//
+//	mux.Handle("GET /healthz", check)
+func (h *HealthCheck) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h.Check(r.Context()); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	io.WriteString(w, "ok\n")
+}
+
+// Healthy checks DefaultGenerator against the given clock references. See
+// HealthCheck to check leases or sequence saturation.
+func Healthy(ctx context.Context, refs ...ClockReference) error {
+	return (&HealthCheck{Clocks: refs}).Check(ctx)
+}
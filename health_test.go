@@ -0,0 +1,70 @@
+package usid
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHealthCheck(t *testing.T) {
+	ctx := context.Background()
+	if err := Healthy(ctx); err != nil {
+		t.Fatalf("Healthy() = %v", err)
+	}
+
+	var now atomic.Int64
+	now.Store(time.Now().UnixNano())
+	gen := NewGenerator(2, WithClock(func() time.Time { return time.Unix(0, now.Load()) }))
+	h := &HealthCheck{Generator: gen, MaxWaitsPerID: 1}
+	for range 10 {
+		gen.Generate()
+	}
+	if err := h.Check(ctx); err != nil {
+		t.Errorf("Check() = %v, want nil", err)
+	}
+
+	// Exhaust the tick so Generate spins until the clock moves.
+	done := make(chan struct{})
+	go func() {
+		for range 60 {
+			gen.Generate()
+		}
+		close(done)
+	}()
+	for gen.Stats().ExhaustionWaits < 1000 {
+		time.Sleep(time.Millisecond)
+	}
+	now.Add(int64(time.Millisecond))
+	<-done
+	if err := (&HealthCheck{Generator: gen}).Check(ctx); err != nil {
+		t.Errorf("Check() without MaxWaitsPerID = %v, want nil", err)
+	}
+	if err := h.Check(ctx); !errors.Is(err, ErrSaturated) {
+		t.Errorf("Check() after exhaustion = %v, want ErrSaturated", err)
+	}
+	if err := h.Check(ctx); err != nil {
+		t.Errorf("Check() with no new waits = %v, want nil", err)
+	}
+
+	lost := errors.New("lease lost")
+	h.Lease = func(context.Context) error { return lost }
+	if err := h.Check(ctx); !errors.Is(err, lost) {
+		t.Errorf("Check() with lost lease = %v, want %v", err, lost)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "lease lost") {
+		t.Errorf("ServeHTTP with lost lease = %d %q, want 503", rec.Code, rec.Body)
+	}
+	h.Lease = nil
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("ServeHTTP = %d %q, want 200", rec.Code, rec.Body)
+	}
+}
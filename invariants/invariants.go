@@ -0,0 +1,136 @@
+// Package invariants checks the guarantees of an ID layout from ordinary Go
+// tests, so a custom Config can be certified on real hardware before rollout:
+//
+//	func TestLayout(t *testing.T) {
+//		invariants.Certify(t, usid.Config{Epoch: usid.Epoch, NodeBits: 8, SeqBits: 4})
+//	}
+//
+// The individual checks can also be run against generators built with the
+// options used in production.
+package invariants
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+// N is the number of IDs each check generates per generator.
+var N = 10000
+
+// Certify applies cfg with usid.Configure for the duration of the test and
+// runs every check against it in subtests. It restores the previous layout
+// when the test ends. Tests calling Certify must not run in parallel with
+// other tests that generate IDs.
+func Certify(t *testing.T, cfg usid.Config) {
+	t.Helper()
+	prev := usid.CurrentConfig()
+	if err := usid.Configure(cfg); err != nil {
+		t.Fatalf("invariants: %v", err)
+	}
+	t.Cleanup(func() { usid.Configure(prev) })
+
+	nodes := min(cfg.MaxNode(), 4)
+	t.Run("Unique", func(t *testing.T) { Unique(t, nodes, 4, N) })
+	t.Run("Monotonic", func(t *testing.T) { Monotonic(t, usid.NewGenerator(nodes), N) })
+	t.Run("Components", func(t *testing.T) { Components(t, usid.NewGenerator(nodes), N) })
+	t.Run("Roundtrip", func(t *testing.T) {
+		g := usid.NewGenerator(nodes)
+		ids := []usid.ID{usid.Nil, usid.Omni, usid.MaxSafeInteger}
+		for range N {
+			ids = append(ids, g.Generate())
+		}
+		Roundtrip(t, ids)
+	})
+}
+
+// Unique generates n IDs from each of goroutines goroutines sharing each
+// generator for nodes 1 through nodes, and reports any ID generated twice.
+func Unique(t testing.TB, nodes int64, goroutines, n int) {
+	t.Helper()
+	results := make([][]usid.ID, 0, int(nodes)*goroutines)
+	var wg sync.WaitGroup
+	for node := int64(1); node <= nodes; node++ {
+		g := usid.NewGenerator(node)
+		for range goroutines {
+			ids := make([]usid.ID, n)
+			results = append(results, ids)
+			wg.Go(func() {
+				for i := range ids {
+					ids[i] = g.Generate()
+				}
+			})
+		}
+	}
+	wg.Wait()
+
+	seen := make(map[usid.ID]struct{}, len(results)*n)
+	for _, ids := range results {
+		for _, id := range ids {
+			if _, dup := seen[id]; dup {
+				t.Errorf("invariants: ID %s (%+v) generated twice", id, id.Components())
+				return
+			}
+			seen[id] = struct{}{}
+		}
+	}
+}
+
+// Monotonic generates n IDs from g on one goroutine and reports the first
+// that is not greater than its predecessor. Generators using usid.WithShards
+// only order IDs within a shard and do not pass.
+func Monotonic(t testing.TB, g *usid.Generator, n int) {
+	t.Helper()
+	prev := g.Generate()
+	for range n {
+		id := g.Generate()
+		if id <= prev {
+			t.Errorf("invariants: ID %d is not greater than the previous ID %d", id, prev)
+			return
+		}
+		prev = id
+	}
+}
+
+// Components generates n IDs from g and reports the first whose node is not
+// g's or whose timestamp lies outside the interval in which it was generated,
+// truncated to usid.Precision.
+func Components(t testing.TB, g *usid.Generator, n int) {
+	t.Helper()
+	node := g.Stats().Node
+	for range n {
+		before := time.Now().Add(-usid.Precision)
+		id := g.Generate()
+		after := time.Now()
+		if id.Node() != node {
+			t.Errorf("invariants: ID %d has node %d, want %d", id, id.Node(), node)
+			return
+		}
+		if ts := id.Timestamp(); !ts.After(before) || ts.After(after) {
+			t.Errorf("invariants: ID %d has timestamp %v, want between %v and %v", id, ts, before, after)
+			return
+		}
+	}
+}
+
+// Roundtrip encodes every ID in ids in each of formats and reports the first
+// that does not parse back to the same ID with usid.ParseFormat. With no
+// formats, it checks every format in usid.Formats, including registered ones.
+func Roundtrip(t testing.TB, ids []usid.ID, formats ...usid.Format) {
+	t.Helper()
+	if len(formats) == 0 {
+		formats = usid.Formats()
+	}
+	for _, f := range formats {
+		for _, id := range ids {
+			s := id.Format(f)
+			got, err := usid.ParseFormat(s, f)
+			if err != nil || got != id {
+				t.Errorf("invariants: %s: ID %d encodes as %q, which parses as %d, %v", f, id, s, got, err)
+				break
+			}
+		}
+	}
+}
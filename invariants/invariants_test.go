@@ -0,0 +1,41 @@
+package invariants_test
+
+import (
+	"testing"
+
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/invariants"
+)
+
+func TestCertify(t *testing.T) {
+	invariants.N = 1000
+	for name, cfg := range map[string]usid.Config{
+		"Default": usid.DefaultConfig(),
+		"JSSafe":  usid.JSSafeConfig(),
+		"Random":  usid.RandomConfig(),
+		"Edge":    usid.EdgeConfig(),
+		"Custom":  {Epoch: usid.Epoch, NodeBits: 8, SeqBits: 4},
+	} {
+		t.Run(name, func(t *testing.T) { invariants.Certify(t, cfg) })
+	}
+	if got := usid.CurrentConfig(); got != usid.DefaultConfig() {
+		t.Errorf("Certify left layout %+v", got)
+	}
+}
+
+func TestRoundtripFailure(t *testing.T) {
+	// Fixed-width Crockford cannot encode negative IDs.
+	ft := &fakeT{TB: t}
+	invariants.Roundtrip(ft, []usid.ID{2, -3}, usid.FormatCrockfordFixed)
+	if !ft.failed {
+		t.Error("Roundtrip accepted an ID that does not roundtrip")
+	}
+}
+
+type fakeT struct {
+	testing.TB
+	failed bool
+}
+
+func (f *fakeT) Helper()               {}
+func (f *fakeT) Errorf(string, ...any) { f.failed = true }
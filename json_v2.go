@@ -0,0 +1,106 @@
+//go:build goexperiment.jsonv2 && go1.27
+
+package usid
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"strconv"
+)
+
+// Compile-time interface checks for encoding/json/v2
+var (
+	_ json.MarshalerTo     = ID(0)
+	_ json.UnmarshalerFrom = (*ID)(nil)
+	_ json.MarshalerTo     = NullID{}
+	_ json.UnmarshalerFrom = (*NullID)(nil)
+	_ json.MarshalerTo     = Hex{}
+	_ json.UnmarshalerFrom = (*Hex)(nil)
+	_ json.MarshalerTo     = Decimal{}
+	_ json.UnmarshalerFrom = (*Decimal)(nil)
+	_ json.MarshalerTo     = B64URL{}
+	_ json.UnmarshalerFrom = (*B64URL)(nil)
+)
+
+// MarshalJSONTo implements json.MarshalerTo for encoding/json/v2.
+// It writes the same representation as MarshalJSON directly into the encoder's buffer.
+func (id ID) MarshalJSONTo(enc *jsontext.Encoder) error {
+	b := enc.AvailableBuffer()
+	if JSONNumeric {
+		return enc.WriteValue(strconv.AppendInt(b, int64(obfuscate(id)), 10))
+	}
+	b = append(b, '"')
+	b = id.AppendFormat(b, DefaultFormat)
+	b = append(b, '"')
+	return enc.WriteValue(b)
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom for encoding/json/v2.
+// It accepts the same inputs as UnmarshalJSON.
+func (id *ID) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	v, err := dec.ReadValue()
+	if err != nil {
+		return err
+	}
+	return id.UnmarshalJSON(v)
+}
+
+// MarshalJSONTo implements json.MarshalerTo for encoding/json/v2.
+func (n NullID) MarshalJSONTo(enc *jsontext.Encoder) error {
+	if !n.Valid {
+		return enc.WriteToken(jsontext.Null)
+	}
+	return n.ID.MarshalJSONTo(enc)
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom for encoding/json/v2.
+func (n *NullID) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	v, err := dec.ReadValue()
+	if err != nil {
+		return err
+	}
+	return n.UnmarshalJSON(v)
+}
+
+// Hex, Decimal, and B64URL override the methods they would otherwise promote
+// from ID, which encode in DefaultFormat.
+
+// MarshalJSONTo implements json.MarshalerTo for encoding/json/v2.
+func (h Hex) MarshalJSONTo(enc *jsontext.Encoder) error { return writeFixed(enc, h.ID, FormatHash) }
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom for encoding/json/v2.
+func (h *Hex) UnmarshalJSONFrom(dec *jsontext.Decoder) error { return readFixed(dec, h) }
+
+// MarshalJSONTo implements json.MarshalerTo for encoding/json/v2.
+func (d Decimal) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return writeFixed(enc, d.ID, FormatDecimal)
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom for encoding/json/v2.
+func (d *Decimal) UnmarshalJSONFrom(dec *jsontext.Decoder) error { return readFixed(dec, d) }
+
+// MarshalJSONTo implements json.MarshalerTo for encoding/json/v2.
+func (u B64URL) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return writeFixed(enc, u.ID, FormatBase64URL)
+}
+
+// UnmarshalJSONFrom implements json.UnmarshalerFrom for encoding/json/v2.
+func (u *B64URL) UnmarshalJSONFrom(dec *jsontext.Decoder) error { return readFixed(dec, u) }
+
+// writeFixed writes id as a JSON string in format f.
+func writeFixed(enc *jsontext.Encoder, id ID, f Format) error {
+	b := enc.AvailableBuffer()
+	b = append(b, '"')
+	b = id.AppendFormat(b, f)
+	b = append(b, '"')
+	return enc.WriteValue(b)
+}
+
+// readFixed reads the next JSON value into u with its UnmarshalJSON.
+func readFixed(dec *jsontext.Decoder, u interface{ UnmarshalJSON([]byte) error }) error {
+	v, err := dec.ReadValue()
+	if err != nil {
+		return err
+	}
+	return u.UnmarshalJSON(v)
+}
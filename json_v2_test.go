@@ -0,0 +1,91 @@
+//go:build goexperiment.jsonv2 && go1.27
+
+package usid
+
+import (
+	"encoding/json/jsontext"
+	"encoding/json/v2"
+	"io"
+	"testing"
+)
+
+func TestJSONv2(t *testing.T) {
+	type record struct {
+		ID       ID     `json:"id"`
+		ParentID NullID `json:"parent_id"`
+	}
+
+	t.Run("Roundtrip", func(t *testing.T) {
+		in := record{ID: testID, ParentID: NullID{ID: testID, Valid: true}}
+		data, err := json.Marshal(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"id":"` + testID.String() + `","parent_id":"` + testID.String() + `"}`
+		if string(data) != want {
+			t.Errorf("json.Marshal = %s, want %s", data, want)
+		}
+		var out record
+		if err := json.Unmarshal(data, &out); err != nil {
+			t.Fatal(err)
+		}
+		if out != in {
+			t.Errorf("roundtrip: got %+v, want %+v", out, in)
+		}
+	})
+	t.Run("Null", func(t *testing.T) {
+		data, err := json.Marshal(record{ID: testID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"id":"` + testID.String() + `","parent_id":null}`
+		if string(data) != want {
+			t.Errorf("json.Marshal = %s, want %s", data, want)
+		}
+		var out record
+		if err := json.Unmarshal(data, &out); err != nil {
+			t.Fatal(err)
+		}
+		if out.ParentID.Valid {
+			t.Errorf("ParentID.Valid = true, want false")
+		}
+	})
+	t.Run("Numeric", func(t *testing.T) {
+		JSONNumeric = true
+		defer func() { JSONNumeric = false }()
+
+		data, err := json.Marshal(testID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "1234567890123456789"; string(data) != want {
+			t.Errorf("json.Marshal = %s, want %s", data, want)
+		}
+		var got ID
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got != testID {
+			t.Errorf("roundtrip: got %v, want %v", got, testID)
+		}
+	})
+}
+
+func TestJSONv2Allocs(t *testing.T) {
+	enc := jsontext.NewEncoder(io.Discard)
+	for _, v := range []json.MarshalerTo{testID, NullID{ID: testID, Valid: true}, Hex{testID}, Decimal{testID}, B64URL{testID}} {
+		if n := testing.AllocsPerRun(100, func() { v.MarshalJSONTo(enc) }); n != 0 {
+			t.Errorf("%T.MarshalJSONTo: %v allocs, want 0", v, n)
+		}
+	}
+}
+
+// BenchmarkJSONv2Marshal measures MarshalJSONTo on a reused encoder;
+// json.Marshal adds its own allocations for the result and the interface.
+func BenchmarkJSONv2Marshal(b *testing.B) {
+	enc := jsontext.NewEncoder(io.Discard)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		testID.MarshalJSONTo(enc)
+	}
+}
@@ -0,0 +1,230 @@
+// Package keys loads obfuscation and signing key material from the
+// environment, files, AWS KMS, or HashiCorp Vault, so secrets stay out of
+// source code.
+//
+//	src := keys.NewCache(keys.Vault{Addr: addr, Token: token, Path: "secret/data/usid", Field: "obfuscation"}, time.Hour)
+//	err := keys.SetObfuscator(ctx, src)
+//
+// Text sources (Env, File, and Vault) hold the key base64-encoded, as
+// produced by `openssl rand -base64 8`.
+package keys
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+// ErrNoKey is returned when a source holds no key material.
+var ErrNoKey = errors.New("usid: no key material")
+
+// Source returns key material.
+type Source interface {
+	Key(ctx context.Context) ([]byte, error)
+}
+
+// SourceFunc adapts a function to a Source.
+type SourceFunc func(ctx context.Context) ([]byte, error)
+
+// Key calls f.
+func (f SourceFunc) Key(ctx context.Context) ([]byte, error) { return f(ctx) }
+
+// Env returns a Source reading the base64 key in the named environment
+// variable.
+func Env(name string) Source {
+	return SourceFunc(func(context.Context) ([]byte, error) {
+		s, ok := os.LookupEnv(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not set", ErrNoKey, name)
+		}
+		return decode(name, s)
+	})
+}
+
+// File returns a Source reading the base64 key in the file at path, such as
+// a mounted Kubernetes secret. The file is read on every call, so a Cache
+// picks up secrets rotated in place.
+func File(path string) Source {
+	return SourceFunc(func(context.Context) ([]byte, error) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("usid: read key: %w", err)
+		}
+		return decode(path, string(b))
+	})
+}
+
+// Decrypter decrypts a KMS ciphertext. Adapt the AWS SDK's kms.Client with
+// a few lines calling Decrypt and returning the output's Plaintext.
+type Decrypter interface {
+	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
+}
+
+// KMS returns a Source decrypting ciphertext, a data key encrypted under a
+// KMS key, with d. The ciphertext itself is not secret and can be committed
+// or passed in configuration.
+func KMS(d Decrypter, ciphertext []byte) Source {
+	return SourceFunc(func(ctx context.Context) ([]byte, error) {
+		key, err := d.Decrypt(ctx, ciphertext)
+		if err != nil {
+			return nil, fmt.Errorf("usid: kms decrypt: %w", err)
+		}
+		if len(key) == 0 {
+			return nil, fmt.Errorf("%w: kms returned an empty plaintext", ErrNoKey)
+		}
+		return key, nil
+	})
+}
+
+// Vault reads a base64 key from a field of a HashiCorp Vault KV version 2
+// secret.
+type Vault struct {
+	Addr   string       // server address, such as "https://vault.internal:8200"
+	Token  string       // Vault token
+	Path   string       // API path of the secret, such as "secret/data/usid"
+	Field  string       // key within the secret's data
+	Client *http.Client // nil means http.DefaultClient
+}
+
+// Key implements Source.
+func (v Vault) Key(ctx context.Context) ([]byte, error) {
+	url := strings.TrimRight(v.Addr, "/") + "/v1/" + strings.TrimLeft(v.Path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("usid: vault: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", v.Token)
+	client := v.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("usid: vault: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("usid: vault: GET %s: %s", url, resp.Status)
+	}
+	var secret struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&secret); err != nil {
+		return nil, fmt.Errorf("usid: vault: %w", err)
+	}
+	s, ok := secret.Data.Data[v.Field]
+	if !ok {
+		return nil, fmt.Errorf("%w: vault secret %s has no field %q", ErrNoKey, v.Path, v.Field)
+	}
+	return decode(v.Path, s)
+}
+
+func decode(name, s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("usid: %s: key is not base64: %w", name, err)
+	}
+	if len(key) == 0 {
+		return nil, fmt.Errorf("%w: %s is empty", ErrNoKey, name)
+	}
+	return key, nil
+}
+
+// Cache is a Source that remembers the key of another Source for a TTL.
+// When a refresh returns different material, the OnRotate callbacks run
+// with the new key. If a refresh fails, Cache keeps serving the previous
+// key until the next attempt a TTL later, so an outage of the secret store
+// does not take down ID encoding. Safe for concurrent use.
+type Cache struct {
+	src Source
+	ttl time.Duration
+	now func() time.Time
+
+	mu       sync.Mutex
+	key      []byte
+	fetched  time.Time
+	onRotate []func(key []byte)
+}
+
+// NewCache returns a Cache refreshing src every ttl.
+func NewCache(src Source, ttl time.Duration) *Cache {
+	return &Cache{src: src, ttl: ttl, now: time.Now}
+}
+
+// OnRotate registers fn to run whenever a refresh observes new key
+// material. The Cache is locked while fn runs, so fn must not call Key.
+func (c *Cache) OnRotate(fn func(key []byte)) {
+	c.mu.Lock()
+	c.onRotate = append(c.onRotate, fn)
+	c.mu.Unlock()
+}
+
+// Key implements Source.
+func (c *Cache) Key(ctx context.Context) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.key != nil && c.now().Sub(c.fetched) < c.ttl {
+		return c.key, nil
+	}
+	key, err := c.src.Key(ctx)
+	c.fetched = c.now()
+	if err != nil {
+		if c.key != nil {
+			return c.key, nil
+		}
+		return nil, err
+	}
+	if c.key != nil && string(key) != string(c.key) {
+		for _, fn := range c.onRotate {
+			fn(key)
+		}
+	}
+	c.key = key
+	return key, nil
+}
+
+// ObfuscationKey converts 8 bytes of key material to an Obfuscator key.
+func ObfuscationKey(key []byte) (int64, error) {
+	if len(key) != 8 {
+		return 0, fmt.Errorf("usid: obfuscation key must be 8 bytes, got %d", len(key))
+	}
+	return int64(binary.BigEndian.Uint64(key)), nil
+}
+
+// SetObfuscator loads a key from src and installs it with usid.SetObfuscator.
+// Rotating the obfuscation key changes every ID's external form, so do not
+// wire it to OnRotate unless clients can tolerate that.
+func SetObfuscator(ctx context.Context, src Source) error {
+	key, err := src.Key(ctx)
+	if err != nil {
+		return err
+	}
+	k, err := ObfuscationKey(key)
+	if err != nil {
+		return err
+	}
+	usid.SetObfuscator(k)
+	return nil
+}
+
+// NewSigner loads a key from src and returns a usid.Signer for it.
+func NewSigner(ctx context.Context, src Source) (*usid.Signer, error) {
+	key, err := src.Key(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return usid.NewSigner(key), nil
+}
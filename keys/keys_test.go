@@ -0,0 +1,132 @@
+package keys
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+func TestEnvAndFile(t *testing.T) {
+	ctx := context.Background()
+	want := []byte("12345678")
+	t.Setenv("USID_TEST_KEY", base64.StdEncoding.EncodeToString(want))
+	if got, err := Env("USID_TEST_KEY").Key(ctx); err != nil || string(got) != string(want) {
+		t.Errorf("Env = %q, %v, want %q", got, err, want)
+	}
+	if _, err := Env("USID_TEST_UNSET").Key(ctx); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Env of an unset variable = %v, want ErrNoKey", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(want)+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := File(path).Key(ctx); err != nil || string(got) != string(want) {
+		t.Errorf("File = %q, %v, want %q", got, err, want)
+	}
+	if err := os.WriteFile(path, []byte("not base64!"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := File(path).Key(ctx); err == nil {
+		t.Error("File with invalid base64: want error")
+	}
+}
+
+type decrypter func(ctx context.Context, ciphertext []byte) ([]byte, error)
+
+func (d decrypter) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return d(ctx, ciphertext)
+}
+
+func TestKMS(t *testing.T) {
+	d := decrypter(func(_ context.Context, ciphertext []byte) ([]byte, error) {
+		return append([]byte("plain:"), ciphertext...), nil
+	})
+	if got, err := KMS(d, []byte("blob")).Key(context.Background()); err != nil || string(got) != "plain:blob" {
+		t.Errorf("KMS = %q, %v", got, err)
+	}
+}
+
+func TestVault(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/data/usid" || r.Header.Get("X-Vault-Token") != "tok" {
+			http.Error(w, "permission denied", http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"data":{"data":{"obfuscation":"AAAAAAAAMDk="},"metadata":{"version":3}}}`))
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	v := Vault{Addr: srv.URL, Token: "tok", Path: "secret/data/usid", Field: "obfuscation"}
+	key, err := v.Key(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k, err := ObfuscationKey(key); err != nil || k != 12345 {
+		t.Errorf("ObfuscationKey(Vault key) = %d, %v, want 12345", k, err)
+	}
+	v.Field = "missing"
+	if _, err := v.Key(ctx); !errors.Is(err, ErrNoKey) {
+		t.Errorf("Vault with a missing field = %v, want ErrNoKey", err)
+	}
+	v.Token = "wrong"
+	if _, err := v.Key(ctx); err == nil {
+		t.Error("Vault with a bad token: want error")
+	}
+}
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	current, fail, calls := "a", false, 0
+	c := NewCache(SourceFunc(func(context.Context) ([]byte, error) {
+		calls++
+		if fail {
+			return nil, errors.New("store down")
+		}
+		return []byte(current), nil
+	}), time.Minute)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	var rotated []string
+	c.OnRotate(func(key []byte) { rotated = append(rotated, string(key)) })
+
+	for range 3 {
+		if key, err := c.Key(ctx); err != nil || string(key) != "a" {
+			t.Fatalf("Key = %q, %v, want a", key, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("source called %d times within the TTL, want 1", calls)
+	}
+
+	current, now = "b", now.Add(time.Minute)
+	if key, _ := c.Key(ctx); string(key) != "b" || len(rotated) != 1 || rotated[0] != "b" {
+		t.Errorf("Key after rotation = %q, rotated %q, want b", key, rotated)
+	}
+
+	fail, now = true, now.Add(time.Minute)
+	if key, err := c.Key(ctx); err != nil || string(key) != "b" {
+		t.Errorf("Key while the source fails = %q, %v, want stale b", key, err)
+	}
+}
+
+func TestSetObfuscator(t *testing.T) {
+	defer func() { usid.DefaultObfuscator = nil }()
+	src := SourceFunc(func(context.Context) ([]byte, error) { return []byte("short"), nil })
+	if err := SetObfuscator(context.Background(), src); err == nil || usid.DefaultObfuscator != nil {
+		t.Errorf("SetObfuscator with a 5-byte key = %v, want error and no obfuscator", err)
+	}
+	src = SourceFunc(func(context.Context) ([]byte, error) { return []byte("8 bytes!"), nil })
+	if err := SetObfuscator(context.Background(), src); err != nil || usid.DefaultObfuscator == nil {
+		t.Errorf("SetObfuscator = %v, want obfuscator installed", err)
+	}
+}
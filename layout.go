@@ -0,0 +1,47 @@
+package usid
+
+import (
+	"fmt"
+	"time"
+)
+
+// ParseWithConfig parses s in DefaultFormat as an ID minted under cfg rather
+// than the current layout, such as one copied from another system or from
+// before a migration. It returns an error if cfg is invalid, or if the ID
+// is negative or sets bits cfg leaves unused. Decode the result with
+// TimestampWithConfig; the other accessors still assume the current layout.
+func ParseWithConfig(s string, cfg Config) (ID, error) {
+	if err := cfg.Validate(); err != nil {
+		return Nil, err
+	}
+	id, err := Parse(s)
+	if err != nil {
+		return Nil, err
+	}
+	if id.IsSpecial() {
+		return id, nil
+	}
+	if id < 0 {
+		return Nil, fmt.Errorf("usid: ID %d is negative", int64(id))
+	}
+	if cfg.TimeBits > 0 {
+		used := cfg.TimeBits + cfg.NodeBits + cfg.TenantBits + cfg.SeqBits
+		unused := (int64(1)<<(63-cfg.EraBits) - 1) &^ (int64(1)<<used - 1)
+		if int64(id)&unused != 0 {
+			return Nil, fmt.Errorf("usid: ID %d sets bits outside the %d-bit layout", int64(id), used+cfg.EraBits)
+		}
+	}
+	return id, nil
+}
+
+// TimestampWithConfig extracts the creation time from the ID as laid out by
+// cfg instead of the current layout. Timestamps count from cfg.Epoch whatever
+// the ID's era; eras registered with RegisterEra apply only to Timestamp.
+func (id ID) TimestampWithConfig(cfg Config) time.Time {
+	shift := cfg.NodeBits + cfg.TenantBits + cfg.SeqBits
+	ticks := int64(id) >> shift
+	if cfg.EraBits > 0 {
+		ticks &= 1<<(63-cfg.EraBits-shift) - 1
+	}
+	return time.UnixMicro(ticks*precisionMicros(cfg.Precision) + cfg.Epoch)
+}
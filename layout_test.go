@@ -0,0 +1,52 @@
+package usid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWithConfig(t *testing.T) {
+	old := Config{
+		Epoch:     time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).UnixMicro(),
+		Precision: time.Millisecond,
+		TimeBits:  40,
+		NodeBits:  10,
+		SeqBits:   12,
+	}
+	at := time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC)
+	raw := (at.UnixMilli()-old.Epoch/1000)<<22 | 5<<12 | 7
+
+	id, err := ParseWithConfig(FromInt64(raw).String(), old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := id.TimestampWithConfig(old); !got.Equal(at) {
+		t.Errorf("TimestampWithConfig = %v, want %v", got, at)
+	}
+	if got := id.TimestampWithConfig(CurrentConfig()); !got.Equal(id.Timestamp()) {
+		t.Errorf("TimestampWithConfig(CurrentConfig()) = %v, want Timestamp() = %v", got, id.Timestamp())
+	}
+
+	era := old
+	era.TimeBits, era.EraBits, era.Era = 0, 2, 1
+	if got := FromInt64(1<<61 | raw).TimestampWithConfig(era); !got.Equal(at) {
+		t.Errorf("TimestampWithConfig with era bits = %v, want %v", got, at)
+	}
+
+	for _, bad := range []struct {
+		s   string
+		cfg Config
+	}{
+		{FromInt64(1 << 62).String(), old},              // beyond 40 + 22 bits
+		{FromInt64(-5).String(), old},                   // negative
+		{FromInt64(raw).String(), Config{NodeBits: 64}}, // invalid layout
+		{"!", old}, // unparsable
+	} {
+		if _, err := ParseWithConfig(bad.s, bad.cfg); err == nil {
+			t.Errorf("ParseWithConfig(%q, %+v): want err != nil", bad.s, bad.cfg)
+		}
+	}
+	if id, err := ParseWithConfig(Omni.String(), old); err != nil || id != Omni {
+		t.Errorf("ParseWithConfig(Omni) = %v, %v; want Omni", id, err)
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package localnode
+
+import (
+	"errors"
+	"os"
+)
+
+func tryLock(f *os.File) (bool, error) {
+	return false, errors.ErrUnsupported
+}
@@ -0,0 +1,182 @@
+// Package localnode allocates node IDs among processes on one host, for CLI
+// tools and worker pools that cannot reach Postgres or Redis.
+//
+// Each node is a file in a shared lock directory. A process owns a node while
+// it holds an exclusive flock on the node's file; the file records the owner's
+// PID for inspection. The kernel drops the lock when the process exits, even
+// if it crashes, so nodes are never leaked.
+//
+//	lease, err := localnode.Acquire("/run/myapp/usid")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer lease.Release()
+//	usid.SetNodeID(lease.Node)
+package localnode
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+var (
+	// ErrExhausted is returned when every node in the range is held.
+	ErrExhausted = errors.New("usid: no free local node")
+
+	// ErrReleased is returned by Check after Release.
+	ErrReleased = errors.New("usid: node lease released")
+)
+
+// Lease is a node ID held by this process until Release.
+type Lease struct {
+	Node int64
+	f    *os.File
+}
+
+// Acquire leases the lowest free node in [1, MaxNode] of the current layout,
+// leaving node 0 to the Postgres usid() function.
+func Acquire(dir string) (*Lease, error) {
+	return AcquireRange(dir, 1, usid.CurrentConfig().MaxNode())
+}
+
+// AcquireRange leases the lowest free node in [first, last], creating dir if
+// needed. Processes sharing a host must use the same dir.
+func AcquireRange(dir string, first, last int64) (*Lease, error) {
+	if first < 0 || first > last {
+		return nil, fmt.Errorf("usid: invalid node range [%d, %d]", first, last)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	for node := first; node <= last; node++ {
+		f, err := os.OpenFile(path(dir, node), os.O_RDWR|os.O_CREATE, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		ok, err := tryLock(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		if !ok {
+			f.Close()
+			continue
+		}
+		if err := writePID(f); err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &Lease{Node: node, f: f}, nil
+	}
+	return nil, ErrExhausted
+}
+
+// Release gives the node back. Using the node after Release risks
+// duplicate IDs.
+func (l *Lease) Release() error {
+	if l.f == nil {
+		return nil
+	}
+	// Clear the PID before unlocking so readers never see a stale owner.
+	l.f.Truncate(0)
+	err := l.f.Close() // closing drops the lock
+	l.f = nil
+	return err
+}
+
+// Check reports an error if the lease was released or its lock file was
+// deleted or replaced, which would let another process take the node. Use it
+// as usid.HealthCheck.Lease.
+func (l *Lease) Check(ctx context.Context) error {
+	if l.f == nil {
+		return ErrReleased
+	}
+	held, err := l.f.Stat()
+	if err != nil {
+		return err
+	}
+	onDisk, err := os.Stat(l.f.Name())
+	if err != nil {
+		return fmt.Errorf("usid: node %d lock file: %w", l.Node, err)
+	}
+	if !os.SameFile(held, onDisk) {
+		return fmt.Errorf("usid: node %d lock file was replaced", l.Node)
+	}
+	return nil
+}
+
+// Holder describes a node file in a lock directory.
+type Holder struct {
+	Node int64
+	PID  int  // last recorded owner, or 0 if unknown
+	Held bool // whether a process currently holds the lock
+}
+
+// List reports every node file in dir, in node order, checking whether each
+// is held by trying its lock.
+func List(dir string) ([]Holder, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var holders []Holder
+	for _, e := range entries {
+		n, ok := strings.CutPrefix(e.Name(), "node-")
+		if !ok {
+			continue
+		}
+		node, err := strconv.ParseInt(strings.TrimSuffix(n, ".lock"), 10, 64)
+		if err != nil {
+			continue
+		}
+		h, err := inspect(dir, node)
+		if err != nil {
+			return nil, err
+		}
+		holders = append(holders, h)
+	}
+	slices.SortFunc(holders, func(a, b Holder) int { return cmp.Compare(a.Node, b.Node) })
+	return holders, nil
+}
+
+func inspect(dir string, node int64) (Holder, error) {
+	h := Holder{Node: node}
+	f, err := os.OpenFile(path(dir, node), os.O_RDWR, 0)
+	if err != nil {
+		return h, err
+	}
+	defer f.Close()
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return h, err
+	}
+	h.PID, _ = strconv.Atoi(strings.TrimSpace(string(b)))
+	free, err := tryLock(f)
+	if err != nil {
+		return h, err
+	}
+	h.Held = !free
+	return h, nil // closing f releases the probe lock
+}
+
+func path(dir string, node int64) string {
+	return filepath.Join(dir, fmt.Sprintf("node-%d.lock", node))
+}
+
+func writePID(f *os.File) error {
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	_, err := f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	return err
+}
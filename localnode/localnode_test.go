@@ -0,0 +1,64 @@
+package localnode_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paraglidehq/usid/v2/localnode"
+)
+
+func TestAcquire(t *testing.T) {
+	dir := t.TempDir()
+	a, err := localnode.AcquireRange(dir, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := localnode.AcquireRange(dir, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Node != 1 || b.Node != 2 {
+		t.Errorf("nodes = %d, %d; want 1, 2", a.Node, b.Node)
+	}
+	if _, err := localnode.AcquireRange(dir, 1, 2); !errors.Is(err, localnode.ErrExhausted) {
+		t.Errorf("third Acquire err = %v, want ErrExhausted", err)
+	}
+
+	holders, err := localnode.List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(holders) != 2 || !holders[0].Held || holders[0].PID != os.Getpid() {
+		t.Errorf("List() = %+v, want both held by %d", holders, os.Getpid())
+	}
+
+	if err := a.Check(context.Background()); err != nil {
+		t.Errorf("Check() = %v, want nil", err)
+	}
+	if err := a.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Check(context.Background()); !errors.Is(err, localnode.ErrReleased) {
+		t.Errorf("Check() after Release = %v, want ErrReleased", err)
+	}
+	c, err := localnode.AcquireRange(dir, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Node != 1 {
+		t.Errorf("Acquire after Release = node %d, want 1", c.Node)
+	}
+	os.Remove(filepath.Join(dir, "node-2.lock"))
+	if err := b.Check(context.Background()); err == nil {
+		t.Error("Check() with deleted lock file = nil, want error")
+	}
+	b.Release()
+	os.WriteFile(filepath.Join(dir, "node-2.lock"), nil, 0o644)
+	holders, _ = localnode.List(dir)
+	if holders[1].Held {
+		t.Errorf("node 2 still held after Release: %+v", holders[1])
+	}
+}
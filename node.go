@@ -0,0 +1,125 @@
+package usid
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"os"
+	"strconv"
+)
+
+// NodeFromEnv reads a node ID from the named environment variable, such as
+// "USID_NODE". It returns an error if the variable is unset, not an integer,
+// out of range for the current layout, or outside StaticNodes when that is set.
+func NodeFromEnv(name string) (int64, error) {
+	s, ok := os.LookupEnv(name)
+	if !ok {
+		return 0, fmt.Errorf("usid: %s is not set", name)
+	}
+	node, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("usid: %s: invalid node ID %q", name, s)
+	}
+	if maxNode := CurrentConfig().MaxNode(); node < 0 || node > maxNode {
+		return 0, fmt.Errorf("usid: %s: node ID %d out of range [0, %d]", name, node, maxNode)
+	}
+	if !StaticNodes.IsZero() && !StaticNodes.Contains(node) {
+		return 0, fmt.Errorf("usid: %s: node ID %d outside StaticNodes [%d, %d]", name, node, StaticNodes.Min, StaticNodes.Max)
+	}
+	return node, nil
+}
+
+// NodeFromHostname derives a node ID from the host name with HashNode.
+func NodeFromHostname(bits uint8) (int64, error) {
+	host, err := os.Hostname()
+	if err != nil {
+		return 0, err
+	}
+	return HashNode(host, bits)
+}
+
+// HashNode hashes s to a node ID in [1, 2^bits-1], leaving node 0 to the
+// Postgres usid() function. bits must be between 1 and NodeBits; using fewer
+// bits than NodeBits leaves the rest of the node space to other allocators.
+//
+// Hashing needs no coordination but can collide: among h hosts spread over
+// n = 2^bits-1 nodes, the chance that two share a node is about
+// 1-e^(-h(h-1)/2n), already about 1 in 2 for 10 hosts and 3 in 5 for 11
+// with the default 6 node bits. Prefer postgres.NextNode or localnode when
+// duplicates would be costly.
+func HashNode(s string, bits uint8) (int64, error) {
+	if bits == 0 || bits > NodeBits {
+		return 0, fmt.Errorf("usid: node hash bits must be between 1 and %d", NodeBits)
+	}
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return int64(h.Sum64()%(1<<bits-1)) + 1, nil
+}
+
+// NodeRange is an inclusive range of node IDs. The zero value is no range.
+type NodeRange struct {
+	Min, Max int64
+}
+
+// IsZero reports whether r is the zero value, which contains no nodes.
+func (r NodeRange) IsZero() bool { return r == NodeRange{} }
+
+// Contains reports whether node is in the range.
+func (r NodeRange) Contains(node int64) bool { return !r.IsZero() && node >= r.Min && node <= r.Max }
+
+// Size returns the number of nodes in the range.
+func (r NodeRange) Size() int64 {
+	if r.IsZero() {
+		return 0
+	}
+	return r.Max - r.Min + 1
+}
+
+// Reserved node ranges, which keep coordinated and uncoordinated node
+// assignment apart. Each is unset by default; set them with Configure, which
+// checks that they fit in NodeBits and do not overlap, and mirror them in
+// postgres.Config so usid_next_node skips them.
+var (
+	// DBNodes holds the nodes of IDs minted inside the database, such as
+	// the Citus worker nodes of postgres.Config.CitusNodes. NewGenerator
+	// refuses them.
+	DBNodes NodeRange
+
+	// StaticNodes holds the nodes assigned by configuration. NodeFromEnv
+	// rejects nodes outside it.
+	StaticNodes NodeRange
+
+	// EphemeralNodes holds the nodes NewEphemeralGenerator picks from.
+	// NewGenerator refuses them, so only ephemeral jobs share them. Unset,
+	// NewEphemeralGenerator picks from every node but Postgres's node 0.
+	EphemeralNodes NodeRange
+)
+
+// nodeReserved reports whether NewGenerator refuses node.
+func nodeReserved(node int64) bool {
+	return DBNodes.Contains(node) || EphemeralNodes.Contains(node)
+}
+
+// NewEphemeralGenerator returns a Generator for a node picked at random from
+// EphemeralNodes, for short-lived jobs and CLIs that cannot coordinate, along
+// with the probability that at least two of concurrent such jobs pick the
+// same node. Two jobs sharing a node issue colliding IDs whenever they
+// generate in the same tick with the same sequence, so keep the probability
+// small or give the jobs RandBits. Panics if EphemeralNodes does not fit in
+// NodeBits.
+func NewEphemeralGenerator(concurrent int, opts ...Option) (*Generator, float64) {
+	r := EphemeralNodes
+	if r.IsZero() {
+		r = NodeRange{Min: 1, Max: CurrentConfig().MaxNode()}
+	}
+	if r.Min < 0 || r.Max > CurrentConfig().MaxNode() || r.Size() < 1 {
+		panic("usid: EphemeralNodes out of range")
+	}
+	g := newGenerator(r.Min+rand.Int64N(r.Size()), opts...)
+	// Birthday problem over the range, as in Config.CollisionProbability.
+	unique := 1.0
+	for i := 1; i < concurrent; i++ {
+		unique *= 1 - float64(i)/float64(r.Size())
+	}
+	return g, 1 - max(unique, 0)
+}
@@ -0,0 +1,140 @@
+package usid
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestNodeFromEnv(t *testing.T) {
+	t.Setenv("USID_TEST_NODE", "42")
+	if node, err := NodeFromEnv("USID_TEST_NODE"); err != nil || node != 42 {
+		t.Errorf("NodeFromEnv = %d, %v; want 42", node, err)
+	}
+	for _, bad := range []string{"", "x", "-1", "64"} {
+		t.Setenv("USID_TEST_NODE", bad)
+		if _, err := NodeFromEnv("USID_TEST_NODE"); err == nil {
+			t.Errorf("NodeFromEnv(%q): want err != nil", bad)
+		}
+	}
+	if _, err := NodeFromEnv("USID_TEST_UNSET"); err == nil {
+		t.Error("NodeFromEnv(unset): want err != nil")
+	}
+
+	defer func() { StaticNodes = NodeRange{} }()
+	StaticNodes = NodeRange{Min: 1, Max: 15}
+	t.Setenv("USID_TEST_NODE", "16")
+	if _, err := NodeFromEnv("USID_TEST_NODE"); err == nil {
+		t.Error("NodeFromEnv outside StaticNodes: want err != nil")
+	}
+}
+
+func TestReservedNodes(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.DBNodes = NodeRange{Min: 56, Max: 63}
+	cfg.StaticNodes = NodeRange{Min: 1, Max: 15}
+	cfg.EphemeralNodes = NodeRange{Min: 40, Max: 55}
+	if err := Configure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	defer Configure(DefaultConfig())
+	if got := CurrentConfig(); got != cfg {
+		t.Errorf("CurrentConfig() = %+v, want %+v", got, cfg)
+	}
+	for _, node := range []int64{40, 55, 56, 63} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewGenerator(%d): want panic for reserved node", node)
+				}
+			}()
+			NewGenerator(node)
+		}()
+	}
+	proxy := NewGenerator(20)
+	for _, node := range []int64{40, 56} {
+		if _, err := proxy.GenerateForNode(node); !errors.Is(err, ErrNodeReserved) {
+			t.Errorf("GenerateForNode(%d) error = %v, want ErrNodeReserved", node, err)
+		}
+	}
+	if _, err := proxy.GenerateForNode(21); err != nil {
+		t.Errorf("GenerateForNode(21) = %v", err)
+	}
+	if g, _ := NewEphemeralGenerator(1); !EphemeralNodes.Contains(g.Generate().Node()) {
+		t.Error("NewEphemeralGenerator picked a node outside EphemeralNodes")
+	}
+
+	for _, bad := range []Config{
+		{NodeBits: 6, SeqBits: 6, DBNodes: NodeRange{Min: 56, Max: 64}},
+		{NodeBits: 6, SeqBits: 6, StaticNodes: NodeRange{Min: 10, Max: 5}},
+		{NodeBits: 6, SeqBits: 6, StaticNodes: NodeRange{Min: 1, Max: 40}, EphemeralNodes: NodeRange{Min: 40, Max: 55}},
+		{NodeBits: 6, SeqBits: 6, DBNodes: NodeRange{Min: 50, Max: 63}, EphemeralNodes: NodeRange{Min: 40, Max: 55}},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Validate(%+v): want err != nil", bad)
+		}
+	}
+}
+
+func TestHashNode(t *testing.T) {
+	seen := map[int64]bool{}
+	for _, host := range []string{"web-1", "web-2", "web-3", "worker-a", "worker-b"} {
+		node, err := HashNode(host, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if node < 1 || node > 15 {
+			t.Errorf("HashNode(%q, 4) = %d, want in [1, 15]", host, node)
+		}
+		if again, _ := HashNode(host, 4); again != node {
+			t.Errorf("HashNode(%q) not stable: %d then %d", host, node, again)
+		}
+		seen[node] = true
+	}
+	if len(seen) < 2 {
+		t.Error("HashNode mapped every host to the same node")
+	}
+	for _, bits := range []uint8{0, NodeBits + 1} {
+		if _, err := HashNode("web-1", bits); err == nil {
+			t.Errorf("HashNode(bits=%d): want err != nil", bits)
+		}
+	}
+	if _, err := NodeFromHostname(NodeBits); err != nil {
+		t.Errorf("NodeFromHostname: %v", err)
+	}
+}
+
+func TestNewEphemeralGenerator(t *testing.T) {
+	defer func() { EphemeralNodes = NodeRange{} }()
+	EphemeralNodes = NodeRange{Min: 48, Max: 63}
+	seen := map[int64]bool{}
+	for range 200 {
+		g, p := NewEphemeralGenerator(4)
+		node := g.Generate().Node()
+		if !EphemeralNodes.Contains(node) {
+			t.Fatalf("node %d outside %+v", node, EphemeralNodes)
+		}
+		seen[node] = true
+		// 1 - (15/16)(14/16)(13/16)
+		if want := 1 - 15.0*14*13/(16*16*16); math.Abs(p-want) > 1e-9 {
+			t.Errorf("collision probability = %v, want %v", p, want)
+		}
+	}
+	if len(seen) < 8 {
+		t.Errorf("200 generators used only %d of 16 nodes", len(seen))
+	}
+	if _, p := NewEphemeralGenerator(17); p != 1 {
+		t.Errorf("collision probability of 17 jobs on 16 nodes = %v, want 1", p)
+	}
+	if _, p := NewEphemeralGenerator(1); p != 0 {
+		t.Errorf("collision probability of one job = %v, want 0", p)
+	}
+
+	EphemeralNodes = NodeRange{Min: 60, Max: 64}
+	defer func() {
+		if recover() == nil {
+			t.Error("EphemeralNodes past MaxNode: want panic")
+		}
+	}()
+	NewEphemeralGenerator(1)
+}
@@ -0,0 +1,140 @@
+package usid
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+)
+
+// Null is a nullable ID of any type whose underlying type is int64, such as
+// the typed IDs generated by cmd/usidgen, so nullable columns need no wrapper
+// type per entity. It implements the same interfaces as NullID, deferring to
+// T's own Value, Scan, JSON, and text methods when T has them (for example,
+// prefixed string forms) and to ID's otherwise.
+//
+//	var parent usid.Null[models.UserID]
+//	row.Scan(&parent)
+type Null[T ~int64] struct {
+	ID    T
+	Valid bool
+}
+
+// Compile-time interface checks for Null
+var (
+	_ driver.Valuer            = Null[ID]{}
+	_ sql.Scanner              = (*Null[ID])(nil)
+	_ json.Marshaler           = Null[ID]{}
+	_ json.Unmarshaler         = (*Null[ID])(nil)
+	_ encoding.TextMarshaler   = Null[ID]{}
+	_ encoding.TextUnmarshaler = (*Null[ID])(nil)
+)
+
+// NullFrom returns a valid Null holding id.
+func NullFrom[T ~int64](id T) Null[T] {
+	return Null[T]{ID: id, Valid: true}
+}
+
+// IsZero reports whether n is NULL or holds the zero ID.
+func (n Null[T]) IsZero() bool {
+	return !n.Valid || n.ID == 0
+}
+
+// Value implements the driver.Valuer interface.
+func (n Null[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	if v, ok := any(n.ID).(driver.Valuer); ok {
+		return v.Value()
+	}
+	return ID(n.ID).Value()
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *Null[T]) Scan(src any) error {
+	if src == nil {
+		n.ID, n.Valid = 0, false
+		return nil
+	}
+	if s, ok := any(&n.ID).(sql.Scanner); ok {
+		err := s.Scan(src)
+		n.Valid = err == nil
+		return err
+	}
+	var id ID
+	err := id.Scan(src)
+	n.ID, n.Valid = T(id), err == nil
+	return err
+}
+
+// MarshalJSON marshals n as null or as T's JSON form.
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return nullJSON, nil
+	}
+	switch v := any(n.ID).(type) {
+	case json.Marshaler:
+		return v.MarshalJSON()
+	case encoding.TextMarshaler:
+		b, err := v.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(string(b))
+	}
+	return ID(n.ID).MarshalJSON()
+}
+
+// UnmarshalJSON unmarshals null or T's JSON form.
+func (n *Null[T]) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		n.ID, n.Valid = 0, false
+		return nil
+	}
+	var err error
+	switch v := any(&n.ID).(type) {
+	case json.Unmarshaler:
+		err = v.UnmarshalJSON(b)
+	case encoding.TextUnmarshaler:
+		var s string
+		if err = json.Unmarshal(b, &s); err == nil {
+			err = v.UnmarshalText([]byte(s))
+		}
+	default:
+		var id ID
+		err = id.UnmarshalJSON(b)
+		n.ID = T(id)
+	}
+	n.Valid = err == nil
+	return err
+}
+
+// MarshalText implements encoding.TextMarshaler. NULL marshals as empty text.
+func (n Null[T]) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	if v, ok := any(n.ID).(encoding.TextMarshaler); ok {
+		return v.MarshalText()
+	}
+	return ID(n.ID).MarshalText()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Empty text is NULL.
+func (n *Null[T]) UnmarshalText(b []byte) error {
+	if len(b) == 0 {
+		n.ID, n.Valid = 0, false
+		return nil
+	}
+	var err error
+	if v, ok := any(&n.ID).(encoding.TextUnmarshaler); ok {
+		err = v.UnmarshalText(b)
+	} else {
+		var id ID
+		err = id.UnmarshalText(b)
+		n.ID = T(id)
+	}
+	n.Valid = err == nil
+	return err
+}
@@ -0,0 +1,103 @@
+package usid
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// plainID is a typed ID without methods of its own.
+type plainID int64
+
+// prefixedID is a typed ID with a prefixed text form, like those usidgen
+// generates.
+type prefixedID ID
+
+func (id prefixedID) MarshalText() ([]byte, error) { return []byte("pre_" + ID(id).String()), nil }
+
+func (id *prefixedID) UnmarshalText(b []byte) error {
+	rest, ok := strings.CutPrefix(string(b), "pre_")
+	if !ok {
+		return errors.New("missing prefix")
+	}
+	parsed, err := Parse(rest)
+	*id = prefixedID(parsed)
+	return err
+}
+
+// labeledID is a typed ID whose text form needs JSON escaping.
+type labeledID ID
+
+func (id labeledID) MarshalText() ([]byte, error) {
+	return []byte("é\x01\x7f<" + ID(id).String()), nil
+}
+
+func TestNull(t *testing.T) {
+	id := ID(1234567890)
+
+	t.Run("JSON", func(t *testing.T) {
+		type row struct {
+			A Null[ID]
+			B Null[plainID]
+			C Null[prefixedID]
+			D Null[prefixedID]
+		}
+		in := row{NullFrom(id), NullFrom(plainID(id)), NullFrom(prefixedID(id)), Null[prefixedID]{}}
+		b, err := json.Marshal(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"A":"` + id.String() + `","B":"` + id.String() + `","C":"pre_` + id.String() + `","D":null}`
+		if string(b) != want {
+			t.Errorf("Marshal = %s, want %s", b, want)
+		}
+		var out row
+		if err := json.Unmarshal(b, &out); err != nil {
+			t.Fatal(err)
+		}
+		if out != in {
+			t.Errorf("roundtrip = %+v, want %+v", out, in)
+		}
+		if err := json.Unmarshal([]byte(`{"C":"`+id.String()+`"}`), &out); err == nil {
+			t.Error("Unmarshal accepted an unprefixed ID for prefixedID")
+		}
+	})
+
+	t.Run("SQL", func(t *testing.T) {
+		var n Null[plainID]
+		if err := n.Scan(int64(id)); err != nil || !n.Valid || n.ID != plainID(id) {
+			t.Errorf("Scan(int64) = %+v, %v", n, err)
+		}
+		if v, err := n.Value(); err != nil || v != int64(id) {
+			t.Errorf("Value() = %v, %v", v, err)
+		}
+		if err := n.Scan(nil); err != nil || n.Valid || !n.IsZero() {
+			t.Errorf("Scan(nil) = %+v, %v", n, err)
+		}
+		if v, err := n.Value(); err != nil || v != nil {
+			t.Errorf("Value() of NULL = %v, %v", v, err)
+		}
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		var n Null[prefixedID]
+		if err := n.UnmarshalText([]byte("pre_" + id.String())); err != nil || n.ID != prefixedID(id) {
+			t.Errorf("UnmarshalText = %+v, %v", n, err)
+		}
+		if b, _ := n.MarshalText(); string(b) != "pre_"+id.String() {
+			t.Errorf("MarshalText = %q", b)
+		}
+		if err := n.UnmarshalText(nil); err != nil || n.Valid {
+			t.Errorf("UnmarshalText(empty) = %+v, %v", n, err)
+		}
+	})
+
+	t.Run("JSONEscaping", func(t *testing.T) {
+		b, err := Null[labeledID]{ID: labeledID(id), Valid: true}.MarshalJSON()
+		var s string
+		if err != nil || !json.Valid(b) || json.Unmarshal(b, &s) != nil || s != "é\x01\x7f<"+id.String() {
+			t.Errorf("MarshalJSON = %s, %v, want a JSON string of the text form", b, err)
+		}
+	})
+}
@@ -23,7 +23,7 @@ func SetObfuscator(key int64) {
 	DefaultObfuscator = NewObfuscator(key)
 }
 
-// Obfuscate XORs the ID with the key.
+// Obfuscate XORs the ID with the key. It runs in constant time.
 func (o *Obfuscator) Obfuscate(id ID) ID {
 	return ID(int64(id) ^ o.key)
 }
@@ -37,7 +37,7 @@ func TestObfuscation(t *testing.T) {
 	DefaultObfuscator = NewObfuscator(key)
 
 	// Test all formats roundtrip
-	formats := []Format{FormatCrockford, FormatBase58, FormatDecimal, FormatHash, FormatBase64}
+	formats := []Format{FormatCrockford, FormatBase58, FormatBase62, FormatDecimal, FormatHash, FormatBase64, FormatBase64URL}
 	for _, f := range formats {
 		DefaultFormat = f
 		s := id.Format(f)
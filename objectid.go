@@ -0,0 +1,58 @@
+package usid
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+)
+
+// ErrObjectID is returned when converting an ObjectID that was not produced
+// by ID.ObjectID. Ordinary MongoDB ObjectIDs carry a random process value and
+// counter instead of an ID, so that direction cannot be recovered.
+var ErrObjectID = errors.New("usid: ObjectID was not derived from a usid.ID")
+
+// ObjectID returns a 12-byte value laid out like a MongoDB ObjectID, for
+// schemas that insist on ObjectID fields: the ID's Unix timestamp in seconds
+// (big-endian, as MongoDB expects) followed by the 8 raw ID bytes. Converting
+// it to primitive.ObjectID or bson.ObjectID is a plain type conversion.
+//
+// The mapping is deterministic and FromObjectID reverses it, and ObjectIDs of
+// IDs sort like the IDs themselves. Timestamps after 2106 wrap, as they do
+// for MongoDB.
+func (id ID) ObjectID() [12]byte {
+	var oid [12]byte
+	binary.BigEndian.PutUint32(oid[:4], uint32(id.Timestamp().Unix()))
+	binary.BigEndian.PutUint64(oid[4:], uint64(id))
+	return oid
+}
+
+// ObjectIDHex returns id.ObjectID() as 24 lowercase hex digits, the form
+// MongoDB tools display.
+func (id ID) ObjectIDHex() string {
+	oid := id.ObjectID()
+	return hex.EncodeToString(oid[:])
+}
+
+// FromObjectID returns the ID an ObjectID was derived from by ID.ObjectID.
+// It returns ErrObjectID if the timestamp does not match the embedded ID,
+// which is the case for ObjectIDs generated by MongoDB or a driver, or if
+// the ID was created under a different Epoch or layout.
+func FromObjectID(oid [12]byte) (ID, error) {
+	id := ID(binary.BigEndian.Uint64(oid[4:]))
+	if id.ObjectID() != oid {
+		return Nil, ErrObjectID
+	}
+	return id, nil
+}
+
+// ParseObjectIDHex parses the output of ID.ObjectIDHex. See FromObjectID.
+func ParseObjectIDHex(s string) (ID, error) {
+	var oid [12]byte
+	if len(s) != 2*len(oid) {
+		return Nil, ErrObjectID
+	}
+	if _, err := hex.Decode(oid[:], []byte(s)); err != nil {
+		return Nil, ErrObjectID
+	}
+	return FromObjectID(oid)
+}
@@ -0,0 +1,38 @@
+package usid
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestObjectID(t *testing.T) {
+	g := NewGenerator(1)
+	a := g.Generate()
+	b := g.Generate()
+
+	oid := a.ObjectID()
+	if secs := int64(oid[0])<<24 | int64(oid[1])<<16 | int64(oid[2])<<8 | int64(oid[3]); secs != a.Timestamp().Unix() {
+		t.Errorf("ObjectID timestamp = %d, want %d", secs, a.Timestamp().Unix())
+	}
+	if bo := b.ObjectID(); bytes.Compare(oid[:], bo[:]) >= 0 {
+		t.Errorf("ObjectID(%d) = %x does not sort before ObjectID(%d) = %x", a, oid, b, bo)
+	}
+	if got, err := FromObjectID(oid); err != nil || got != a {
+		t.Errorf("FromObjectID(%x) = %d, %v; want %d", oid, got, err, a)
+	}
+	if got, err := ParseObjectIDHex(a.ObjectIDHex()); err != nil || got != a {
+		t.Errorf("ParseObjectIDHex(%s) = %d, %v; want %d", a.ObjectIDHex(), got, err, a)
+	}
+
+	// An ObjectID as generated by MongoDB: timestamp, random value, counter.
+	mongo := [12]byte{0x66, 0xf1, 0xc2, 0xa9, 0x5f, 0x1c, 0x2a, 0x91, 0x0e, 0x00, 0x00, 0x01}
+	for _, s := range []string{"", "zz" + a.ObjectIDHex()[2:], "66f1c2a9e4b0a1b2c3d4e5f6"} {
+		if _, err := ParseObjectIDHex(s); !errors.Is(err, ErrObjectID) {
+			t.Errorf("ParseObjectIDHex(%q): err = %v, want ErrObjectID", s, err)
+		}
+	}
+	if _, err := FromObjectID(mongo); !errors.Is(err, ErrObjectID) {
+		t.Errorf("FromObjectID(%x): err = %v, want ErrObjectID", mongo, err)
+	}
+}
@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// auditSampleSize caps the number of duplicate IDs listed in an AuditReport.
+const auditSampleSize = 100
+
+// AuditReport describes problems found by Audit.
+type AuditReport struct {
+	Tables []TableAudit
+
+	// Duplicates is the number of distinct IDs present in more than one of
+	// the audited tables. Tables that deliberately share IDs, such as 1:1
+	// extension tables, should be audited separately.
+	Duplicates int64
+
+	// DuplicateSample lists up to 100 of the duplicated IDs, ascending.
+	DuplicateSample []int64
+
+	// MaxNode is the last node handed out by usid_next_node. After the node
+	// sequence wraps around, nodes above it were handed out too and
+	// TableAudit.UnknownNode overcounts.
+	MaxNode int64
+}
+
+// TableAudit holds the per-table results of Audit.
+type TableAudit struct {
+	Table  string // quoted, schema-qualified if outside the search path
+	Column string // quoted primary-key column
+	Rows   int64
+
+	// BeforeEpoch counts negative IDs, whose timestamps precede the epoch.
+	BeforeEpoch int64
+
+	// UnknownNode counts IDs whose node was never handed out by
+	// usid_next_node. Node 0 (IDs generated by usid() in the database) and
+	// the nodes reserved by Config.StaticNodes and Config.EphemeralNodes are
+	// always allowed.
+	UnknownNode int64
+}
+
+// OK reports whether the audit found no problems.
+func (r AuditReport) OK() bool {
+	if r.Duplicates > 0 {
+		return false
+	}
+	for _, t := range r.Tables {
+		if t.BeforeEpoch > 0 || t.UnknownNode > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Audit scans the USID primary keys of the given tables for IDs shared
+// between tables, IDs from nodes that were never handed out, and timestamps
+// before the epoch, as left behind when two instances ran with the same node
+// or with a mismatched layout. Tables are named as in SQL ("orders" or
+// "billing.invoices") and must have a single-column bigint or usid primary
+// key. The nil and omni sentinels are ignored.
+//
+// Audit reads every row of every table; run it against a replica.
+func Audit(ctx context.Context, db DB, tables ...string) (AuditReport, error) {
+	var report AuditReport
+	if len(tables) == 0 {
+		return report, errors.New("usid: audit needs at least one table")
+	}
+	cfg, err := GetConfig(ctx, db)
+	if err != nil {
+		return report, fmt.Errorf("usid: read config: %w", err)
+	}
+
+	if report.MaxNode, err = lastNode(ctx, db); err != nil {
+		return report, err
+	}
+
+	selects := make([]string, len(tables))
+	for i, table := range tables {
+		t, err := auditTable(ctx, db, table, cfg, report.MaxNode)
+		if err != nil {
+			return report, err
+		}
+		report.Tables = append(report.Tables, t)
+		selects[i] = fmt.Sprintf("SELECT %s AS id FROM %s", t.Column, t.Table)
+	}
+
+	if len(tables) > 1 {
+		var sample string
+		err = db.QueryRowContext(ctx, fmt.Sprintf(`
+			WITH ids AS (%s),
+			dups AS (SELECT id FROM ids WHERE id <> 0 AND id <> 9223372036854775807 GROUP BY id HAVING count(*) > 1)
+			SELECT count(*), coalesce((SELECT string_agg(id::text, ',' ORDER BY id) FROM (SELECT id FROM dups ORDER BY id LIMIT %d) s), '')
+			FROM dups`, strings.Join(selects, " UNION ALL "), auditSampleSize)).Scan(&report.Duplicates, &sample)
+		if err != nil {
+			return report, fmt.Errorf("usid: audit duplicates: %w", err)
+		}
+		for s := range strings.SplitSeq(sample, ",") {
+			if s == "" {
+				continue
+			}
+			id, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return report, fmt.Errorf("usid: audit duplicates: %w", err)
+			}
+			report.DuplicateSample = append(report.DuplicateSample, id)
+		}
+	}
+	return report, nil
+}
+
+// lastNode returns the last node handed out by usid_next_node, or 0 if none
+// has been.
+func lastNode(ctx context.Context, db DB) (int64, error) {
+	var node int64
+	err := db.QueryRowContext(ctx, `SELECT CASE WHEN is_called THEN last_value ELSE 0 END FROM usid_node_seq`).Scan(&node)
+	if err != nil {
+		return 0, fmt.Errorf("usid: read node sequence: %w", err)
+	}
+	return node, nil
+}
+
+// auditTable resolves table's primary key and counts its invalid IDs.
+func auditTable(ctx context.Context, db DB, table string, cfg Config, maxNode int64) (TableAudit, error) {
+	t := TableAudit{}
+	var typ string
+	var keys int
+	err := db.QueryRowContext(ctx, `
+		SELECT $1::regclass::text, coalesce(quote_ident(min(a.attname)), ''), coalesce(min(format_type(a.atttypid, NULL)), ''), count(*)
+		FROM pg_index i
+		JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+		WHERE i.indrelid = $1::regclass AND i.indisprimary`, table).Scan(&t.Table, &t.Column, &typ, &keys)
+	if err != nil {
+		return t, fmt.Errorf("usid: audit %s: %w", table, err)
+	}
+	if keys != 1 || (typ != "bigint" && typ != "usid") {
+		return t, fmt.Errorf("usid: audit %s: primary key is not a single bigint column", table)
+	}
+
+	err = db.QueryRowContext(ctx, fmt.Sprintf(`
+		SELECT count(*),
+			count(*) FILTER (WHERE %[1]s < 0),
+			count(*) FILTER (WHERE %[1]s > 0 AND %[1]s <> 9223372036854775807 AND (%[1]s >> %[3]d) & %[4]d BETWEEN %[5]d + 1 AND %[6]d
+				AND %[7]s)
+		FROM %[2]s`, t.Column, t.Table, cfg.NodeShift(), cfg.NodeMask(), maxNode, cfg.MaxAppNode(),
+		notReserved(cfg, fmt.Sprintf("(%s >> %d) & %d", t.Column, cfg.NodeShift(), cfg.NodeMask())))).
+		Scan(&t.Rows, &t.BeforeEpoch, &t.UnknownNode)
+	if err != nil {
+		return t, fmt.Errorf("usid: audit %s: %w", table, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"sync/atomic"
+)
+
+// Block is a range of IDs reserved by ReserveBlock for exclusive use by one
+// process. It covers every sequence value of a dedicated block node for a
+// window of consecutive ticks, so its IDs never collide with usid() (node 0),
+// with instances using nodes from usid_next_node, or with other blocks.
+type Block struct {
+	Node  int64 // node the block was carved from
+	First int64 // first ID in the block
+	Last  int64 // last ID in the block, inclusive
+	Size  int64 // number of IDs in the block, at least the number requested
+
+	seqBits   uint8
+	nodeShift uint8
+	timeShift uint8
+	next      atomic.Int64
+}
+
+// ID returns the i-th ID of the block, for 0 <= i < Size. IDs increase with i.
+func (b *Block) ID(i int64) int64 {
+	perTick := int64(1) << b.seqBits
+	tick := b.First>>b.timeShift + i/perTick
+	return tick<<b.timeShift | b.Node<<b.nodeShift | i%perTick
+}
+
+// Next returns the next unused ID of the block, or false once the block is
+// used up. Safe for concurrent use.
+func (b *Block) Next() (int64, bool) {
+	i := b.next.Add(1) - 1
+	if i >= b.Size {
+		return 0, false
+	}
+	return b.ID(i), true
+}
+
+// All returns every ID of the block in increasing order, regardless of what
+// Next has handed out.
+func (b *Block) All() iter.Seq[int64] {
+	return func(yield func(int64) bool) {
+		for i := range b.Size {
+			if !yield(b.ID(i)) {
+				return
+			}
+		}
+	}
+}
+
+// ReserveBlock atomically reserves at least n IDs, for minting IDs offline
+// or faster than a Generator can. The block starts at the current tick, or
+// right after the previous block if that one runs ahead of the clock, so
+// heavy reservation yields timestamps in the future.
+//
+// The first call reserves a node with usid_next_node for all blocks.
+func ReserveBlock(ctx context.Context, db DB, n int64) (*Block, error) {
+	if n < 1 {
+		return nil, errors.New("usid: block size must be positive")
+	}
+	cfg, err := GetConfig(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("usid: read config: %w", err)
+	}
+	b := &Block{seqBits: cfg.SeqBits, nodeShift: cfg.NodeShift(), timeShift: cfg.TimeShift()}
+	err = db.QueryRowContext(ctx, `SELECT first_id, last_id FROM usid_reserve_block($1)`, n).Scan(&b.First, &b.Last)
+	if err != nil {
+		return nil, fmt.Errorf("usid: reserve block: %w", err)
+	}
+	b.Node = (b.First >> cfg.NodeShift()) & cfg.NodeMask()
+	b.Size = (b.Last>>b.timeShift - b.First>>b.timeShift + 1) << cfg.SeqBits
+	return b, nil
+}
+
+// blockSQL returns the table and function behind ReserveBlock.
+func blockSQL(cfg Config) string {
+	return fmt.Sprintf(`
+-- Block reservation
+CREATE TABLE IF NOT EXISTS _usid_block (
+  id int PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+  node int NOT NULL,
+  next_tick bigint NOT NULL
+);
+
+CREATE OR REPLACE FUNCTION usid_reserve_block(n bigint, OUT first_id bigint, OUT last_id bigint)
+  LANGUAGE plpgsql
+  VOLATILE
+  AS $$
+DECLARE
+  ticks bigint;
+  now_tick bigint;
+  start_tick bigint;
+  block_node bigint;
+BEGIN
+  IF n < 1 THEN
+    RAISE EXCEPTION 'usid_reserve_block: n must be positive';
+  END IF;
+  IF NOT EXISTS (SELECT 1 FROM _usid_block) THEN
+    INSERT INTO _usid_block (node, next_tick) VALUES (usid_next_node(), 0) ON CONFLICT (id) DO NOTHING;
+  END IF;
+  ticks := (n + %[1]d) >> %[2]d;
+  now_tick := ((extract(epoch FROM clock_timestamp()) * 1000000)::bigint - %[3]d) / %[4]d;
+  UPDATE _usid_block SET next_tick = GREATEST(next_tick, now_tick) + ticks
+    RETURNING node, next_tick - ticks INTO block_node, start_tick;
+  first_id := (start_tick << %[5]d) | (block_node << %[6]d);
+  last_id := ((start_tick + ticks - 1) << %[5]d) | (block_node << %[6]d) | %[1]d;
+END;
+$$;
+`,
+		cfg.MaxSeq(),          // 1: per-tick rounding and last seq
+		cfg.SeqBits,           // 2: ticks per block
+		cfg.Epoch,             // 3
+		cfg.PrecisionMicros(), // 4
+		cfg.TimeShift(),       // 5
+		cfg.NodeShift(),       // 6
+	)
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// MaxPagesPerRange is the largest pages_per_range PostgreSQL accepts for a
+// BRIN index.
+const MaxPagesPerRange = 131072
+
+// DefaultBRINSpan is the time one BRIN block range covers unless told
+// otherwise.
+const DefaultBRINSpan = time.Minute
+
+// PagesPerRange suggests the pages_per_range of a BRIN index on a USID column
+// of a table receiving rowsPerSecond rows of about rowsPerPage rows per heap
+// page: the pages filled in span, clamped to [1, MaxPagesPerRange]. USIDs grow
+// with insertion time, so in an append-only table each range then holds the
+// IDs of one span. A span around the narrowest time window typically queried
+// keeps the index small without scanning much more than needed.
+// Returns 128, PostgreSQL's default, if either rate is not positive.
+func PagesPerRange(rowsPerSecond, rowsPerPage float64, span time.Duration) int {
+	if rowsPerSecond <= 0 || rowsPerPage <= 0 {
+		return 128
+	}
+	if span <= 0 {
+		span = DefaultBRINSpan
+	}
+	pages := math.Ceil(rowsPerSecond * span.Seconds() / rowsPerPage)
+	return int(max(1, min(pages, MaxPagesPerRange)))
+}
+
+// CreateBRIN creates a BRIN index named <table>_<column>_brin on the USID
+// column of table, without blocking writes, and returns its pages_per_range.
+// The insert rate and row density are estimated from the table's statistics
+// and the timestamps of its smallest and largest IDs, as by the
+// usid_brin_pages_per_range SQL function; see PagesPerRange. A span of zero
+// means DefaultBRINSpan. Does nothing if an index of that name already
+// exists. Like CREATE INDEX CONCURRENTLY, it cannot run in a transaction.
+//
+// In SQL, SELECT usid_brin('orders', 'id') does the same, blocking writes
+// while it builds.
+func CreateBRIN(ctx context.Context, db DB, table, column string, span time.Duration) (int, error) {
+	if span <= 0 {
+		span = DefaultBRINSpan
+	}
+	var pages int
+	var stmt string
+	err := db.QueryRowContext(ctx, `
+		SELECT p, format('CREATE INDEX CONCURRENTLY IF NOT EXISTS %I ON %s USING brin (%I) WITH (pages_per_range = %s)',
+			c.relname || '_' || $2::name || '_brin', c.oid::regclass, $2::name, p)
+		FROM pg_class c, usid_brin_pages_per_range($1::regclass, $2::name, make_interval(secs => $3)) p
+		WHERE c.oid = $1::regclass`, table, column, span.Seconds()).Scan(&pages, &stmt)
+	if err != nil {
+		return 0, fmt.Errorf("usid: estimate BRIN pages per range for %s: %w", table, err)
+	}
+	if _, err := db.ExecContext(ctx, stmt); err != nil {
+		return 0, fmt.Errorf("usid: create BRIN index on %s: %w", table, err)
+	}
+	return pages, nil
+}
+
+// brinSQL returns usid_brin_pages_per_range and usid_brin.
+func brinSQL(cfg Config) string {
+	return fmt.Sprintf(`
+-- BRIN indexes: pages_per_range covering the pages filled in span
+CREATE OR REPLACE FUNCTION usid_brin_pages_per_range(tbl regclass, col name, span interval DEFAULT '1 minute')
+  RETURNS int
+  LANGUAGE plpgsql
+  STABLE
+  AS $$
+DECLARE
+  pages float8;
+  lo bigint;
+  hi bigint;
+  seconds float8;
+BEGIN
+  SELECT relpages INTO pages FROM pg_class WHERE oid = tbl;
+  EXECUTE format('SELECT min(%%1$I), max(%%1$I) FROM %%2$s WHERE %%1$I > 0 AND %%1$I <> 9223372036854775807', col, tbl)
+    INTO lo, hi;
+  seconds := ((hi >> %[1]d) - (lo >> %[1]d))::float8 * %[2]d / 1000000;
+  IF pages IS NULL OR pages <= 0 OR seconds IS NULL OR seconds <= 0 THEN
+    RETURN 128; -- no statistics or a single tick: Postgres' default
+  END IF;
+  RETURN greatest(1, least(%[3]d, ceil(pages / seconds * extract(epoch FROM span))))::int;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION usid_brin(tbl regclass, col name DEFAULT 'id', span interval DEFAULT '1 minute')
+  RETURNS int
+  LANGUAGE plpgsql
+  VOLATILE
+  AS $$
+DECLARE
+  p int := usid_brin_pages_per_range(tbl, col, span);
+BEGIN
+  EXECUTE format('CREATE INDEX IF NOT EXISTS %%I ON %%s USING brin (%%I) WITH (pages_per_range = %%s)',
+    (SELECT relname FROM pg_class WHERE oid = tbl) || '_' || col || '_brin', tbl, col, p);
+  RETURN p;
+END;
+$$;
+`, cfg.TimeShift(), cfg.PrecisionMicros(), MaxPagesPerRange)
+}
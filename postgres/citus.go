@@ -0,0 +1,53 @@
+package postgres
+
+import "fmt"
+
+// MaxAppNode returns the largest node usid_next_node hands out: MaxNode,
+// less the nodes reserved for Citus workers.
+func (c Config) MaxAppNode() int64 { return c.MaxNode() - c.CitusNodes }
+
+// usidNode returns the SQL expression for the node of IDs minted by usid().
+func usidNode(cfg Config) string {
+	if cfg.CitusNodes > 0 {
+		return "usid_citus_node()"
+	}
+	return "0"
+}
+
+// citusSQL returns the functions and distribution calls behind
+// Config.CitusNodes, or nothing if it is zero.
+func citusSQL(cfg Config) string {
+	if cfg.CitusNodes <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(`
+-- Citus: every node mints IDs with its own node, counted down from the top of
+-- the node space by group ID (the coordinator is group 0)
+ALTER SEQUENCE usid_node_seq MAXVALUE %[1]d;
+
+CREATE OR REPLACE FUNCTION usid_citus_node()
+  RETURNS bigint
+  LANGUAGE plpgsql
+  STABLE
+  AS $$
+DECLARE
+  g int;
+BEGIN
+  SELECT groupid INTO g FROM pg_dist_local_group;
+  IF g IS NULL OR g >= %[2]d THEN
+    RAISE EXCEPTION 'usid: Citus group %% is outside the %[2]d nodes reserved for usid()', g;
+  END IF;
+  RETURN %[3]d - g;
+END;
+$$;
+
+SELECT run_command_on_workers('CREATE SEQUENCE IF NOT EXISTS usid_seq CYCLE MAXVALUE %[4]d');
+SELECT create_distributed_function('usid_citus_node()');
+SELECT create_distributed_function('usid()');
+`,
+		cfg.MaxAppNode(), // 1: usid_node_seq MAXVALUE
+		cfg.CitusNodes,   // 2: reserved nodes
+		cfg.MaxNode(),    // 3: node of the coordinator
+		cfg.MaxSeq(),     // 4: usid_seq MAXVALUE on workers
+	)
+}
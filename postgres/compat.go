@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Difference is a layout setting on which two configurations disagree.
+type Difference struct {
+	Setting string // _usid_config column, such as "epoch"
+	A, B    int64
+}
+
+func (d Difference) String() string {
+	return fmt.Sprintf("%s %d != %d", d.Setting, d.A, d.B)
+}
+
+// Diff returns the settings on which c and other disagree that change how
+// IDs decode: the epoch, precision, and node, tenant, and sequence bits.
+func (c Config) Diff(other Config) []Difference {
+	var diff []Difference
+	add := func(setting string, a, b int64) {
+		if a != b {
+			diff = append(diff, Difference{Setting: setting, A: a, B: b})
+		}
+	}
+	add("epoch", c.Epoch, other.Epoch)
+	add("precision_us", c.PrecisionMicros(), other.PrecisionMicros())
+	add("node_bits", int64(c.NodeBits), int64(other.NodeBits))
+	add("tenant_bits", int64(c.TenantBits), int64(other.TenantBits))
+	add("seq_bits", int64(c.SeqBits), int64(other.SeqBits))
+	return diff
+}
+
+// IncompatibleError is returned by VerifyCompatible when two databases
+// decode IDs differently. It matches ErrConfigMismatch with errors.Is.
+type IncompatibleError struct {
+	Differences []Difference
+}
+
+func (e *IncompatibleError) Error() string {
+	parts := make([]string, len(e.Differences))
+	for i, d := range e.Differences {
+		parts[i] = d.String()
+	}
+	return ErrConfigMismatch.Error() + ": " + strings.Join(parts, ", ")
+}
+
+func (e *IncompatibleError) Unwrap() error { return ErrConfigMismatch }
+
+// VerifyCompatible compares the USID configuration of two migrated
+// databases, such as an OLTP database and the reporting database its IDs are
+// copied to, and returns an *IncompatibleError listing the differences if
+// IDs from one would decode to different timestamps or nodes in the other.
+func VerifyCompatible(ctx context.Context, a, b DB) error {
+	cfgA, err := GetConfig(ctx, a)
+	if err != nil {
+		return fmt.Errorf("usid: read config of first database: %w", err)
+	}
+	cfgB, err := GetConfig(ctx, b)
+	if err != nil {
+		return fmt.Errorf("usid: read config of second database: %w", err)
+	}
+	if diff := cfgA.Diff(cfgB); len(diff) > 0 {
+		return &IncompatibleError{Differences: diff}
+	}
+	return nil
+}
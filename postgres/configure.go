@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+// DBOption configures ConfigureFromDB.
+type DBOption func(*dbOptions)
+
+type dbOptions struct {
+	acquire bool
+	opts    []usid.Option
+}
+
+// WithAcquiredNode makes ConfigureFromDB take a node ID from
+// usid_next_node() and rebuild DefaultGenerator for it with opts, instead of
+// keeping DefaultGenerator's current node and options.
+func WithAcquiredNode(opts ...usid.Option) DBOption {
+	return func(o *dbOptions) {
+		o.acquire = true
+		o.opts = opts
+	}
+}
+
+// ConfigureFromDB reads the layout recorded by Migrate in db and applies it
+// with usid.Configure, so the Go side cannot disagree with the database about
+// the epoch, precision, or bit allocation. Settings the database does not
+// record (TimeBits and RandBits) are reset to zero; its reserved node ranges,
+// including the Citus worker nodes as DBNodes, carry over. It returns an
+// error if the current layout uses eras, which the database functions do not
+// decode.
+// It returns the rebuilt usid.DefaultGenerator. Call once at startup in place
+// of GetConfig, usid.Configure, and NextNode.
+func ConfigureFromDB(ctx context.Context, db DB, opts ...DBOption) (*usid.Generator, error) {
+	var o dbOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := checkNoEras(); err != nil {
+		return nil, err
+	}
+	pc, err := GetConfig(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("usid: read database config: %w", err)
+	}
+	cfg := usid.Config{
+		Epoch:          pc.Epoch,
+		Precision:      pc.Precision,
+		NodeBits:       pc.NodeBits,
+		SeqBits:        pc.SeqBits,
+		TenantBits:     pc.TenantBits,
+		StaticNodes:    pc.StaticNodes,
+		EphemeralNodes: pc.EphemeralNodes,
+	}
+	if pc.CitusNodes > 0 {
+		cfg.DBNodes = NodeRange{Min: pc.MaxAppNode() + 1, Max: pc.MaxNode()}
+	}
+	if err := usid.Configure(cfg); err != nil {
+		return nil, err
+	}
+	if o.acquire {
+		node, err := NextNode(ctx, db)
+		if err != nil {
+			return nil, fmt.Errorf("usid: acquire node: %w", err)
+		}
+		usid.DefaultGenerator = usid.NewGenerator(node, o.opts...)
+	}
+	return usid.DefaultGenerator, nil
+}
@@ -0,0 +1,43 @@
+package postgres
+
+import "fmt"
+
+// explainSQL returns usid_pretty and usid_explain, which break IDs down for
+// debugging in psql.
+func explainSQL(cfg Config) string {
+	ts := fmt.Sprintf(`to_char(to_timestamp(((id >> %d) * %d + %d)::numeric / 1000000) AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"')`,
+		cfg.TimeShift(), cfg.PrecisionMicros(), cfg.Epoch)
+	node := fmt.Sprintf("(id >> %d) & %d", cfg.NodeShift(), cfg.NodeMask())
+	tenant := fmt.Sprintf("(id >> %d) & %d", cfg.SeqBits, cfg.TenantMask())
+	seq := fmt.Sprintf("id & %d", cfg.SeqMask())
+
+	pretty := ts + " || ' node=' || (" + node + ")"
+	explain := "jsonb_build_object('timestamp', " + ts + ", 'node', " + node
+	if cfg.TenantBits > 0 {
+		pretty += " || ' tenant=' || (" + tenant + ")"
+		explain += ", 'tenant', " + tenant
+	}
+	pretty += " || ' seq=' || (" + seq + ")"
+	explain += ", 'seq', " + seq + `, 'raw', id, 'formats', jsonb_build_object(
+    'crockford', usid_to_crockford(id),
+    'base58', usid_to_b58(id),
+    'base64', usid_to_b64(id),
+    'hash', usid_to_hex(id),
+    'decimal', id::text))`
+
+	return fmt.Sprintf(`
+-- Debugging: usid_pretty(id) is like "2025-12-16T10:11:12.345678Z node=3 seq=17",
+-- usid_explain(id) has every component and encoding, as ID.Components
+CREATE OR REPLACE FUNCTION usid_pretty(id bigint)
+  RETURNS text
+  LANGUAGE sql
+  STABLE PARALLEL SAFE STRICT
+  %s
+
+CREATE OR REPLACE FUNCTION usid_explain(id bigint)
+  RETURNS jsonb
+  LANGUAGE sql
+  STABLE PARALLEL SAFE STRICT
+  %s
+`, sqlBody(cfg, pretty), sqlBody(cfg, explain))
+}
@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+)
+
+// functions are the signatures of every function created by GenerateSQL,
+// except usid_citus_node.
+var functions = []string{
+	"usid_next_node()",
+	"usid()",
+	"omni_usid()",
+	"nil_usid()",
+	"is_omni_usid(bigint)",
+	"is_nil_usid(bigint)",
+	"usid_is_special(bigint)",
+	"usid_clamp(bigint)",
+	"ts_from_usid(bigint)",
+	"node_from_usid(bigint)",
+	"tenant_from_usid(bigint)",
+	"seq_from_usid(bigint)",
+	"crockford_to_usid(text)",
+	"usid_to_crockford(bigint)",
+	"b58_to_usid(varchar)",
+	"usid_to_b58(bigint)",
+	"b64_to_usid(varchar)",
+	"usid_to_b64(bigint)",
+	"hex_to_usid(text)",
+	"usid_to_hex(bigint)",
+	"usid_pretty(bigint)",
+	"usid_explain(bigint)",
+	"usid_reserve_block(bigint)",
+	"usid_brin_pages_per_range(regclass, name, interval)",
+	"usid_brin(regclass, name, interval)",
+}
+
+// definerFunctions are the functions that touch sequences or tables, and
+// so run as their owner with Config.SecurityDefiner.
+var definerFunctions = []string{
+	"usid()",
+	"usid_next_node()",
+	"usid_reserve_block(bigint)",
+}
+
+// grantSQL returns the statements applying Config.SecurityDefiner and
+// Config.Grants.
+func grantSQL(cfg Config) string {
+	var b strings.Builder
+	if cfg.SecurityDefiner {
+		b.WriteString("\n-- Run as the owner, with the search path of the migration\n")
+		for _, fn := range definerFunctions {
+			fmt.Fprintf(&b, "ALTER FUNCTION %s SECURITY DEFINER SET search_path FROM CURRENT;\n", fn)
+		}
+		// Functions are executable by PUBLIC by default, which would let any
+		// role run them with the owner's privileges
+		fmt.Fprintf(&b, "REVOKE EXECUTE ON FUNCTION %s FROM PUBLIC;\n", strings.Join(definerFunctions, ", "))
+	}
+	var roles []string
+	for role := range strings.SplitSeq(cfg.Grants, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, quoteRole(role))
+		}
+	}
+	if len(roles) == 0 {
+		return b.String()
+	}
+	to := strings.Join(roles, ", ")
+	fns := functions
+	if cfg.CitusNodes > 0 {
+		fns = append(fns[:len(fns):len(fns)], "usid_citus_node()")
+	}
+	b.WriteString("\n-- Grants\n")
+	fmt.Fprintf(&b, "GRANT EXECUTE ON FUNCTION %s TO %s;\n", strings.Join(fns, ", "), to)
+	if !cfg.SecurityDefiner {
+		fmt.Fprintf(&b, "GRANT USAGE ON SEQUENCE usid_seq, usid_node_seq TO %s;\n", to)
+		fmt.Fprintf(&b, "GRANT SELECT, INSERT, UPDATE ON TABLE _usid_block TO %s;\n", to)
+	}
+	fmt.Fprintf(&b, "GRANT SELECT, INSERT, UPDATE ON TABLE _usid_watermark TO %s;\n", to)
+	if cfg.CreateDomain {
+		fmt.Fprintf(&b, "GRANT USAGE ON DOMAIN usid TO %s;\n", to)
+	}
+	return b.String()
+}
+
+// quoteRole quotes a role name for a GRANT statement. PUBLIC is a keyword,
+// not a role, and is left unquoted.
+func quoteRole(role string) string {
+	if strings.EqualFold(role, "public") {
+		return "PUBLIC"
+	}
+	return `"` + strings.ReplaceAll(role, `"`, `""`) + `"`
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+// NodeRange is an inclusive range of node IDs, shared with the usid package.
+// The zero value is no range.
+type NodeRange = usid.NodeRange
+
+// reservedRanges returns the set ranges of StaticNodes and EphemeralNodes.
+func (c Config) reservedRanges() []NodeRange {
+	var rs []NodeRange
+	for _, r := range []NodeRange{c.StaticNodes, c.EphemeralNodes} {
+		if !r.IsZero() {
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
+// validateNodes checks that StaticNodes and EphemeralNodes lie within the
+// nodes usid_next_node could hand out, do not overlap, and leave it at least
+// one node.
+func (c Config) validateNodes() error {
+	if c.CitusNodes < 0 || c.CitusNodes >= c.MaxNode() {
+		return fmt.Errorf("usid: %d Citus nodes leave no node for applications", c.CitusNodes)
+	}
+	free := c.MaxAppNode()
+	for _, r := range c.reservedRanges() {
+		if r.Min < 1 || r.Min > r.Max || r.Max > c.MaxAppNode() {
+			return fmt.Errorf("usid: reserved nodes %d-%d outside the application nodes 1-%d", r.Min, r.Max, c.MaxAppNode())
+		}
+		free -= r.Size()
+	}
+	if s, e := c.StaticNodes, c.EphemeralNodes; !s.IsZero() && !e.IsZero() && s.Min <= e.Max && e.Min <= s.Max {
+		return fmt.Errorf("usid: static nodes %d-%d overlap ephemeral nodes %d-%d", s.Min, s.Max, e.Min, e.Max)
+	}
+	if free < 1 {
+		return fmt.Errorf("usid: reserved nodes leave no node for usid_next_node")
+	}
+	return nil
+}
+
+// notReserved returns a SQL condition that holds when expr is outside every
+// reserved range, or "true" if none are set.
+func notReserved(cfg Config, expr string) string {
+	conds := []string{"true"}
+	for _, r := range cfg.reservedRanges() {
+		conds = append(conds, fmt.Sprintf("%s NOT BETWEEN %d AND %d", expr, r.Min, r.Max))
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// nodeRangeSQL returns a usid_next_node that skips Config.StaticNodes and
+// Config.EphemeralNodes, or nothing if neither is set.
+func nodeRangeSQL(cfg Config) string {
+	if len(cfg.reservedRanges()) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(`
+-- Skip the nodes reserved for static assignment and ephemeral jobs
+CREATE OR REPLACE FUNCTION usid_next_node()
+  RETURNS int
+  LANGUAGE plpgsql
+  VOLATILE
+  AS $$
+DECLARE
+  node int;
+BEGIN
+  LOOP
+    node := nextval('usid_node_seq')::int;
+    EXIT WHEN %s;
+  END LOOP;
+  RETURN node;
+END;
+$$;
+`, notReserved(cfg, "node"))
+}
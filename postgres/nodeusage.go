@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// NodeUsage counts the IDs one node generated on one day.
+type NodeUsage struct {
+	Node int64
+	Day  time.Time // midnight UTC
+	IDs  int64
+
+	// Unknown reports that usid_next_node never handed out the node and no
+	// Config range reserves it, as counted by TableAudit.UnknownNode.
+	Unknown bool
+}
+
+// NodeUsageReport counts the IDs in column of table by node and UTC day,
+// ordered by node then day, so that nodes still generating after their
+// instances were decommissioned, or nodes nothing should hold, stand out.
+// Only IDs from the last window are counted; a window of zero counts every
+// row. The nil and omni sentinels and negative IDs are ignored.
+//
+// The window is a range scan on column, but every ID in it is read; run it
+// against a replica.
+func NodeUsageReport(ctx context.Context, db DB, table, column string, window time.Duration) ([]NodeUsage, error) {
+	cfg, err := GetConfig(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("usid: read config: %w", err)
+	}
+	maxNode, err := lastNode(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var from int64 = 1
+	if window > 0 {
+		if t := (time.Now().Add(-window).UnixMicro() - cfg.Epoch) / cfg.PrecisionMicros(); t > 0 {
+			from = t << cfg.TimeShift()
+		}
+	}
+	var rows []byte
+	err = db.QueryRowContext(ctx, fmt.Sprintf(`
+		WITH usage AS (
+			SELECT node_from_usid(%[1]s) AS node, ((%[1]s >> %[3]d) * %[4]d + %[5]d) / 86400000000 AS day, count(*) AS ids
+			FROM %[2]s
+			WHERE %[1]s >= $1 AND %[1]s <> 9223372036854775807
+			GROUP BY 1, 2
+		)
+		SELECT coalesce(json_agg(json_build_object('node', node, 'day', day, 'ids', ids) ORDER BY node, day), '[]')
+		FROM usage`, column, table, cfg.TimeShift(), cfg.PrecisionMicros(), cfg.Epoch), from).Scan(&rows)
+	if err != nil {
+		return nil, fmt.Errorf("usid: node usage of %s: %w", table, err)
+	}
+	var days []struct {
+		Node, Day, IDs int64
+	}
+	if err := json.Unmarshal(rows, &days); err != nil {
+		return nil, fmt.Errorf("usid: node usage of %s: %w", table, err)
+	}
+
+	usage := make([]NodeUsage, len(days))
+	for i, d := range days {
+		usage[i] = NodeUsage{
+			Node: d.Node,
+			Day:  time.UnixMicro(d.Day * 86400000000).UTC(),
+			IDs:  d.IDs,
+			Unknown: d.Node > maxNode && d.Node <= cfg.MaxAppNode() &&
+				!cfg.StaticNodes.Contains(d.Node) && !cfg.EphemeralNodes.Contains(d.Node),
+		}
+	}
+	return usage, nil
+}
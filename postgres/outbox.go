@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OutboxMessage is an event in a transactional outbox table.
+type OutboxMessage struct {
+	ID       int64           `json:"id"`       // USID; zero in InsertOutbox means usid()
+	Topic    string          `json:"topic"`    // destination, such as a Kafka topic
+	Payload  json.RawMessage `json:"payload"`  // event body
+	Attempts int             `json:"attempts"` // times the message has been claimed, including this one
+}
+
+// OutboxSQL returns the DDL for an outbox table keyed by USID. Apply it
+// after Migrate, which creates the usid() default. Acknowledged messages are
+// deleted, so pending messages sit at the start of the primary key index and
+// ClaimOutbox finds them in ID order, oldest first, without a separate index.
+//
+// Here and in the other outbox functions, table may be schema-qualified, as
+// in "app.outbox". Each part is quoted as an identifier, so it is matched
+// case-sensitively.
+func OutboxSQL(table string) string {
+	return fmt.Sprintf(`
+CREATE TABLE IF NOT EXISTS %s (
+  id bigint PRIMARY KEY DEFAULT usid(),
+  topic text NOT NULL,
+  payload jsonb NOT NULL,
+  attempts int NOT NULL DEFAULT 0,
+  claimed_until timestamptz
+);
+`, quoteTable(table))
+}
+
+// InsertOutbox adds msg to the outbox table and returns its ID. Run it in
+// the transaction that makes the change the event describes, so the event
+// is published if and only if the change commits. If msg.ID is zero, the ID
+// comes from usid().
+func InsertOutbox(ctx context.Context, db DB, table string, msg OutboxMessage) (int64, error) {
+	var id int64
+	err := db.QueryRowContext(ctx, fmt.Sprintf(`
+		INSERT INTO %s (id, topic, payload) VALUES (COALESCE(NULLIF($1, 0), usid()), $2, $3)
+		RETURNING id`, quoteTable(table)), msg.ID, msg.Topic, string(msg.Payload)).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("usid: insert outbox message: %w", err)
+	}
+	return id, nil
+}
+
+// ClaimOutbox claims up to limit unacknowledged messages, oldest first, for
+// lease. Claimed messages are skipped by other callers until the lease runs
+// out, after which they are handed out again, so a relay that crashes before
+// AckOutbox redelivers rather than loses them. Concurrent relays claim
+// disjoint messages without blocking each other.
+func ClaimOutbox(ctx context.Context, db DB, table string, limit int, lease time.Duration) ([]OutboxMessage, error) {
+	if limit < 1 {
+		return nil, errors.New("usid: outbox claim limit must be positive")
+	}
+	var claimed []byte
+	err := db.QueryRowContext(ctx, fmt.Sprintf(`
+		WITH claimed AS (
+			UPDATE %[1]s SET claimed_until = clock_timestamp() + $2 * interval '1 microsecond', attempts = attempts + 1
+			WHERE id IN (
+				SELECT id FROM %[1]s
+				WHERE claimed_until IS NULL OR claimed_until < clock_timestamp()
+				ORDER BY id LIMIT $1
+				FOR UPDATE SKIP LOCKED)
+			RETURNING id, topic, payload, attempts)
+		SELECT coalesce(json_agg(json_build_object('id', id, 'topic', topic, 'payload', payload, 'attempts', attempts) ORDER BY id), '[]')
+		FROM claimed`, quoteTable(table)), limit, lease.Microseconds()).Scan(&claimed)
+	if err != nil {
+		return nil, fmt.Errorf("usid: claim outbox messages: %w", err)
+	}
+	var msgs []OutboxMessage
+	if err := json.Unmarshal(claimed, &msgs); err != nil {
+		return nil, fmt.Errorf("usid: claim outbox messages: %w", err)
+	}
+	return msgs, nil
+}
+
+// AckOutbox deletes published messages from the outbox table.
+func AckOutbox(ctx context.Context, db DB, table string, ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	list := make([]string, len(ids))
+	for i, id := range ids {
+		list[i] = strconv.FormatInt(id, 10)
+	}
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE id = ANY($1::bigint[])`, quoteTable(table)),
+		"{"+strings.Join(list, ",")+"}")
+	if err != nil {
+		return fmt.Errorf("usid: ack outbox messages: %w", err)
+	}
+	return nil
+}
+
+// quoteTable quotes each part of a possibly schema-qualified table name.
+func quoteTable(table string) string {
+	parts := strings.Split(table, ".")
+	for i, part := range parts {
+		parts[i] = `"` + strings.ReplaceAll(part, `"`, `""`) + `"`
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,36 @@
+package postgres
+
+import "fmt"
+
+// NodeSetting is the custom setting usid() reads its node from when
+// Config.NodeOverride is set. Set it to a node number to mint IDs with that
+// node, or to NodeOff to make usid() raise an error instead of minting IDs:
+//
+//	ALTER ROLE replicator SET usid.node = 'off';
+//	SET usid.node = 12; -- this session only
+const NodeSetting = "usid.node"
+
+// NodeOff is the NodeSetting value that disables usid().
+const NodeOff = "off"
+
+// nodeOverrideSQL returns the statements at the start of usid() that apply
+// NodeSetting, or nothing without Config.NodeOverride.
+func nodeOverrideSQL(cfg Config) string {
+	if !cfg.NodeOverride {
+		return ""
+	}
+	return fmt.Sprintf(`
+  override := coalesce(current_setting('%[1]s', true), '');
+  IF override = '%[2]s' THEN
+    RAISE EXCEPTION 'usid: ID generation is disabled by %[1]s'
+      USING ERRCODE = 'object_not_in_prerequisite_state';
+  ELSIF override <> '' THEN
+    IF override ~ '^[0-9]{1,18}$' THEN
+      node := override::bigint;
+    END IF;
+    IF override !~ '^[0-9]{1,18}$' OR node > %[3]d THEN
+      RAISE EXCEPTION 'usid: %[1]s = %% is not a node between 0 and %[3]d', override
+        USING ERRCODE = 'invalid_parameter_value';
+    END IF;
+  END IF;`, NodeSetting, NodeOff, cfg.MaxNode())
+}
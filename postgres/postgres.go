@@ -8,6 +8,11 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
 )
 
 // DB is the interface for database operations.
@@ -19,30 +24,93 @@ type DB interface {
 }
 
 // Config holds the USID bit layout configuration for PostgreSQL migrations.
-// This must match the configuration used in the Go application.
+// This must match the configuration used in the Go application. The SQL
+// functions do not decode eras, so the application must leave usid.EraBits
+// at zero.
 type Config struct {
-	Epoch    int64 // Custom epoch in microseconds
-	NodeBits uint8 // Bits allocated for node ID
-	SeqBits  uint8 // Bits allocated for sequence number
+	Epoch      int64         // Custom epoch in microseconds
+	Precision  time.Duration // Timestamp resolution; zero means time.Microsecond
+	NodeBits   uint8         // Bits allocated for node ID
+	SeqBits    uint8         // Bits allocated for sequence number
+	TenantBits uint8         // Bits between node and sequence for a tenant (see usid.TenantBits)
+
+	// ServerVersion is the server_version_num the generated SQL targets, such
+	// as 160004 for PostgreSQL 16.4. Migrate fills it in from the server when
+	// zero; GenerateSQL then sticks to features of MinServerVersion.
+	ServerVersion int
 
 	// CreateDomain creates a `usid` domain type as an alias for bigint.
 	// This provides type safety in your schema but may require configuration
 	// in ORMs and code generators like sqlc.
 	CreateDomain bool
+
+	// Grants is a comma-separated list of roles, such as "anon, authenticated"
+	// on Supabase, granted EXECUTE on every usid function, write access to
+	// the watermark table, and USAGE on the usid sequences and write access
+	// to the block table unless SecurityDefiner is set, so restricted
+	// application roles can call usid() without manual GRANT statements after
+	// each deploy. "public" grants to every role. Roles removed from the list
+	// keep their privileges until revoked by hand.
+	Grants string
+
+	// CitusNodes reserves the top CitusNodes nodes for usid() on a Citus
+	// cluster, so it can run on worker nodes without IDs colliding across
+	// them: each node mints IDs with node MaxNode minus its Citus group ID,
+	// and the functions are distributed to the workers. usid_next_node then
+	// hands out nodes up to MaxAppNode only. Zero, the default, gives usid()
+	// node 0 everywhere.
+	CitusNodes int64
+
+	// NodeOverride makes usid() take its node from the NodeSetting custom
+	// setting when set, so triggers firing on a replica or during logical
+	// replication replay can use a distinct node, or refuse to mint IDs with
+	// NodeOff, instead of duplicating IDs minted on the primary.
+	NodeOverride bool
+
+	// StaticNodes and EphemeralNodes reserve nodes that usid_next_node skips:
+	// those assigned by configuration (see usid.StaticNodes) and those picked
+	// at random by short-lived jobs (see usid.EphemeralNodes). Each must lie
+	// within 1-MaxAppNode, and together they must leave usid_next_node at
+	// least one node. The zero NodeRange reserves nothing.
+	StaticNodes    NodeRange
+	EphemeralNodes NodeRange
+
+	// SecurityDefiner makes usid(), usid_next_node(), and usid_reserve_block()
+	// run with the privileges of their owner and the search path of the
+	// migration, so callers need no privileges on the underlying sequences
+	// and tables, as with row-level security setups that lock them down.
+	// EXECUTE on these functions is revoked from PUBLIC; list the roles
+	// that may call them in Grants.
+	SecurityDefiner bool
 }
 
 // DefaultConfig returns the default USID configuration.
 // Use this unless you've customized usid.Epoch, usid.NodeBits, or usid.SeqBits.
 func DefaultConfig() Config {
 	return Config{
-		Epoch:    1765947799213000, // 2025-12-16 in µs
-		NodeBits: 6,
-		SeqBits:  6,
+		Epoch:     1765947799213000, // 2025-12-16 in µs
+		Precision: time.Microsecond,
+		NodeBits:  6,
+		SeqBits:   6,
+	}
+}
+
+// PrecisionMicros returns the number of microseconds per timestamp tick.
+func (c Config) PrecisionMicros() int64 {
+	if c.Precision <= time.Microsecond {
+		return 1
 	}
+	return c.Precision.Microseconds()
 }
 
 // TimeShift returns the number of bits to shift for the timestamp component.
-func (c Config) TimeShift() uint8 { return c.NodeBits + c.SeqBits }
+func (c Config) TimeShift() uint8 { return c.NodeBits + c.TenantBits + c.SeqBits }
+
+// NodeShift returns the number of bits to shift for the node component.
+func (c Config) NodeShift() uint8 { return c.TenantBits + c.SeqBits }
+
+// TenantMask returns the bitmask for extracting the tenant.
+func (c Config) TenantMask() int64 { return (1 << c.TenantBits) - 1 }
 
 // MaxNode returns the maximum node ID value.
 func (c Config) MaxNode() int64 { return (1 << c.NodeBits) - 1 }
@@ -56,52 +124,107 @@ func (c Config) NodeMask() int64 { return c.MaxNode() }
 // SeqMask returns the bitmask for extracting the sequence number.
 func (c Config) SeqMask() int64 { return c.MaxSeq() }
 
+// MinServerVersion is the oldest PostgreSQL release Migrate supports, as a
+// server_version_num.
+const MinServerVersion = 130000
+
+// ErrUnsupportedVersion is returned by Migrate for servers older than
+// MinServerVersion.
+var ErrUnsupportedVersion = errors.New("usid: unsupported PostgreSQL version")
+
+// ServerVersion returns the server's version as a server_version_num, such as
+// 160004 for PostgreSQL 16.4.
+func ServerVersion(ctx context.Context, db DB) (int, error) {
+	var v int
+	err := db.QueryRowContext(ctx, `SELECT current_setting('server_version_num')::int`).Scan(&v)
+	return v, err
+}
+
 // ErrConfigMismatch is returned when the database has a different USID configuration
 // than the application is trying to use.
 var ErrConfigMismatch = errors.New("usid: database config does not match application config")
 
+// checkNoEras returns an error if the current usid layout uses eras, which the
+// SQL functions would decode as part of the timestamp.
+func checkNoEras() error {
+	if bits := usid.CurrentConfig().EraBits; bits > 0 {
+		return fmt.Errorf("usid: current layout uses %d era bits, which the database functions do not decode", bits)
+	}
+	return nil
+}
+
 // Migrate runs the idempotent USID migration.
 // If no config is provided, uses DefaultConfig().
 // If the database already has a different configuration, returns ErrConfigMismatch.
+// Servers older than MinServerVersion are rejected with ErrUnsupportedVersion
+// before anything is created, as is a current usid layout with eras.
 func Migrate(ctx context.Context, db DB, cfgs ...Config) error {
 	cfg := DefaultConfig()
 	if len(cfgs) > 0 {
 		cfg = cfgs[0]
 	}
+	if cfg.Precision == 0 {
+		cfg.Precision = time.Microsecond
+	}
+
+	if err := cfg.validateNodes(); err != nil {
+		return err
+	}
+	if err := checkNoEras(); err != nil {
+		return err
+	}
+
+	version, err := ServerVersion(ctx, db)
+	if err != nil {
+		return fmt.Errorf("usid: read server version: %w", err)
+	}
+	if version < MinServerVersion {
+		return fmt.Errorf("%w: server is %d, need at least %d", ErrUnsupportedVersion, version, MinServerVersion)
+	}
+	if cfg.ServerVersion == 0 {
+		cfg.ServerVersion = version
+	}
 
 	// Create config table
-	_, err := db.ExecContext(ctx, `
+	_, err = db.ExecContext(ctx, `
 		CREATE TABLE IF NOT EXISTS _usid_config (
 			id int PRIMARY KEY DEFAULT 1 CHECK (id = 1),
 			epoch bigint NOT NULL,
 			node_bits int NOT NULL,
 			seq_bits int NOT NULL
-		)
-	`)
+		);
+	`+addConfigColumns())
 	if err != nil {
 		return fmt.Errorf("usid: create config table: %w", err)
 	}
 
 	// Check existing config
-	var epoch int64
-	var nodeBits, seqBits int
-	err = db.QueryRowContext(ctx, `SELECT epoch, node_bits, seq_bits FROM _usid_config`).Scan(&epoch, &nodeBits, &seqBits)
+	existing, err := GetConfig(ctx, db)
 	if err == nil {
 		// Config exists, validate it matches
-		if epoch != cfg.Epoch || uint8(nodeBits) != cfg.NodeBits || uint8(seqBits) != cfg.SeqBits {
-			return fmt.Errorf("%w: db has epoch=%d node_bits=%d seq_bits=%d, app has epoch=%d node_bits=%d seq_bits=%d",
-				ErrConfigMismatch, epoch, nodeBits, seqBits, cfg.Epoch, cfg.NodeBits, cfg.SeqBits)
+		if existing.Epoch != cfg.Epoch || existing.Precision != cfg.Precision ||
+			existing.NodeBits != cfg.NodeBits || existing.SeqBits != cfg.SeqBits || existing.TenantBits != cfg.TenantBits {
+			return fmt.Errorf("%w: db has epoch=%d precision=%s node_bits=%d tenant_bits=%d seq_bits=%d, app has epoch=%d precision=%s node_bits=%d tenant_bits=%d seq_bits=%d",
+				ErrConfigMismatch, existing.Epoch, existing.Precision, existing.NodeBits, existing.TenantBits, existing.SeqBits,
+				cfg.Epoch, cfg.Precision, cfg.NodeBits, cfg.TenantBits, cfg.SeqBits)
 		}
 	} else if errors.Is(err, sql.ErrNoRows) {
 		// Insert config
-		_, err = db.ExecContext(ctx, `INSERT INTO _usid_config (epoch, node_bits, seq_bits) VALUES ($1, $2, $3)`,
-			cfg.Epoch, cfg.NodeBits, cfg.SeqBits)
+		_, err = db.ExecContext(ctx, `INSERT INTO _usid_config (epoch, node_bits, seq_bits, precision_us, tenant_bits) VALUES ($1, $2, $3, $4, $5)`,
+			cfg.Epoch, cfg.NodeBits, cfg.SeqBits, cfg.PrecisionMicros(), cfg.TenantBits)
 		if err != nil {
 			return fmt.Errorf("usid: insert config: %w", err)
 		}
 	} else {
 		return fmt.Errorf("usid: read config: %w", err)
 	}
+	if existing.CitusNodes != cfg.CitusNodes || existing.StaticNodes != cfg.StaticNodes || existing.EphemeralNodes != cfg.EphemeralNodes {
+		_, err = db.ExecContext(ctx, `UPDATE _usid_config SET citus_nodes = $1, static_min = $2, static_max = $3, ephemeral_min = $4, ephemeral_max = $5`,
+			cfg.CitusNodes, cfg.StaticNodes.Min, cfg.StaticNodes.Max, cfg.EphemeralNodes.Min, cfg.EphemeralNodes.Max)
+		if err != nil {
+			return fmt.Errorf("usid: update config: %w", err)
+		}
+	}
 
 	// Generate and run migrations with configured values
 	migrations := GenerateSQL(cfg)
@@ -121,16 +244,73 @@ func NextNode(ctx context.Context, db DB) (int64, error) {
 	return node, err
 }
 
-// GetConfig reads the USID configuration from the database.
+// Clock returns a function reporting the database server's current time,
+// for use as a usid.ClockReference with usid.CheckClock.
+func Clock(db DB) func(ctx context.Context) (time.Time, error) {
+	return func(ctx context.Context) (time.Time, error) {
+		var now time.Time
+		err := db.QueryRowContext(ctx, "SELECT clock_timestamp()").Scan(&now)
+		return now, err
+	}
+}
+
+// configColumns are the _usid_config columns added by later releases, in
+// the order GetConfig reads them, with their types and the values that
+// databases migrated before them imply.
+var configColumns = []struct{ name, typ, def string }{
+	{"precision_us", "bigint", "1"},
+	{"tenant_bits", "int", "0"},
+	{"citus_nodes", "bigint", "0"},
+	{"static_min", "bigint", "0"},
+	{"static_max", "bigint", "0"},
+	{"ephemeral_min", "bigint", "0"},
+	{"ephemeral_max", "bigint", "0"},
+}
+
+// addConfigColumns returns the statements adding configColumns to a
+// _usid_config table created by an earlier release.
+func addConfigColumns() string {
+	var b strings.Builder
+	for _, c := range configColumns {
+		fmt.Fprintf(&b, "ALTER TABLE _usid_config ADD COLUMN IF NOT EXISTS %s %s NOT NULL DEFAULT %s;\n", c.name, c.typ, c.def)
+	}
+	return b.String()
+}
+
+// GetConfig reads the USID configuration from the database. Columns that a
+// database migrated by an earlier release lacks read as their defaults until
+// the next Migrate adds them.
 func GetConfig(ctx context.Context, db DB) (Config, error) {
 	var cfg Config
-	var nodeBits, seqBits int
-	err := db.QueryRowContext(ctx, `SELECT epoch, node_bits, seq_bits FROM _usid_config`).Scan(&cfg.Epoch, &nodeBits, &seqBits)
+	var columns sql.NullString
+	err := db.QueryRowContext(ctx, `
+		SELECT string_agg(attname, ',') FROM pg_attribute
+		WHERE attrelid = to_regclass('_usid_config') AND attnum > 0 AND NOT attisdropped`).Scan(&columns)
+	if err != nil {
+		return cfg, err
+	}
+	present := strings.Split(columns.String, ",")
+	selects := []string{"epoch", "node_bits", "seq_bits"}
+	for _, c := range configColumns {
+		if slices.Contains(present, c.name) {
+			selects = append(selects, c.name)
+		} else {
+			selects = append(selects, c.def)
+		}
+	}
+
+	var nodeBits, seqBits, tenantBits int
+	var precisionUS int64
+	err = db.QueryRowContext(ctx, "SELECT "+strings.Join(selects, ", ")+" FROM _usid_config").
+		Scan(&cfg.Epoch, &nodeBits, &seqBits, &precisionUS, &tenantBits, &cfg.CitusNodes,
+			&cfg.StaticNodes.Min, &cfg.StaticNodes.Max, &cfg.EphemeralNodes.Min, &cfg.EphemeralNodes.Max)
 	if err != nil {
 		return cfg, err
 	}
+	cfg.Precision = time.Duration(precisionUS) * time.Microsecond
 	cfg.NodeBits = uint8(nodeBits)
 	cfg.SeqBits = uint8(seqBits)
+	cfg.TenantBits = uint8(tenantBits)
 	return cfg, nil
 }
 
@@ -138,7 +318,6 @@ func GetConfig(ctx context.Context, db DB) (Config, error) {
 // This is called by Migrate but can be used directly if you need the raw SQL.
 func GenerateSQL(cfg Config) string {
 	timeShift := cfg.TimeShift()
-	maxNode := cfg.MaxNode()
 	maxSeq := cfg.MaxSeq()
 	nodeMask := cfg.NodeMask()
 	seqMask := cfg.SeqMask()
@@ -169,7 +348,7 @@ CREATE OR REPLACE FUNCTION usid_next_node()
   SELECT nextval('usid_node_seq')::int;
 $$;
 
--- Generate usid (node 0 for Postgres)
+-- Generate usid (node 0 for Postgres, unless reserved for Citus)
 CREATE OR REPLACE FUNCTION usid()
   RETURNS bigint
   LANGUAGE plpgsql
@@ -177,12 +356,14 @@ CREATE OR REPLACE FUNCTION usid()
   AS $$
 DECLARE
   epoch bigint := %d;
-  now_us bigint;
+  ticks bigint;
   seq bigint;
-BEGIN
-  now_us := (extract(epoch FROM clock_timestamp()) * 1000000)::bigint - epoch;
+  node bigint := %s;
+  override text;
+BEGIN%s
+  ticks := ((extract(epoch FROM clock_timestamp()) * 1000000)::bigint - epoch) / %d;
   seq := nextval('usid_seq') & %d;
-  RETURN (now_us << %d) | (0 << %d) | seq;  -- node 0
+  RETURN (ticks << %d) | (node << %d) | seq;  -- tenant 0
 END;
 $$;
 
@@ -191,31 +372,35 @@ CREATE OR REPLACE FUNCTION omni_usid() RETURNS bigint LANGUAGE sql IMMUTABLE AS
 CREATE OR REPLACE FUNCTION nil_usid() RETURNS bigint LANGUAGE sql IMMUTABLE AS $$ SELECT 0::bigint; $$;
 CREATE OR REPLACE FUNCTION is_omni_usid(id bigint) RETURNS boolean LANGUAGE sql IMMUTABLE AS $$ SELECT id = 9223372036854775807; $$;
 CREATE OR REPLACE FUNCTION is_nil_usid(id bigint) RETURNS boolean LANGUAGE sql IMMUTABLE AS $$ SELECT id = 0; $$;
+-- Nil and omni are open-range sentinels: "from the beginning" and "until forever".
+-- usid_clamp maps negative bounds, which no usid() call produces, to nil.
+CREATE OR REPLACE FUNCTION usid_is_special(id bigint) RETURNS boolean LANGUAGE sql IMMUTABLE AS $$ SELECT id = 0 OR id = 9223372036854775807; $$;
+CREATE OR REPLACE FUNCTION usid_clamp(id bigint) RETURNS bigint LANGUAGE sql IMMUTABLE AS $$ SELECT GREATEST(id, 0); $$;
 
 -- Extract components
 CREATE OR REPLACE FUNCTION ts_from_usid(id bigint)
   RETURNS timestamp without time zone
   LANGUAGE sql
   IMMUTABLE PARALLEL SAFE STRICT LEAKPROOF
-  AS $$
-  SELECT to_timestamp(((id >> %d) + %d)::numeric / 1000000);
-$$;
+  %s
 
 CREATE OR REPLACE FUNCTION node_from_usid(id bigint)
   RETURNS int
   LANGUAGE sql
   IMMUTABLE PARALLEL SAFE STRICT LEAKPROOF
-  AS $$
-  SELECT ((id >> %d) & %d)::int;
-$$;
+  %s
+
+CREATE OR REPLACE FUNCTION tenant_from_usid(id bigint)
+  RETURNS int
+  LANGUAGE sql
+  IMMUTABLE PARALLEL SAFE STRICT LEAKPROOF
+  %s
 
 CREATE OR REPLACE FUNCTION seq_from_usid(id bigint)
   RETURNS int
   LANGUAGE sql
   IMMUTABLE PARALLEL SAFE STRICT LEAKPROOF
-  AS $$
-  SELECT (id & %d)::int;
-$$;
+  %s
 
 -- Crockford Base32 encoding/decoding
 CREATE OR REPLACE FUNCTION crockford_to_usid(encoded_id text)
@@ -266,7 +451,9 @@ BEGIN
 END;
 $$;
 
--- Base58 encoding/decoding
+-- Base58 encoding/decoding. Like usid.ParseBase58, decoding rejects empty or
+-- over-length input and bad characters with SQLSTATE 22P02, and values past
+-- the largest bigint with 22003.
 CREATE OR REPLACE FUNCTION b58_to_usid(encoded_id varchar(11))
   RETURNS bigint
   LANGUAGE plpgsql
@@ -278,11 +465,21 @@ DECLARE
   p int;
   result bigint := 0;
 BEGIN
+  -- Postgres ignores the varchar(11) length of a parameter
+  IF char_length(encoded_id) NOT BETWEEN 1 AND 11 THEN
+    RAISE EXCEPTION 'Invalid base58 length: %%', char_length(encoded_id)
+      USING ERRCODE = 'invalid_text_representation';
+  END IF;
   FOR i IN 1..char_length(encoded_id) LOOP
     c := substring(encoded_id FROM i FOR 1);
     p := position(c IN alphabet);
     IF p = 0 THEN
-      RAISE EXCEPTION 'Invalid base58 character: %%', c;
+      RAISE EXCEPTION 'Invalid base58 character: %%', c
+        USING ERRCODE = 'invalid_text_representation';
+    END IF;
+    IF result > (9223372036854775807 - (p - 1)) / 58 THEN
+      RAISE EXCEPTION 'base58 value out of range for usid: %%', encoded_id
+        USING ERRCODE = 'numeric_value_out_of_range';
     END IF;
     result := (result * 58) + (p - 1);
   END LOOP;
@@ -351,13 +548,26 @@ CREATE OR REPLACE FUNCTION usid_to_b64(id bigint)
   );
 $$;
 
--- Hex encoding/decoding
+-- Hex encoding/decoding. Like usid.ParseHash, decoding accepts 1-16 hex
+-- digits in either case and rejects anything else with SQLSTATE 22P02.
+-- Values with the high bit set, which no generator issues, are rejected with
+-- 22003 rather than decoded to a negative bigint.
 CREATE OR REPLACE FUNCTION hex_to_usid(encoded_id text)
   RETURNS bigint
-  LANGUAGE sql
+  LANGUAGE plpgsql
   IMMUTABLE PARALLEL SAFE STRICT LEAKPROOF
   AS $$
-  SELECT ('x' || lpad(encoded_id, 16, '0'))::bit(64)::bigint;
+BEGIN
+  IF encoded_id !~ '^[0-9A-Fa-f]{1,16}$' THEN
+    RAISE EXCEPTION 'Invalid hex usid: %%', encoded_id
+      USING ERRCODE = 'invalid_text_representation';
+  END IF;
+  IF char_length(encoded_id) = 16 AND substring(encoded_id FROM 1 FOR 1) !~ '[0-7]' THEN
+    RAISE EXCEPTION 'hex value out of range for usid: %%', encoded_id
+      USING ERRCODE = 'numeric_value_out_of_range';
+  END IF;
+  RETURN ('x' || lpad(encoded_id, 16, '0'))::bit(64)::bigint;
+END;
 $$;
 
 CREATE OR REPLACE FUNCTION usid_to_hex(id bigint)
@@ -368,17 +578,31 @@ CREATE OR REPLACE FUNCTION usid_to_hex(id bigint)
   SELECT to_hex(id);
 $$;
 `,
-		maxSeq,      // usid_seq MAXVALUE
-		maxNode,     // usid_node_seq MAXVALUE
-		maxNode,     // comment: 1-maxNode
-		cfg.Epoch,   // epoch in usid()
-		seqMask,     // seq mask in usid()
-		timeShift,   // time shift in usid()
-		cfg.SeqBits, // node shift in usid()
-		timeShift,   // time shift in ts_from_usid
-		cfg.Epoch,   // epoch in ts_from_usid
-		cfg.SeqBits, // node shift in node_from_usid
-		nodeMask,    // node mask in node_from_usid
-		seqMask,     // seq mask in seq_from_usid
-	)
+		maxSeq,                // usid_seq MAXVALUE
+		cfg.MaxAppNode(),      // usid_node_seq MAXVALUE
+		cfg.MaxAppNode(),      // comment: 1-MaxAppNode
+		cfg.Epoch,             // epoch in usid()
+		usidNode(cfg),         // node in usid()
+		nodeOverrideSQL(cfg),  // NodeSetting in usid()
+		cfg.PrecisionMicros(), // precision in usid()
+		seqMask,               // seq mask in usid()
+		timeShift,             // time shift in usid()
+		cfg.NodeShift(),       // node shift in usid()
+		sqlBody(cfg, fmt.Sprintf("to_timestamp(((id >> %d) * %d + %d)::numeric / 1000000)",
+			timeShift, cfg.PrecisionMicros(), cfg.Epoch)), // ts_from_usid
+		sqlBody(cfg, fmt.Sprintf("((id >> %d) & %d)::int", cfg.NodeShift(), nodeMask)),     // node_from_usid
+		sqlBody(cfg, fmt.Sprintf("((id >> %d) & %d)::int", cfg.SeqBits, cfg.TenantMask())), // tenant_from_usid
+		sqlBody(cfg, fmt.Sprintf("(id & %d)::int", seqMask)),                               // seq_from_usid
+	) + nodeRangeSQL(cfg) + explainSQL(cfg) + blockSQL(cfg) + watermarkSQL() + brinSQL(cfg) + citusSQL(cfg) + grantSQL(cfg)
+}
+
+// sqlBody returns the body of a LANGUAGE sql function that returns expr. On
+// PostgreSQL 14 and later it is a SQL-standard RETURN clause, parsed once when
+// the function is created and tracked as a dependency of the objects it uses;
+// before that, a string literal parsed on first call.
+func sqlBody(cfg Config, expr string) string {
+	if cfg.ServerVersion >= 140000 {
+		return "RETURN " + expr + ";"
+	}
+	return "AS $$\n  SELECT " + expr + ";\n$$;"
 }
@@ -3,11 +3,17 @@ package postgres_test
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	"github.com/paraglidehq/usid/v2"
 	"github.com/paraglidehq/usid/v2/postgres"
 	"github.com/testcontainers/testcontainers-go"
 	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
@@ -18,7 +24,13 @@ func setupPostgres(t *testing.T) (*sql.DB, func()) {
 	t.Helper()
 	ctx := context.Background()
 
-	container, err := tcpostgres.Run(ctx, "postgres:16-alpine",
+	// USID_POSTGRES_IMAGE selects the server version, for testing the
+	// migration against every supported release.
+	image := os.Getenv("USID_POSTGRES_IMAGE")
+	if image == "" {
+		image = "postgres:16-alpine"
+	}
+	container, err := tcpostgres.Run(ctx, image,
 		tcpostgres.WithDatabase("testdb"),
 		tcpostgres.WithUsername("test"),
 		tcpostgres.WithPassword("test"),
@@ -79,6 +91,518 @@ func TestMigrate(t *testing.T) {
 	}
 }
 
+func TestGetConfigOldSchema(t *testing.T) {
+	db, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	// The config table as the first release created it
+	ctx := context.Background()
+	cfg := postgres.DefaultConfig()
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`
+		CREATE TABLE _usid_config (
+			id int PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+			epoch bigint NOT NULL,
+			node_bits int NOT NULL,
+			seq_bits int NOT NULL
+		);
+		INSERT INTO _usid_config (epoch, node_bits, seq_bits) VALUES (%d, %d, %d)`,
+		cfg.Epoch, cfg.NodeBits, cfg.SeqBits))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := postgres.GetConfig(ctx, db); err != nil || got != cfg {
+		t.Errorf("GetConfig on the old schema = %+v, %v, want %+v", got, err, cfg)
+	}
+	if err := postgres.Migrate(ctx, db, cfg); err != nil {
+		t.Fatalf("Migrate on the old schema: %v", err)
+	}
+	if got, err := postgres.GetConfig(ctx, db); err != nil || got != cfg {
+		t.Errorf("GetConfig after Migrate = %+v, %v, want %+v", got, err, cfg)
+	}
+}
+
+func TestConfigDiff(t *testing.T) {
+	a := postgres.DefaultConfig()
+	b := a
+	b.ServerVersion, b.Grants = 160004, "app"
+	if diff := a.Diff(b); len(diff) != 0 {
+		t.Errorf("Diff of layouts differing only in migration options = %v", diff)
+	}
+	b.Precision, b.NodeBits = time.Millisecond, 10
+	diff := a.Diff(b)
+	want := []postgres.Difference{{"precision_us", 1, 1000}, {"node_bits", 6, 10}}
+	if len(diff) != len(want) || diff[0] != want[0] || diff[1] != want[1] {
+		t.Errorf("Diff = %v, want %v", diff, want)
+	}
+	err := error(&postgres.IncompatibleError{Differences: diff})
+	if !errors.Is(err, postgres.ErrConfigMismatch) || !strings.Contains(err.Error(), "node_bits 6 != 10") {
+		t.Errorf("IncompatibleError = %v", err)
+	}
+}
+
+func TestVerifyCompatible(t *testing.T) {
+	oltp, cleanup := setupPostgres(t)
+	defer cleanup()
+	reporting, cleanup2 := setupPostgres(t)
+	defer cleanup2()
+
+	ctx := context.Background()
+	if err := postgres.Migrate(ctx, oltp, postgres.DefaultConfig()); err != nil {
+		t.Fatal(err)
+	}
+	cfg := postgres.DefaultConfig()
+	if err := postgres.Migrate(ctx, reporting, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := postgres.VerifyCompatible(ctx, oltp, reporting); err != nil {
+		t.Errorf("VerifyCompatible of identical layouts: %v", err)
+	}
+
+	if _, err := reporting.ExecContext(ctx, "UPDATE _usid_config SET epoch = epoch + 1"); err != nil {
+		t.Fatal(err)
+	}
+	var incompatible *postgres.IncompatibleError
+	err := postgres.VerifyCompatible(ctx, oltp, reporting)
+	if !errors.As(err, &incompatible) || len(incompatible.Differences) != 1 || incompatible.Differences[0].Setting != "epoch" {
+		t.Errorf("VerifyCompatible = %v, want an epoch difference", err)
+	}
+}
+
+func TestConfigureFromDB(t *testing.T) {
+	db, cleanup := setupPostgres(t)
+	defer cleanup()
+	defer usid.Configure(usid.DefaultConfig())
+
+	ctx := context.Background()
+	cfg := postgres.DefaultConfig()
+	cfg.Precision, cfg.NodeBits, cfg.SeqBits = time.Millisecond, 10, 10
+	if err := postgres.Migrate(ctx, db, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	gen, err := postgres.ConfigureFromDB(ctx, db, postgres.WithAcquiredNode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := usid.CurrentConfig(); got != usid.PrecisionMilli() {
+		t.Errorf("CurrentConfig = %+v, want %+v", got, usid.PrecisionMilli())
+	}
+	if gen != usid.DefaultGenerator {
+		t.Error("ConfigureFromDB did not return DefaultGenerator")
+	}
+
+	// A node acquired by ConfigureFromDB is never handed out again.
+	next, err := postgres.NextNode(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node := gen.Generate().Node(); node == next {
+		t.Errorf("generator node %d handed out again", node)
+	}
+}
+
+func TestEras(t *testing.T) {
+	defer usid.Configure(usid.DefaultConfig())
+	cfg := usid.DefaultConfig()
+	cfg.EraBits = 1
+	if err := usid.Configure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := postgres.ConfigureFromDB(context.Background(), nil); err == nil {
+		t.Error("ConfigureFromDB dropped the current layout's eras")
+	}
+	if err := postgres.Migrate(context.Background(), nil); err == nil {
+		t.Error("Migrate accepted a layout with eras")
+	}
+}
+
+func TestWatermark(t *testing.T) {
+	db, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := postgres.Migrate(ctx, db, postgres.DefaultConfig()); err != nil {
+		t.Fatal(err)
+	}
+	if id, err := postgres.LoadWatermark(ctx, db, "export"); err != nil || id != 0 {
+		t.Fatalf("LoadWatermark before any save = %d, %v, want 0", id, err)
+	}
+
+	w := usid.Watermark{ID: usid.New()}
+	if err := postgres.SaveWatermark(ctx, db, "export", w.ID.Int64()); err != nil {
+		t.Fatal(err)
+	}
+	if err := postgres.SaveWatermark(ctx, db, "export", w.ID.Int64()-1); err != nil {
+		t.Fatal(err)
+	}
+	id, err := postgres.LoadWatermark(ctx, db, "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := (usid.Watermark{ID: usid.FromInt64(id)}); got != w {
+		t.Errorf("LoadWatermark = %v after saving an older ID, want %v", got, w)
+	}
+}
+
+func TestOutboxSQL(t *testing.T) {
+	sql := postgres.OutboxSQL(`app.out"box; DROP TABLE users`)
+	if !strings.Contains(sql, `CREATE TABLE IF NOT EXISTS "app"."out""box; DROP TABLE users" (`) {
+		t.Errorf("OutboxSQL does not quote the table name:\n%s", sql)
+	}
+}
+
+func TestOutbox(t *testing.T) {
+	db, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := postgres.Migrate(ctx, db, postgres.DefaultConfig()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, postgres.OutboxSQL("outbox")); err != nil {
+		t.Fatal(err)
+	}
+
+	appID := usid.New().Int64()
+	first, err := postgres.InsertOutbox(ctx, db, "outbox", postgres.OutboxMessage{Topic: "orders", Payload: []byte(`{"n":1}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := postgres.InsertOutbox(ctx, db, "outbox", postgres.OutboxMessage{ID: appID, Topic: "orders", Payload: []byte(`{"n":2}`)}); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, err := postgres.ClaimOutbox(ctx, db, "outbox", 10, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 2 || msgs[0].ID >= msgs[1].ID || msgs[0].Attempts != 1 {
+		t.Fatalf("ClaimOutbox = %+v, want 2 messages in ID order", msgs)
+	}
+	if ids := []int64{msgs[0].ID, msgs[1].ID}; !slices.Contains(ids, first) || !slices.Contains(ids, appID) {
+		t.Errorf("ClaimOutbox IDs = %v, want %d and %d", ids, first, appID)
+	}
+	if again, err := postgres.ClaimOutbox(ctx, db, "outbox", 10, time.Hour); err != nil || len(again) != 0 {
+		t.Errorf("ClaimOutbox during lease = %+v, %v, want none", again, err)
+	}
+
+	if err := postgres.AckOutbox(ctx, db, "outbox", msgs[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, "UPDATE outbox SET claimed_until = now() - interval '1 second'"); err != nil {
+		t.Fatal(err)
+	}
+	redelivered, err := postgres.ClaimOutbox(ctx, db, "outbox", 10, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(redelivered) != 1 || redelivered[0].ID != msgs[1].ID || redelivered[0].Attempts != 2 {
+		t.Errorf("ClaimOutbox after lease expiry = %+v, want message %d on attempt 2", redelivered, msgs[1].ID)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	blue, cleanup := setupPostgres(t)
+	defer cleanup()
+	green, cleanup2 := setupPostgres(t)
+	defer cleanup2()
+
+	ctx := context.Background()
+	for _, db := range []*sql.DB{blue, green} {
+		if err := postgres.Migrate(ctx, db, postgres.DefaultConfig()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for range 3 {
+		if _, err := postgres.NextNode(ctx, blue); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := postgres.ReserveBlock(ctx, blue, 1000); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := postgres.Export(ctx, blue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Node != 4 || state.BlockNode != 4 || state.BlockTick == 0 {
+		t.Fatalf("Export = %+v, want 4 nodes handed out and a block", state)
+	}
+	for range 2 { // importing twice is harmless
+		if err := postgres.Import(ctx, green, state); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if node, err := postgres.NextNode(ctx, green); err != nil || node != 5 {
+		t.Errorf("NextNode after Import = %d, %v, want 5", node, err)
+	}
+	if got, err := postgres.Export(ctx, green); err != nil || got.BlockNode != state.BlockNode || got.BlockTick != state.BlockTick {
+		t.Errorf("Export after Import = %+v, %v, want block state of %+v", got, err, state)
+	}
+
+	// usid_seq does not move back
+	for range 3 {
+		if _, err := green.ExecContext(ctx, "SELECT usid()"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before, err := postgres.Export(ctx, green)
+	if err != nil {
+		t.Fatal(err)
+	}
+	behind := state
+	behind.Seq = 1
+	if err := postgres.Import(ctx, green, behind); err != nil {
+		t.Fatal(err)
+	}
+	if after, err := postgres.Export(ctx, green); err != nil || after.Seq != before.Seq {
+		t.Errorf("Export after importing an older sequence = %+v, %v, want usid_seq at %d", after, err, before.Seq)
+	}
+
+	moved := state
+	moved.BlockNode++
+	if err := postgres.Import(ctx, green, moved); err == nil {
+		t.Error("Import with a different block node succeeded")
+	}
+
+	state.Config.NodeBits = 8
+	var incompatible *postgres.IncompatibleError
+	if err := postgres.Import(ctx, green, state); !errors.As(err, &incompatible) {
+		t.Errorf("Import with a different layout = %v, want *IncompatibleError", err)
+	}
+}
+
+func TestServerVersion(t *testing.T) {
+	db, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	version, err := postgres.ServerVersion(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version < postgres.MinServerVersion {
+		t.Fatalf("ServerVersion() = %d, want >= %d", version, postgres.MinServerVersion)
+	}
+
+	// Migrate targets the server's version, and the SQL for the oldest
+	// supported release runs on every newer one.
+	for _, target := range []int{postgres.MinServerVersion, 0} {
+		cfg := postgres.DefaultConfig()
+		cfg.ServerVersion = target
+		if err := postgres.Migrate(ctx, db, cfg); err != nil {
+			t.Fatalf("Migrate(ServerVersion: %d): %v", target, err)
+		}
+		var node int
+		if err := db.QueryRowContext(ctx, "SELECT node_from_usid($1)", 5<<6).Scan(&node); err != nil || node != 5 {
+			t.Errorf("node_from_usid = %d, %v, want 5", node, err)
+		}
+	}
+}
+
+func TestGenerateSQLVersion(t *testing.T) {
+	cfg := postgres.DefaultConfig()
+	if sql := postgres.GenerateSQL(cfg); strings.Contains(sql, "RETURN (id &") {
+		t.Error("GenerateSQL without ServerVersion uses SQL-standard function bodies")
+	}
+	cfg.ServerVersion = 160004
+	if sql := postgres.GenerateSQL(cfg); !strings.Contains(sql, "RETURN (id & 63)::int;") {
+		t.Error("GenerateSQL for PostgreSQL 16 does not use SQL-standard function bodies")
+	}
+}
+
+func TestGrants(t *testing.T) {
+	cfg := postgres.DefaultConfig()
+	cfg.Grants = "app, Weird\"Role"
+	sql := postgres.GenerateSQL(cfg)
+	for _, want := range []string{
+		`TO "app", "Weird""Role";`,
+		"GRANT USAGE ON SEQUENCE usid_seq, usid_node_seq",
+		"usid_reserve_block(bigint), ",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("GenerateSQL with Grants does not contain %q", want)
+		}
+	}
+	cfg.SecurityDefiner = true
+	sql = postgres.GenerateSQL(cfg)
+	if !strings.Contains(sql, "ALTER FUNCTION usid() SECURITY DEFINER") || strings.Contains(sql, "ON SEQUENCE") {
+		t.Error("GenerateSQL with SecurityDefiner still grants sequence access or is missing SECURITY DEFINER")
+	}
+	if !strings.Contains(sql, "ON TABLE _usid_watermark TO") {
+		t.Error("GenerateSQL with SecurityDefiner does not grant access to the watermark table")
+	}
+	revoke := strings.Index(sql, "REVOKE EXECUTE ON FUNCTION usid(), usid_next_node(), usid_reserve_block(bigint) FROM PUBLIC;")
+	if revoke < 0 || revoke > strings.Index(sql, "GRANT EXECUTE") {
+		t.Error("GenerateSQL with SecurityDefiner does not revoke EXECUTE from PUBLIC before granting it")
+	}
+	if sql := postgres.GenerateSQL(postgres.DefaultConfig()); strings.Contains(sql, "GRANT") {
+		t.Error("GenerateSQL without Grants contains GRANT statements")
+	}
+}
+
+func TestCitusSQL(t *testing.T) {
+	cfg := postgres.DefaultConfig()
+	if sql := postgres.GenerateSQL(cfg); strings.Contains(sql, "usid_citus_node") {
+		t.Error("GenerateSQL without CitusNodes references usid_citus_node")
+	}
+	cfg.CitusNodes = 8
+	if got := cfg.MaxAppNode(); got != 55 {
+		t.Errorf("MaxAppNode() = %d, want 55", got)
+	}
+	sql := postgres.GenerateSQL(cfg)
+	for _, want := range []string{
+		"CYCLE MINVALUE 1 MAXVALUE 55;",
+		"ALTER SEQUENCE usid_node_seq MAXVALUE 55;",
+		"node bigint := usid_citus_node();",
+		"RETURN 63 - g;",
+		"create_distributed_function('usid()')",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("GenerateSQL with CitusNodes does not contain %q", want)
+		}
+	}
+}
+
+func TestNodeRangeSQL(t *testing.T) {
+	cfg := postgres.DefaultConfig()
+	if sql := postgres.GenerateSQL(cfg); strings.Contains(sql, "node NOT BETWEEN") {
+		t.Error("GenerateSQL without reserved ranges skips nodes")
+	}
+	cfg.StaticNodes = postgres.NodeRange{Min: 1, Max: 15}
+	cfg.EphemeralNodes = postgres.NodeRange{Min: 48, Max: 55}
+	cfg.CitusNodes = 8
+	sql := postgres.GenerateSQL(cfg)
+	if want := "EXIT WHEN true AND node NOT BETWEEN 1 AND 15 AND node NOT BETWEEN 48 AND 55;"; !strings.Contains(sql, want) {
+		t.Errorf("GenerateSQL with reserved ranges does not contain %q", want)
+	}
+
+	ctx := context.Background()
+	for _, bad := range []struct{ static, ephemeral postgres.NodeRange }{
+		{static: postgres.NodeRange{Min: 0, Max: 15}},
+		{static: postgres.NodeRange{Min: 20, Max: 10}},
+		{ephemeral: postgres.NodeRange{Min: 48, Max: 63}}, // Citus nodes
+		{static: postgres.NodeRange{Min: 1, Max: 30}, ephemeral: postgres.NodeRange{Min: 30, Max: 55}},
+		{static: postgres.NodeRange{Min: 1, Max: 27}, ephemeral: postgres.NodeRange{Min: 28, Max: 55}},
+	} {
+		c := cfg
+		c.StaticNodes, c.EphemeralNodes = bad.static, bad.ephemeral
+		// Migrate rejects the config before it touches the database.
+		if err := postgres.Migrate(ctx, nil, c); err == nil {
+			t.Errorf("Migrate(static %+v, ephemeral %+v): want err != nil", bad.static, bad.ephemeral)
+		}
+	}
+}
+
+func TestNextNodeReserved(t *testing.T) {
+	db, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cfg := postgres.DefaultConfig()
+	cfg.StaticNodes = postgres.NodeRange{Min: 1, Max: 15}
+	cfg.EphemeralNodes = postgres.NodeRange{Min: 48, Max: 63}
+	if err := postgres.Migrate(ctx, db, cfg); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	got, err := postgres.GetConfig(ctx, db)
+	if err != nil {
+		t.Fatalf("GetConfig failed: %v", err)
+	}
+	if got.StaticNodes != cfg.StaticNodes || got.EphemeralNodes != cfg.EphemeralNodes {
+		t.Errorf("GetConfig ranges = %+v, %+v; want %+v, %+v", got.StaticNodes, got.EphemeralNodes, cfg.StaticNodes, cfg.EphemeralNodes)
+	}
+	for range 2 * 32 {
+		node, err := postgres.NextNode(ctx, db)
+		if err != nil {
+			t.Fatalf("NextNode failed: %v", err)
+		}
+		if node < 16 || node > 47 {
+			t.Errorf("NextNode = %d, want a node in 16-47", node)
+		}
+	}
+}
+
+func TestNodeOverride(t *testing.T) {
+	db, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cfg := postgres.DefaultConfig()
+	cfg.NodeOverride = true
+	if err := postgres.Migrate(ctx, db, cfg); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var node int64
+	if err := conn.QueryRowContext(ctx, "SELECT node_from_usid(usid())").Scan(&node); err != nil || node != 0 {
+		t.Errorf("node without override = %d, %v, want 0", node, err)
+	}
+	conn.ExecContext(ctx, "SET "+postgres.NodeSetting+" = 12")
+	if err := conn.QueryRowContext(ctx, "SELECT node_from_usid(usid())").Scan(&node); err != nil || node != 12 {
+		t.Errorf("node with override = %d, %v, want 12", node, err)
+	}
+
+	var id int64
+	var pqErr *pq.Error
+	for value, code := range map[string]pq.ErrorCode{postgres.NodeOff: "55000", "64": "22023", "x": "22023"} {
+		conn.ExecContext(ctx, "SET "+postgres.NodeSetting+" = '"+value+"'")
+		err := conn.QueryRowContext(ctx, "SELECT usid()").Scan(&id)
+		if !errors.As(err, &pqErr) || pqErr.Code != code {
+			t.Errorf("usid() with %s = %q: error = %v, want SQLSTATE %s", postgres.NodeSetting, value, err, code)
+		}
+	}
+}
+
+func TestRestrictedRole(t *testing.T) {
+	db, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, `CREATE ROLE app NOLOGIN; REVOKE EXECUTE ON ALL FUNCTIONS IN SCHEMA public FROM PUBLIC`); err != nil {
+		t.Fatal(err)
+	}
+	for _, definer := range []bool{false, true} {
+		cfg := postgres.DefaultConfig()
+		cfg.Grants = "app"
+		cfg.SecurityDefiner = definer
+		if err := postgres.Migrate(ctx, db, cfg); err != nil {
+			t.Fatalf("migration failed: %v", err)
+		}
+		if definer {
+			if _, err := db.ExecContext(ctx, `REVOKE USAGE ON SEQUENCE usid_seq, usid_node_seq FROM app;
+				REVOKE SELECT, INSERT, UPDATE ON TABLE _usid_block FROM app`); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var id int64
+		var first, last int64
+		_, err = conn.ExecContext(ctx, "SET ROLE app")
+		if err == nil {
+			err = conn.QueryRowContext(ctx, "SELECT usid()").Scan(&id)
+		}
+		if err == nil {
+			err = conn.QueryRowContext(ctx, "SELECT first_id, last_id FROM usid_reserve_block(10)").Scan(&first, &last)
+		}
+		if err != nil {
+			t.Errorf("SecurityDefiner=%v: restricted role: %v", definer, err)
+		}
+		conn.ExecContext(ctx, "RESET ROLE")
+		conn.Close()
+	}
+}
+
 func TestMigrateConfigMismatch(t *testing.T) {
 	db, cleanup := setupPostgres(t)
 	defer cleanup()
@@ -198,6 +722,34 @@ func TestTimestampExtraction(t *testing.T) {
 	}
 }
 
+func TestTenantExtraction(t *testing.T) {
+	db, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cfg := postgres.DefaultConfig()
+	cfg.TenantBits = 4
+	if err := postgres.Migrate(ctx, db, cfg); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+
+	layout := usid.DefaultConfig()
+	layout.TenantBits = 4
+	if err := usid.Configure(layout); err != nil {
+		t.Fatal(err)
+	}
+	defer usid.Configure(usid.DefaultConfig())
+	id := usid.NewGenerator(3, usid.WithTenant(5)).Generate()
+
+	var node, tenant int64
+	if err := db.QueryRowContext(ctx, "SELECT node_from_usid($1), tenant_from_usid($1)", id).Scan(&node, &tenant); err != nil {
+		t.Fatalf("extraction failed: %v", err)
+	}
+	if node != 3 || tenant != 5 {
+		t.Errorf("node_from_usid, tenant_from_usid = %d, %d, want 3, 5", node, tenant)
+	}
+}
+
 func TestNilAndOmni(t *testing.T) {
 	db, cleanup := setupPostgres(t)
 	defer cleanup()
@@ -256,6 +808,22 @@ func TestNilAndOmni(t *testing.T) {
 	if isOmni {
 		t.Error("is_omni_usid(123) = true, want false")
 	}
+
+	// Test usid_is_special() and usid_clamp()
+	for _, tc := range []struct {
+		id      int64
+		special bool
+		clamped int64
+	}{{nilID, true, 0}, {omniID, true, omniID}, {123, false, 123}, {-5, false, 0}} {
+		var special bool
+		var clamped int64
+		if err := db.QueryRowContext(ctx, "SELECT usid_is_special($1), usid_clamp($1)", tc.id).Scan(&special, &clamped); err != nil {
+			t.Fatalf("usid_is_special/usid_clamp failed: %v", err)
+		}
+		if special != tc.special || clamped != tc.clamped {
+			t.Errorf("usid_is_special(%d), usid_clamp(%d) = %v, %d, want %v, %d", tc.id, tc.id, special, clamped, tc.special, tc.clamped)
+		}
+	}
 }
 
 func TestSequenceIncrement(t *testing.T) {
@@ -352,6 +920,159 @@ func TestEncodingRoundtrip(t *testing.T) {
 	}
 }
 
+func TestDecodeValidation(t *testing.T) {
+	db, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := postgres.Migrate(ctx, db, postgres.DefaultConfig()); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+
+	tests := []struct {
+		decode string
+		input  string
+		code   pq.ErrorCode
+		parse  func(string) (usid.ID, error)
+	}{
+		{"b58_to_usid", "", "22P02", usid.ParseBase58},
+		{"b58_to_usid", "111111111112", "22P02", usid.ParseBase58}, // 12 characters
+		{"b58_to_usid", "0OIl", "22P02", usid.ParseBase58},
+		{"b58_to_usid", "zzzzzzzzzzz", "22003", usid.ParseBase58},
+		{"hex_to_usid", "", "22P02", usid.ParseHash},
+		{"hex_to_usid", "00000000000000001", "22P02", usid.ParseHash}, // 17 digits
+		{"hex_to_usid", "xyz", "22P02", usid.ParseHash},
+		{"hex_to_usid", "-1", "22P02", usid.ParseHash},
+		// The Go parser keeps the high bit for obfuscated IDs
+		{"hex_to_usid", "8000000000000000", "22003", nil},
+		{"hex_to_usid", "FFFFFFFFFFFFFFFF", "22003", nil},
+	}
+	for _, tt := range tests {
+		var id int64
+		err := db.QueryRowContext(ctx, "SELECT "+tt.decode+"($1)", tt.input).Scan(&id)
+		var pqErr *pq.Error
+		if !errors.As(err, &pqErr) || pqErr.Code != tt.code {
+			t.Errorf("%s(%q) error = %v, want SQLSTATE %s", tt.decode, tt.input, err, tt.code)
+		}
+		if tt.parse == nil {
+			continue
+		}
+		if _, err := tt.parse(tt.input); err == nil {
+			t.Errorf("Go parser accepted %q, which %s rejects", tt.input, tt.decode)
+		}
+	}
+
+	// Both layers accept the largest ID
+	for decode, input := range map[string]string{
+		"b58_to_usid": usid.Omni.Format(usid.FormatBase58),
+		"hex_to_usid": usid.Omni.Format(usid.FormatHash),
+	} {
+		var id int64
+		if err := db.QueryRowContext(ctx, "SELECT "+decode+"($1)", input).Scan(&id); err != nil {
+			t.Errorf("%s(%q): %v", decode, input, err)
+		}
+	}
+}
+
+func TestExplain(t *testing.T) {
+	db, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := postgres.Migrate(ctx, db, postgres.DefaultConfig()); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+
+	id := usid.NewGenerator(3).Generate()
+	var pretty string
+	var explain []byte
+	if err := db.QueryRowContext(ctx, "SELECT usid_pretty($1), usid_explain($1)", id).Scan(&pretty, &explain); err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprintf("%s node=3 seq=%d", id.Timestamp().UTC().Format("2006-01-02T15:04:05.000000Z"), id.Seq())
+	if pretty != want {
+		t.Errorf("usid_pretty = %q, want %q", pretty, want)
+	}
+
+	var got struct {
+		Node    int64             `json:"node"`
+		Seq     int64             `json:"seq"`
+		Raw     int64             `json:"raw"`
+		Formats map[string]string `json:"formats"`
+	}
+	if err := json.Unmarshal(explain, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Node != 3 || got.Seq != id.Seq() || got.Raw != id.Int64() {
+		t.Errorf("usid_explain = %s, want node 3, seq %d, raw %d", explain, id.Seq(), id.Int64())
+	}
+	for f, s := range got.Formats {
+		if want := id.Format(usid.Format(f)); s != want {
+			t.Errorf("usid_explain %s = %q, want %q", f, s, want)
+		}
+	}
+}
+
+func TestPagesPerRange(t *testing.T) {
+	tests := []struct {
+		rate, perPage float64
+		span          time.Duration
+		want          int
+	}{
+		{100, 50, time.Minute, 120},
+		{100, 50, 0, 120},
+		{1, 100, time.Minute, 1},
+		{1e6, 10, time.Hour, postgres.MaxPagesPerRange},
+		{0, 50, time.Minute, 128},
+	}
+	for _, tt := range tests {
+		if got := postgres.PagesPerRange(tt.rate, tt.perPage, tt.span); got != tt.want {
+			t.Errorf("PagesPerRange(%v, %v, %v) = %d, want %d", tt.rate, tt.perPage, tt.span, got, tt.want)
+		}
+	}
+}
+
+func TestCreateBRIN(t *testing.T) {
+	db, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := postgres.Migrate(ctx, db, postgres.DefaultConfig()); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	// 10,000 rows over 100 seconds
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE events (id bigint PRIMARY KEY, payload text);
+		INSERT INTO events
+			SELECT (((extract(epoch FROM now()) * 1000000)::bigint - 1765947799213000 - i * 10000) << 12) | 1, repeat('x', 100)
+			FROM generate_series(1, 10000) i;
+		ANALYZE events`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pages, err := postgres.CreateBRIN(ctx, db, "events", "id", 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var relpages int
+	db.QueryRowContext(ctx, "SELECT relpages FROM pg_class WHERE relname = 'events'").Scan(&relpages)
+	if want := (relpages + 9) / 10; pages < want-1 || pages > want+1 {
+		t.Errorf("CreateBRIN = %d pages per range, want ~%d (a tenth of %d pages)", pages, want, relpages)
+	}
+	var def string
+	if err := db.QueryRowContext(ctx, "SELECT indexdef FROM pg_indexes WHERE indexname = 'events_id_brin'").Scan(&def); err != nil {
+		t.Fatalf("index not created: %v", err)
+	}
+	if !strings.Contains(def, "USING brin") || !strings.Contains(def, fmt.Sprintf("pages_per_range='%d'", pages)) {
+		t.Errorf("index definition = %q", def)
+	}
+
+	if _, err := postgres.CreateBRIN(ctx, db, "events", "id", 0); err != nil {
+		t.Errorf("CreateBRIN on an existing index: %v", err)
+	}
+}
+
 func TestCreateDomain(t *testing.T) {
 	db, cleanup := setupPostgres(t)
 	defer cleanup()
@@ -413,3 +1134,225 @@ func TestCreateDomainDisabled(t *testing.T) {
 		t.Fatal("expected error when using usid domain (should not exist), got nil")
 	}
 }
+
+func TestIDArrayAny(t *testing.T) {
+	db, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := postgres.Migrate(ctx, db, postgres.DefaultConfig()); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `CREATE TABLE items (id bigint PRIMARY KEY DEFAULT usid())`); err != nil {
+		t.Fatalf("create table failed: %v", err)
+	}
+
+	var ids usid.IDArray
+	for i := 0; i < 3; i++ {
+		var id usid.ID
+		if err := db.QueryRowContext(ctx, "INSERT INTO items DEFAULT VALUES RETURNING id").Scan(&id); err != nil {
+			t.Fatalf("insert failed: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	var count int
+	if err := db.QueryRowContext(ctx, "SELECT count(*) FROM items WHERE id = ANY($1)", ids[:2]).Scan(&count); err != nil {
+		t.Fatalf("ANY query failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("count = %d, want 2", count)
+	}
+
+	var got usid.IDArray
+	if err := db.QueryRowContext(ctx, "SELECT array_agg(id ORDER BY id) FROM items").Scan(&got); err != nil {
+		t.Fatalf("array_agg scan failed: %v", err)
+	}
+	if len(got) != len(ids) {
+		t.Fatalf("got %d ids, want %d", len(got), len(ids))
+	}
+	for i := range ids {
+		if got[i] != ids[i] {
+			t.Errorf("got[%d] = %v, want %v", i, got[i], ids[i])
+		}
+	}
+}
+
+func TestClock(t *testing.T) {
+	db, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := usid.CheckClock(ctx, postgres.Clock(db)); err != nil {
+		t.Errorf("CheckClock against database: %v", err)
+	}
+}
+
+func TestAudit(t *testing.T) {
+	db, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := postgres.Migrate(ctx, db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	node, err := postgres.NextNode(ctx, db)
+	if err != nil {
+		t.Fatalf("NextNode failed: %v", err)
+	}
+
+	g := usid.NewGenerator(node)
+	shared := g.Generate()
+	foreign := usid.NewGenerator(node + 5).Generate()
+	for _, q := range []string{
+		`CREATE TABLE orders (id bigint PRIMARY KEY)`,
+		`CREATE TABLE refunds (id bigint PRIMARY KEY)`,
+		`CREATE TABLE notes (body text)`,
+	} {
+		if _, err := db.ExecContext(ctx, q); err != nil {
+			t.Fatalf("%s: %v", q, err)
+		}
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO orders VALUES ($1), ($2), (usid()), (-5)`, shared, g.Generate()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO refunds VALUES ($1), ($2)`, shared, foreign); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := postgres.Audit(ctx, db, "orders", "refunds")
+	if err != nil {
+		t.Fatalf("Audit failed: %v", err)
+	}
+	if report.OK() {
+		t.Error("OK() = true, want problems")
+	}
+	if report.MaxNode != node {
+		t.Errorf("MaxNode = %d, want %d", report.MaxNode, node)
+	}
+	if report.Duplicates != 1 || len(report.DuplicateSample) != 1 || report.DuplicateSample[0] != shared.Int64() {
+		t.Errorf("duplicates = %d %v, want [%d]", report.Duplicates, report.DuplicateSample, shared)
+	}
+	orders, refunds := report.Tables[0], report.Tables[1]
+	if orders.Rows != 4 || orders.BeforeEpoch != 1 || orders.UnknownNode != 0 {
+		t.Errorf("orders = %+v", orders)
+	}
+	if refunds.Rows != 2 || refunds.BeforeEpoch != 0 || refunds.UnknownNode != 1 {
+		t.Errorf("refunds = %+v", refunds)
+	}
+
+	if _, err := postgres.Audit(ctx, db, "notes"); err == nil {
+		t.Error("Audit accepted a table without a bigint primary key")
+	}
+}
+
+func TestNodeUsageReport(t *testing.T) {
+	db, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	cfg := postgres.DefaultConfig()
+	if err := postgres.Migrate(ctx, db, cfg); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	if _, err := postgres.NextNode(ctx, db); err != nil { // hands out node 1
+		t.Fatalf("NextNode failed: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `CREATE TABLE events (id bigint PRIMARY KEY)`); err != nil {
+		t.Fatalf("create table failed: %v", err)
+	}
+
+	now := time.Now()
+	id := func(at time.Time, node, seq int64) int64 {
+		return (at.UnixMicro()-cfg.Epoch)<<cfg.TimeShift() | node<<cfg.NodeShift() | seq
+	}
+	ids := []int64{
+		id(now, 1, 0), id(now, 1, 1), id(now, 9, 0),
+		id(now.Add(-72*time.Hour), 1, 0), // outside the window
+		9223372036854775807,              // omni
+	}
+	for _, id := range ids {
+		if _, err := db.ExecContext(ctx, `INSERT INTO events VALUES ($1)`, id); err != nil {
+			t.Fatalf("insert failed: %v", err)
+		}
+	}
+
+	day := now.UTC().Truncate(24 * time.Hour)
+	want := []postgres.NodeUsage{
+		{Node: 1, Day: day, IDs: 2},
+		{Node: 9, Day: day, IDs: 1, Unknown: true},
+	}
+	got, err := postgres.NodeUsageReport(ctx, db, "events", "id", 48*time.Hour)
+	if err != nil {
+		t.Fatalf("NodeUsageReport failed: %v", err)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("NodeUsageReport = %+v, want %+v", got, want)
+	}
+
+	all, err := postgres.NodeUsageReport(ctx, db, "events", "id", 0)
+	if err != nil {
+		t.Fatalf("NodeUsageReport failed: %v", err)
+	}
+	if len(all) != 3 || all[0].Day.After(all[1].Day) {
+		t.Errorf("NodeUsageReport without window = %+v, want node 1 on two days and node 9", all)
+	}
+}
+
+func TestReserveBlock(t *testing.T) {
+	db, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := postgres.Migrate(ctx, db); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	cfg := postgres.DefaultConfig()
+
+	a, err := postgres.ReserveBlock(ctx, db, 100)
+	if err != nil {
+		t.Fatalf("ReserveBlock failed: %v", err)
+	}
+	b, err := postgres.ReserveBlock(ctx, db, 1)
+	if err != nil {
+		t.Fatalf("ReserveBlock failed: %v", err)
+	}
+	if a.Size < 100 || a.Size%(cfg.MaxSeq()+1) != 0 {
+		t.Errorf("a.Size = %d, want a multiple of %d >= 100", a.Size, cfg.MaxSeq()+1)
+	}
+	if a.Node == 0 || b.Node != a.Node {
+		t.Errorf("block nodes = %d, %d; want the same nonzero node", a.Node, b.Node)
+	}
+	if b.First <= a.Last {
+		t.Errorf("blocks overlap: a ends at %d, b starts at %d", a.Last, b.First)
+	}
+
+	var ids []usid.ID
+	for {
+		id, ok := a.Next()
+		if !ok {
+			break
+		}
+		if node := usid.ID(id).Node(); node != a.Node {
+			t.Fatalf("ID %d has node %d, want %d", id, node, a.Node)
+		}
+		ids = append(ids, usid.ID(id))
+	}
+	if int64(len(ids)) != a.Size || ids[0].Int64() != a.First || ids[len(ids)-1].Int64() != a.Last {
+		t.Errorf("Next() yielded %d IDs from %d to %d, want %d from %d to %d",
+			len(ids), ids[0], ids[len(ids)-1], a.Size, a.First, a.Last)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("ids[%d] = %d, want > %d", i, ids[i], ids[i-1])
+		}
+	}
+
+	var dbID int64
+	if err := db.QueryRowContext(ctx, "SELECT usid()").Scan(&dbID); err != nil {
+		t.Fatal(err)
+	}
+	if usid.ID(dbID).Node() == a.Node {
+		t.Errorf("usid() uses the block node %d", a.Node)
+	}
+}
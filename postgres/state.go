@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+)
+
+// SequenceState is the ID generation state of a database: the layout and
+// the positions of the usid sequences and block table. Export it from the
+// old database and Import it into the new one during a blue/green or
+// logical replication cutover, since sequences are not replicated and a
+// freshly migrated database would hand out nodes and blocks again.
+type SequenceState struct {
+	Config    Config // layout recorded by Migrate
+	Seq       int64  // last value of usid_seq, or 0 if never used
+	Node      int64  // last node handed out by usid_next_node, or 0 if none
+	BlockNode int64  // node reserved for ReserveBlock, or 0 if none
+	BlockTick int64  // tick at which the next block starts
+}
+
+// Export reads the ID generation state of db.
+func Export(ctx context.Context, db DB) (SequenceState, error) {
+	var s SequenceState
+	var err error
+	if s.Config, err = GetConfig(ctx, db); err != nil {
+		return s, fmt.Errorf("usid: export config: %w", err)
+	}
+	err = db.QueryRowContext(ctx, `
+		SELECT (SELECT CASE WHEN is_called THEN last_value ELSE 0 END FROM usid_seq),
+		       (SELECT CASE WHEN is_called THEN last_value ELSE 0 END FROM usid_node_seq),
+		       coalesce((SELECT node FROM _usid_block), 0),
+		       coalesce((SELECT next_tick FROM _usid_block), 0)`).
+		Scan(&s.Seq, &s.Node, &s.BlockNode, &s.BlockTick)
+	if err != nil {
+		return s, fmt.Errorf("usid: export state: %w", err)
+	}
+	return s, nil
+}
+
+// Import restores state exported from another database into db, which must
+// have been migrated with the same layout; otherwise it returns an
+// *IncompatibleError. Positions only move forward, so importing into a
+// database that has already run ahead, or importing twice, is harmless. Run
+// it in a transaction before the new database takes traffic.
+//
+// If db has already reserved a block node of its own, Import returns an
+// error when it differs from s.BlockNode: the two nodes' blocks cannot be
+// merged, so import before the first ReserveBlock on db.
+func Import(ctx context.Context, db DB, s SequenceState) error {
+	cfg, err := GetConfig(ctx, db)
+	if err != nil {
+		return fmt.Errorf("usid: import config: %w", err)
+	}
+	if diff := s.Config.Diff(cfg); len(diff) > 0 {
+		return &IncompatibleError{Differences: diff}
+	}
+	_, err = db.ExecContext(ctx, `
+		SELECT setval('usid_node_seq', $1) FROM usid_node_seq
+		WHERE $1 > CASE WHEN is_called THEN last_value ELSE 0 END`, s.Node)
+	if err != nil {
+		return fmt.Errorf("usid: import node sequence: %w", err)
+	}
+	_, err = db.ExecContext(ctx, `
+		SELECT setval('usid_seq', $1) FROM usid_seq
+		WHERE $1 > CASE WHEN is_called THEN last_value ELSE 0 END`, s.Seq)
+	if err != nil {
+		return fmt.Errorf("usid: import sequence: %w", err)
+	}
+	if s.BlockNode > 0 {
+		var node int64
+		if err := db.QueryRowContext(ctx, `SELECT coalesce((SELECT node FROM _usid_block), 0)`).Scan(&node); err != nil {
+			return fmt.Errorf("usid: import block state: %w", err)
+		}
+		if node != 0 && node != s.BlockNode {
+			return fmt.Errorf("usid: import block state: database already reserves blocks with node %d, not %d", node, s.BlockNode)
+		}
+		_, err := db.ExecContext(ctx, `
+			INSERT INTO _usid_block (node, next_tick) VALUES ($1, $2)
+			ON CONFLICT (id) DO UPDATE SET next_tick = GREATEST(_usid_block.next_tick, EXCLUDED.next_tick)`,
+			s.BlockNode, s.BlockTick)
+		if err != nil {
+			return fmt.Errorf("usid: import block state: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// LoadWatermark returns the ID last saved under name by SaveWatermark, or 0
+// (the nil ID) if none has been, so a new consumer starts from the beginning.
+func LoadWatermark(ctx context.Context, db DB, name string) (int64, error) {
+	var id int64
+	err := db.QueryRowContext(ctx, `SELECT id FROM _usid_watermark WHERE name = $1`, name).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("usid: load watermark %q: %w", name, err)
+	}
+	return id, nil
+}
+
+// SaveWatermark records id as the watermark of the consumer called name. The
+// stored watermark only moves forward: saving an ID below it is a no-op, so
+// a consumer restarted from an older checkpoint cannot rewind another
+// instance's progress. Run it in the transaction that writes the consumer's
+// output to make processing and checkpointing atomic.
+func SaveWatermark(ctx context.Context, db DB, name string, id int64) error {
+	_, err := db.ExecContext(ctx, `
+INSERT INTO _usid_watermark (name, id) VALUES ($1, $2)
+ON CONFLICT (name) DO UPDATE SET id = GREATEST(_usid_watermark.id, EXCLUDED.id), updated_at = now()`, name, id)
+	if err != nil {
+		return fmt.Errorf("usid: save watermark %q: %w", name, err)
+	}
+	return nil
+}
+
+// watermarkSQL returns the table behind LoadWatermark and SaveWatermark.
+func watermarkSQL() string {
+	return `
+-- Consumer watermarks
+CREATE TABLE IF NOT EXISTS _usid_watermark (
+  name text PRIMARY KEY,
+  id bigint NOT NULL,
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+`
+}
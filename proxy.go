@@ -0,0 +1,59 @@
+package usid
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNodeRange is returned by GenerateForNode for a node ID that does not fit
+// in the generator's node bits.
+var ErrNodeRange = errors.New("usid: node ID out of range")
+
+// ErrNodeReserved is returned by GenerateForNode for node 0, which the
+// Postgres usid() function mints IDs for, and for nodes in DBNodes or
+// EphemeralNodes, unless it is the generator's own node.
+var ErrNodeReserved = errors.New("usid: node ID reserved")
+
+// GenerateForNode produces a new unique ID on behalf of node, for ID-issuing
+// services that mint IDs for many logical nodes without creating a Generator
+// for each. Every node gets its own sequence, created on first use, so IDs
+// for one node are strictly increasing and never collide with IDs of other
+// nodes. IDs for the generator's own node share its sequence with Generate;
+// other nodes are not sharded and use the full sequence space.
+// The generator's options, such as WithHLC or WithMaxRate, apply to every
+// node; Stats counts IDs issued for all of them.
+//
+// Returns ErrNodeRange if node does not fit in NodeBits, and ErrNodeReserved
+// if it is reserved.
+// Safe for concurrent use.
+func (g *Generator) GenerateForNode(node int64) (ID, error) {
+	if node < 0 || node >= 1<<(g.timeShift-g.nodeShift) {
+		return Nil, ErrNodeRange
+	}
+	if node != g.node && (node == 0 || nodeReserved(node)) {
+		return Nil, ErrNodeReserved
+	}
+	if g.limit != nil {
+		if d, _ := g.limit.reserve(true); d > 0 {
+			time.Sleep(d)
+		}
+	}
+	if node == g.node {
+		return g.generate(), nil
+	}
+	return g.generateIn(g.nodeState(node), g.nodeMask, 0, node), nil
+}
+
+// nodeState returns the sequence state of a node other than the generator's
+// own, creating it on first use.
+func (g *Generator) nodeState(node int64) *shardState {
+	if sh, ok := g.nodes.Load(node); ok {
+		return sh.(*shardState)
+	}
+	sh, _ := g.nodes.LoadOrStore(node, new(shardState))
+	st := sh.(*shardState)
+	// Start past any position the generator was moved to by Observe,
+	// RestoreState, or SetNodeID, including one reached while storing.
+	raise(&st.Uint64, g.floor.Load())
+	return st
+}
@@ -0,0 +1,71 @@
+package usid
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrRateLimited is returned by TryGenerate when the generator has used up
+// its rate limit (see WithMaxRate).
+var ErrRateLimited = errors.New("usid: rate limited")
+
+// WithMaxRate limits the generator to perSecond IDs per second on average,
+// allowing bursts of up to burst IDs. Once the limit is reached, Generate
+// sleeps until the next ID is allowed instead of spinning through the
+// sequence space, and TryGenerate returns ErrRateLimited. Pacing uses the
+// monotonic clock, so it is unaffected by WithClock and wall clock steps.
+// Panics if perSecond is not positive or burst is less than 1.
+func WithMaxRate(perSecond float64, burst int) Option {
+	if perSecond <= 0 || burst < 1 {
+		panic("usid: invalid rate limit")
+	}
+	interval := max(int64(float64(time.Second)/perSecond), 1)
+	return func(g *Generator) {
+		g.limit = &rateLimit{
+			start:     time.Now(),
+			interval:  interval,
+			tolerance: int64(burst-1) * interval,
+		}
+	}
+}
+
+// rateLimit is a lock-free generic cell rate algorithm limiter: tat is the
+// theoretical arrival time of the next ID, in nanoseconds since start.
+type rateLimit struct {
+	start     time.Time
+	interval  int64 // nanoseconds between IDs at the sustained rate
+	tolerance int64 // how far ahead of now tat may run (the burst)
+	tat       atomic.Int64
+}
+
+// reserve claims the next slot and returns how long the caller must wait
+// before using it. If wait is false and the caller would have to wait, it
+// claims nothing and returns false.
+func (l *rateLimit) reserve(wait bool) (time.Duration, bool) {
+	now := int64(time.Since(l.start))
+	for {
+		tat := l.tat.Load()
+		t := max(tat, now)
+		delay := t - l.tolerance - now
+		if delay > 0 && !wait {
+			return 0, false
+		}
+		if l.tat.CompareAndSwap(tat, t+l.interval) {
+			return time.Duration(max(delay, 0)), true
+		}
+	}
+}
+
+// TryGenerate is like Generate, but returns ErrRateLimited instead of
+// waiting when the generator's rate limit is exhausted. Without WithMaxRate
+// it never fails.
+// Safe for concurrent use.
+func (g *Generator) TryGenerate() (ID, error) {
+	if g.limit != nil {
+		if _, ok := g.limit.reserve(false); !ok {
+			return Nil, ErrRateLimited
+		}
+	}
+	return g.generate(), nil
+}
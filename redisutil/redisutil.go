@@ -0,0 +1,147 @@
+// Package redisutil formats and parses IDs stored in Redis, either as keys
+// ("order:0000gb61dv03w") or as values in ID-to-ID mappings. It depends on no
+// Redis client; pass its strings to go-redis, rueidis, or redigo as usual.
+//
+// Values are easy to get subtly wrong: usid.ID implements
+// encoding.BinaryMarshaler, so go-redis writes an ID passed directly as a
+// command argument as 8 raw bytes, while redis-cli and Lua scripts see the
+// text a human would expect only if the ID was formatted first. Pick one
+// representation per key pattern and use the matching pair of functions:
+// Value and ParseValue for text, Binary and ParseBinary for raw bytes.
+package redisutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/crockford"
+)
+
+// Separator joins a key prefix to the encoded ID.
+const Separator = ":"
+
+// ErrPrefix is returned by ParseKey for keys outside the given prefix.
+var ErrPrefix = errors.New("usid: redis key has the wrong prefix")
+
+// IsNil reports whether err is a client's "key does not exist" reply. The
+// default matches go-redis's redis.Nil; replace it for other clients.
+var IsNil = func(err error) bool {
+	return err != nil && err.Error() == "redis: nil"
+}
+
+// Key returns prefix, Separator, and id as fixed-width Crockford Base32, so
+// keys sort lexically in time order for ZRANGE BYLEX. id must not be
+// negative. Keys are never obfuscated, since obfuscation would
+// break that order; don't expose them to clients.
+func Key(prefix string, id usid.ID) string {
+	return prefix + Separator + crockford.EncodeFixed(id.Int64())
+}
+
+// ParseKey returns the ID in a key built by Key with the same prefix.
+func ParseKey(prefix, key string) (usid.ID, error) {
+	rest, ok := strings.CutPrefix(key, prefix+Separator)
+	if !ok {
+		return usid.Nil, fmt.Errorf("%w: %q", ErrPrefix, key)
+	}
+	if len(rest) != crockford.MaxLen {
+		return usid.Nil, crockford.ErrInvalid
+	}
+	n, err := crockford.Decode(rest)
+	return usid.ID(n), err
+}
+
+// LexRange returns ZRANGE BYLEX bounds matching members Key(prefix, id) for
+// IDs generated in [from, to). The members must all share the same score.
+func LexRange(prefix string, from, to time.Time) (min, max string) {
+	return "[" + Key(prefix, usid.MinIDForTime(from)), "(" + Key(prefix, usid.MinIDForTime(to))
+}
+
+// Value returns id as text in usid.DefaultFormat, for use as a Redis value.
+func Value(id usid.ID) string {
+	return id.String()
+}
+
+// ParseValue parses a value written by Value, as returned by GET, HGET, or
+// one element of an MGET reply. A nil reply is a missing key and yields a
+// NULL NullID.
+func ParseValue(v any) (usid.NullID, error) {
+	switch v := v.(type) {
+	case nil:
+		return usid.NullID{}, nil
+	case string:
+		id, err := usid.Parse(v)
+		return usid.NullID{ID: id, Valid: err == nil}, err
+	case []byte:
+		id, err := usid.Parse(string(v))
+		return usid.NullID{ID: id, Valid: err == nil}, err
+	default:
+		return usid.NullID{}, fmt.Errorf("usid: unexpected redis reply type %T", v)
+	}
+}
+
+// ParseValues parses an MGET or HMGET reply element by element.
+func ParseValues(vals []any) ([]usid.NullID, error) {
+	ids := make([]usid.NullID, len(vals))
+	for i, v := range vals {
+		id, err := ParseValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("usid: redis reply %d: %w", i, err)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// Binary returns id as 8 big-endian bytes, the form go-redis writes for an
+// ID passed as a command argument. It halves memory for large mappings but
+// is unreadable in redis-cli and Lua.
+func Binary(id usid.ID) string {
+	return string(id.Bytes())
+}
+
+// ParseBinary parses a value written by Binary or by go-redis from a bare
+// usid.ID argument. A nil reply yields a NULL NullID.
+func ParseBinary(v any) (usid.NullID, error) {
+	var b []byte
+	switch v := v.(type) {
+	case nil:
+		return usid.NullID{}, nil
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	default:
+		return usid.NullID{}, fmt.Errorf("usid: unexpected redis reply type %T", v)
+	}
+	id, err := usid.FromBytes(b)
+	return usid.NullID{ID: id, Valid: err == nil}, err
+}
+
+// StringResult is the result of a pipelined command returning a string,
+// such as go-redis's *redis.StringCmd.
+type StringResult interface {
+	Result() (string, error)
+}
+
+// ParseResults parses the text values returned by pipelined GET or HGET
+// commands. Missing keys (see IsNil) yield NULL NullIDs; any other command
+// error is returned.
+func ParseResults[C StringResult](cmds []C) ([]usid.NullID, error) {
+	ids := make([]usid.NullID, len(cmds))
+	for i, cmd := range cmds {
+		s, err := cmd.Result()
+		if IsNil(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("usid: redis command %d: %w", i, err)
+		}
+		if ids[i], err = ParseValue(s); err != nil {
+			return nil, fmt.Errorf("usid: redis command %d: %w", i, err)
+		}
+	}
+	return ids, nil
+}
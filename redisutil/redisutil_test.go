@@ -0,0 +1,81 @@
+package redisutil_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/redisutil"
+)
+
+type result struct {
+	s   string
+	err error
+}
+
+func (r result) Result() (string, error) { return r.s, r.err }
+
+func TestKey(t *testing.T) {
+	a, b := usid.ID(31), usid.New()
+	ka, kb := redisutil.Key("order", a), redisutil.Key("order", b)
+	if ka != "order:000000000000z" {
+		t.Errorf("Key(31) = %q", ka)
+	}
+	if ka >= kb {
+		t.Errorf("Key(%d) = %q sorts after Key(%d) = %q", a, ka, b, kb)
+	}
+	if got, err := redisutil.ParseKey("order", kb); err != nil || got != b {
+		t.Errorf("ParseKey(%q) = %d, %v; want %d", kb, got, err, b)
+	}
+	if _, err := redisutil.ParseKey("user", kb); !errors.Is(err, redisutil.ErrPrefix) {
+		t.Errorf("ParseKey with wrong prefix: err = %v, want ErrPrefix", err)
+	}
+	if _, err := redisutil.ParseKey("order", "order:123"); err == nil {
+		t.Error("ParseKey of short key: want err != nil")
+	}
+
+	now := b.Timestamp()
+	min, max := redisutil.LexRange("order", now.Add(-time.Second), now.Add(time.Second))
+	if !(min[1:] <= kb && kb < max[1:]) || min[0] != '[' || max[0] != '(' {
+		t.Errorf("LexRange = %q, %q; want range containing %q", min, max, kb)
+	}
+}
+
+func TestValues(t *testing.T) {
+	id := usid.New()
+	vals := []any{redisutil.Value(id), nil, []byte(redisutil.Value(id))}
+	got, err := redisutil.ParseValues(vals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []usid.NullID{usid.NullIDFrom(id), {}, usid.NullIDFrom(id)}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseValues = %v, want %v", got, want)
+	}
+	if _, err := redisutil.ParseValues([]any{int64(1)}); err == nil {
+		t.Error("ParseValues of integer reply: want err != nil")
+	}
+
+	if got, err := redisutil.ParseBinary(redisutil.Binary(id)); err != nil || got != usid.NullIDFrom(id) {
+		t.Errorf("ParseBinary(Binary(id)) = %v, %v", got, err)
+	}
+	if _, err := redisutil.ParseBinary(redisutil.Value(id)); err == nil && len(redisutil.Value(id)) != 8 {
+		t.Error("ParseBinary of text value: want err != nil")
+	}
+}
+
+func TestParseResults(t *testing.T) {
+	id := usid.New()
+	got, err := redisutil.ParseResults([]result{{s: redisutil.Value(id)}, {err: errors.New("redis: nil")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []usid.NullID{usid.NullIDFrom(id), {}}; !slices.Equal(got, want) {
+		t.Errorf("ParseResults = %v, want %v", got, want)
+	}
+	if _, err := redisutil.ParseResults([]result{{err: errors.New("i/o timeout")}}); err == nil {
+		t.Error("ParseResults with command error: want err != nil")
+	}
+}
@@ -0,0 +1,109 @@
+package usid
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// Compile-time interface checks for Set
+var (
+	_ json.Marshaler   = Set(nil)
+	_ json.Unmarshaler = (*Set)(nil)
+)
+
+// Set is an unordered collection of unique IDs.
+// It marshals to JSON as a sorted array of ID strings.
+type Set map[ID]struct{}
+
+// NewSet returns a Set containing ids.
+func NewSet(ids ...ID) Set {
+	s := make(Set, len(ids))
+	for _, id := range ids {
+		s[id] = struct{}{}
+	}
+	return s
+}
+
+// Add inserts ids into the set.
+func (s Set) Add(ids ...ID) {
+	for _, id := range ids {
+		s[id] = struct{}{}
+	}
+}
+
+// Remove deletes ids from the set.
+func (s Set) Remove(ids ...ID) {
+	for _, id := range ids {
+		delete(s, id)
+	}
+}
+
+// Contains reports whether id is in the set.
+func (s Set) Contains(id ID) bool {
+	_, ok := s[id]
+	return ok
+}
+
+// Len returns the number of IDs in the set.
+func (s Set) Len() int {
+	return len(s)
+}
+
+// Union returns a new set with the IDs in either s or other.
+func (s Set) Union(other Set) Set {
+	out := make(Set, len(s)+len(other))
+	for id := range s {
+		out[id] = struct{}{}
+	}
+	for id := range other {
+		out[id] = struct{}{}
+	}
+	return out
+}
+
+// Intersect returns a new set with the IDs in both s and other.
+func (s Set) Intersect(other Set) Set {
+	small, large := s, other
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	out := make(Set, len(small))
+	for id := range small {
+		if _, ok := large[id]; ok {
+			out[id] = struct{}{}
+		}
+	}
+	return out
+}
+
+// Slice returns the IDs in ascending order.
+func (s Set) Slice() []ID {
+	out := make([]ID, 0, len(s))
+	for id := range s {
+		out = append(out, id)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// MarshalJSON encodes the set as a sorted array of ID strings.
+func (s Set) MarshalJSON() ([]byte, error) {
+	if s == nil {
+		return nullJSON, nil
+	}
+	return json.Marshal(s.Slice())
+}
+
+// UnmarshalJSON decodes an array of IDs, replacing the set's contents.
+func (s *Set) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*s = nil
+		return nil
+	}
+	var ids []ID
+	if err := json.Unmarshal(b, &ids); err != nil {
+		return err
+	}
+	*s = NewSet(ids...)
+	return nil
+}
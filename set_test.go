@@ -0,0 +1,47 @@
+package usid
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	s := NewSet(1, 2, 3)
+	s.Add(4)
+	s.Remove(1)
+	if !s.Contains(4) || s.Contains(1) || s.Len() != 3 {
+		t.Errorf("set = %v, want {2 3 4}", s.Slice())
+	}
+
+	other := NewSet(3, 4, 5)
+	if got, want := s.Union(other).Slice(), []ID{2, 3, 4, 5}; !slices.Equal(got, want) {
+		t.Errorf("Union = %v, want %v", got, want)
+	}
+	if got, want := s.Intersect(other).Slice(), []ID{3, 4}; !slices.Equal(got, want) {
+		t.Errorf("Intersect = %v, want %v", got, want)
+	}
+}
+
+func TestSetJSON(t *testing.T) {
+	s := NewSet(testID, 1)
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `["` + ID(1).String() + `","` + testID.String() + `"]`
+	if string(data) != want {
+		t.Errorf("json.Marshal = %s, want %s", data, want)
+	}
+
+	var got Set
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got.Slice(), s.Slice()) {
+		t.Errorf("roundtrip = %v, want %v", got.Slice(), s.Slice())
+	}
+	if err := json.Unmarshal([]byte(`["!!"]`), &got); err == nil {
+		t.Error("json.Unmarshal(invalid): want err != nil")
+	}
+}
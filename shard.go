@@ -0,0 +1,26 @@
+package usid
+
+// Shard returns the shard in [0, n) that owns the ID, using jump consistent
+// hashing (Lamping & Veach, 2014) over the raw int64 value. The mapping is
+// stable across processes and languages, and growing n from k to k+1 moves
+// only about 1/(k+1) of IDs. Panics if n <= 0.
+func (id ID) Shard(n int) int {
+	if n <= 0 {
+		panic("usid: Shard requires n > 0")
+	}
+	key := uint64(id)
+	var b, j int64 = -1, 0
+	for j < int64(n) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+// PartitionKey returns the 8-byte big-endian raw value for use as a Kafka
+// message key. Kafka's default partitioner hashes key bytes, so every event
+// for an ID lands on the same partition regardless of DefaultFormat or obfuscation.
+func (id ID) PartitionKey() []byte {
+	return id.Bytes()
+}
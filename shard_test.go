@@ -0,0 +1,54 @@
+package usid
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestShard(t *testing.T) {
+	// Golden values guard against accidental changes to the routing scheme.
+	golden := []struct {
+		id   ID
+		n    int
+		want int
+	}{
+		{0, 1, 0},
+		{1, 1000, 549},
+		{testID, 10, 9},
+		{testID, 1000, 888},
+	}
+	for _, tt := range golden {
+		if got := tt.id.Shard(tt.n); got != tt.want {
+			t.Errorf("ID(%d).Shard(%d) = %d, want %d", tt.id, tt.n, got, tt.want)
+		}
+	}
+
+	const n = 16
+	counts := make([]int, n)
+	moved := 0
+	for i := 0; i < 10000; i++ {
+		id := New()
+		s := id.Shard(n)
+		if s < 0 || s >= n {
+			t.Fatalf("Shard(%d) = %d, out of range", n, s)
+		}
+		counts[s]++
+		if id.Shard(n+1) != s {
+			moved++
+		}
+	}
+	for s, c := range counts {
+		if c < 10000/n/2 {
+			t.Errorf("shard %d got %d IDs, want roughly %d", s, c, 10000/n)
+		}
+	}
+	if moved > 10000/(n+1)*2 {
+		t.Errorf("growing to %d shards moved %d IDs, want about %d", n+1, moved, 10000/(n+1))
+	}
+}
+
+func TestPartitionKey(t *testing.T) {
+	if got := testID.PartitionKey(); !bytes.Equal(got, testID.Bytes()) {
+		t.Errorf("PartitionKey() = %x, want %x", got, testID.Bytes())
+	}
+}
@@ -0,0 +1,147 @@
+package usid
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"strings"
+
+	"github.com/paraglidehq/usid/v2/crockford"
+)
+
+// SignatureLen is the number of Crockford Base32 characters a Signer appends
+// to each ID: a 40-bit truncated HMAC-SHA256.
+const SignatureLen = 8
+
+// ErrSignature is returned when a signed ID is malformed or fails verification.
+var ErrSignature = errors.New("usid: invalid ID signature")
+
+// Signer encodes IDs with an HMAC tag so clients cannot fabricate or
+// increment them, even if the obfuscation key is recovered. A signed ID is
+// the Crockford encoding of the (obfuscated) ID, read as unsigned, followed
+// by SignatureLen characters of HMAC-SHA256 over the raw ID. Each ID has
+// exactly one signed form: Verify accepts only the lowercase encoding Sign
+// produces, so signed IDs can serve as cache or deduplication keys.
+type Signer struct {
+	key  []byte
+	name Format // set by Register, for OnParseFailure
+}
+
+// NewSigner creates a Signer with the given secret key.
+// Use at least 32 random bytes and keep it secret. Panics if key is empty.
+func NewSigner(key []byte) *Signer {
+	if len(key) == 0 {
+		panic("usid: NewSigner with empty key")
+	}
+	return &Signer{key: key}
+}
+
+// Register makes signed IDs available as a format under the given name, so
+// they work with ID.Format, Parse, DefaultFormat, and JSON. Parse rejects
+// strings without a valid signature with ErrSignature.
+// Panics under the same conditions as RegisterFormat.
+func (s *Signer) Register(name string) {
+	s.name = Format(name)
+	RegisterFormat(name, s.encode, s.decode)
+}
+
+// Sign returns the signed string form of id, obfuscated with
+// DefaultObfuscator if set.
+func (s *Signer) Sign(id ID) string {
+	return s.encode(obfuscate(id))
+}
+
+// Verify parses a string produced by Sign and returns the ID, or
+// ErrSignature if the signature does not match. Failures are reported to
+// OnParseFailure under the name the Signer was registered with, if any.
+func (s *Signer) Verify(str string) (ID, error) {
+	id, err := s.decode(str)
+	if err != nil {
+		reportFailure(str, s.name, err)
+		return Nil, err
+	}
+	return deobfuscate(id), nil
+}
+
+// encode signs an already-obfuscated ID.
+func (s *Signer) encode(id ID) string {
+	tag := s.tag(deobfuscate(id))
+	return encodeUnsigned(uint64(id)) + crockford.EncodeFixed(int64(tagValue(tag[:])))[crockford.MaxLen-SignatureLen:]
+}
+
+// decode verifies str and returns the obfuscated ID.
+func (s *Signer) decode(str string) (ID, error) {
+	if len(str) <= SignatureLen {
+		return Nil, ErrSignature
+	}
+	n, err := decodeUnsigned(str[:len(str)-SignatureLen])
+	if err != nil {
+		return Nil, err
+	}
+	// Padded to MaxLen, DecodeStrict accepts the tag's leading zeros
+	sig, err := crockford.DecodeStrict(strings.Repeat("0", crockford.MaxLen-SignatureLen) + str[len(str)-SignatureLen:])
+	if err != nil {
+		return Nil, ErrSignature
+	}
+	var got [8]byte
+	binary.BigEndian.PutUint64(got[:], uint64(sig))
+	want := s.tag(deobfuscate(ID(n)))
+	if !hmac.Equal(got[3:], want[:]) {
+		return Nil, ErrSignature
+	}
+	return ID(n), nil
+}
+
+// lowBits is the number of bits below the first of the MaxLen digits an
+// unsigned ID takes at most.
+const lowBits = 5 * (crockford.MaxLen - 1)
+
+// encodeUnsigned returns the Crockford encoding of n, which unlike
+// crockford.Encode covers IDs whose obfuscation set the sign bit.
+func encodeUnsigned(n uint64) string {
+	high, low := int64(n>>lowBits), int64(n&(1<<lowBits-1))
+	if high == 0 {
+		return crockford.Encode(low)
+	}
+	return crockford.Encode(high) + crockford.EncodeFixed(low)[1:]
+}
+
+// decodeUnsigned parses the canonical encoding produced by encodeUnsigned.
+func decodeUnsigned(s string) (uint64, error) {
+	if len(s) < crockford.MaxLen {
+		n, err := crockford.DecodeStrict(s)
+		return uint64(n), err
+	}
+	if len(s) > crockford.MaxLen {
+		return 0, crockford.ErrInvalid
+	}
+	high, err := crockford.DecodeStrict(s[:1])
+	if err != nil {
+		return 0, err
+	}
+	if high == 0 {
+		return 0, crockford.ErrNotCanonical
+	}
+	low, err := crockford.DecodeStrict("0" + s[1:])
+	if err != nil {
+		return 0, err
+	}
+	return uint64(high)<<lowBits | uint64(low), nil
+}
+
+// tag returns the first 40 bits of HMAC-SHA256 over the raw ID.
+func (s *Signer) tag(raw ID) [5]byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(raw))
+	m := hmac.New(sha256.New, s.key)
+	m.Write(b[:])
+	return [5]byte(m.Sum(nil))
+}
+
+// tagValue packs a tag into an integer for encoding.
+func tagValue(tag []byte) uint64 {
+	var b [8]byte
+	copy(b[3:], tag)
+	return binary.BigEndian.Uint64(b[:])
+}
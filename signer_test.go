@@ -0,0 +1,92 @@
+package usid
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/paraglidehq/usid/v2/crockford"
+)
+
+func TestSigner(t *testing.T) {
+	s := NewSigner([]byte("0123456789abcdef0123456789abcdef"))
+	id := New()
+
+	str := s.Sign(id)
+	got, err := s.Verify(str)
+	if err != nil || got != id {
+		t.Fatalf("Verify(Sign(%d)) = %d, %v", id, got, err)
+	}
+
+	// Incrementing the ID part or editing the tag must fail.
+	next := crockfordOf(id+1) + str[len(str)-SignatureLen:]
+	if _, err := s.Verify(next); !errors.Is(err, ErrSignature) {
+		t.Errorf("Verify(incremented) err = %v, want ErrSignature", err)
+	}
+	tampered := []byte(str)
+	tampered[len(tampered)-1] ^= 1
+	if _, err := s.Verify(string(tampered)); err == nil {
+		t.Error("Verify(tampered tag) succeeded")
+	}
+	if _, err := s.Verify("abc"); !errors.Is(err, ErrSignature) {
+		t.Errorf("Verify(short) err = %v, want ErrSignature", err)
+	}
+	if _, err := NewSigner([]byte("other key")).Verify(str); !errors.Is(err, ErrSignature) {
+		t.Errorf("Verify(other key) err = %v, want ErrSignature", err)
+	}
+
+	// Only the exact form Sign produces verifies
+	for _, alias := range []string{
+		strings.ToUpper(str),
+		str[:3] + "-" + str[3:],
+		"0" + str,
+		strings.Replace(str, "1", "l", 1),
+	} {
+		if alias == str {
+			continue
+		}
+		if _, err := s.Verify(alias); err == nil {
+			t.Errorf("Verify(%q) of %q succeeded", alias, str)
+		}
+	}
+}
+
+func TestSignerNegative(t *testing.T) {
+	// A key with the sign bit set makes every obfuscated ID negative
+	DefaultObfuscator = NewObfuscator(-0x5eed)
+	defer func() { DefaultObfuscator = nil }()
+
+	s := NewSigner([]byte("0123456789abcdef0123456789abcdef"))
+	for _, id := range []ID{Nil, 1, New(), Omni} {
+		str := s.Sign(id)
+		if len(str) != crockford.MaxLen+SignatureLen {
+			t.Errorf("Sign(%d) = %q, want %d characters", id, str, crockford.MaxLen+SignatureLen)
+		}
+		if got, err := s.Verify(str); err != nil || got != id {
+			t.Errorf("Verify(Sign(%d)) = %d, %v", id, got, err)
+		}
+	}
+}
+
+func TestSignerFormat(t *testing.T) {
+	const f = Format("test-signed")
+	NewSigner([]byte("format key")).Register(string(f))
+
+	DefaultObfuscator = NewObfuscator(0x5eed)
+	defer func() { DefaultObfuscator = nil }()
+
+	id := New()
+	str := id.Format(f)
+	got, err := ParseFormat(str, f)
+	if err != nil || got != id {
+		t.Fatalf("ParseFormat(%q) = %d, %v; want %d", str, got, err, id)
+	}
+	if _, err := ParseFormat(crockfordOf(id)+"00000000", f); !errors.Is(err, ErrSignature) {
+		t.Errorf("ParseFormat(forged) err = %v, want ErrSignature", err)
+	}
+}
+
+// crockfordOf encodes id the way a Signer encodes its ID part.
+func crockfordOf(id ID) string {
+	return encodeUnsigned(uint64(obfuscate(id)))
+}
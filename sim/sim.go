@@ -0,0 +1,212 @@
+// Package sim simulates a fleet of generators on simulated clocks, for
+// capacity planning: how many nodes, at what rates and with how much clock
+// skew, a layout can serve before IDs collide, sort out of order, or stall
+// waiting for the sequence to roll over.
+//
+// The simulation drives real usid.Generators in the current layout, so its
+// results reflect the generator's actual behaviour rather than hand math.
+package sim
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+// Scenario describes a simulation run.
+type Scenario struct {
+	// Start is the simulated time at which the run begins (default: now).
+	Start time.Time
+
+	// Duration is the length of the run in simulated time.
+	Duration time.Duration
+
+	Nodes []Node
+}
+
+// Node describes one simulated generator.
+type Node struct {
+	// ID is the node ID (default: the node's index in Scenario.Nodes plus 1).
+	// Give two nodes the same ID to simulate a misassignment.
+	ID int64
+
+	// Rate is the number of IDs the node generates per second, evenly spaced.
+	// At most one ID per nanosecond.
+	Rate float64
+
+	// Skew is the offset of the node's clock from true time.
+	Skew time.Duration
+
+	// Regressions step the node's clock backwards during the run, as NTP
+	// corrections and VM migrations do.
+	Regressions []Regression
+
+	// Options are passed to usid.NewGenerator.
+	Options []usid.Option
+}
+
+// Regression steps a node's clock back by By at offset At from the start of
+// the run.
+type Regression struct {
+	At time.Duration
+	By time.Duration
+}
+
+// Report summarizes a simulation run.
+type Report struct {
+	Generated int `json:"generated"`
+
+	// Collisions counts IDs generated more than once across the fleet.
+	Collisions int `json:"collisions"`
+
+	// OutOfOrder counts IDs smaller than an ID generated earlier in true
+	// time by any node. Some are expected whenever clocks are skewed;
+	// MaxInversion says by how much.
+	OutOfOrder int `json:"out_of_order"`
+
+	// MaxInversion is the largest amount by which an ID's timestamp preceded
+	// that of an ID generated earlier in true time.
+	MaxInversion time.Duration `json:"max_inversion"`
+
+	// Stalled counts IDs whose generation had to wait for the next tick
+	// because the sequence was exhausted, and MaxDelay is the longest such
+	// wait, including any backlog it caused.
+	Stalled  int           `json:"stalled"`
+	MaxDelay time.Duration `json:"max_delay"`
+
+	// Nodes holds each generator's counters at the end of the run, in the
+	// order of Scenario.Nodes.
+	Nodes []usid.GeneratorStats `json:"nodes"`
+}
+
+// node is the state of one simulated generator.
+type node struct {
+	g        *usid.Generator
+	skew     time.Duration
+	interval time.Duration
+	regs     []Regression
+
+	next  time.Duration // scheduled time of the next ID
+	busy  time.Duration // true time at which the previous ID was done
+	now   time.Duration // true time during the current Generate call
+	back  time.Duration // sum of regressions applied so far
+	calls int           // clock readings during the current Generate call
+}
+
+// Run simulates s and reports what went wrong. Every node generates its IDs
+// on one goroutine, in true-time order across the fleet.
+func Run(s Scenario) (Report, error) {
+	var r Report
+	if s.Duration <= 0 {
+		return r, errors.New("usid: simulation duration must be positive")
+	}
+	if len(s.Nodes) == 0 {
+		return r, errors.New("usid: simulation needs at least one node")
+	}
+	start := s.Start
+	if start.IsZero() {
+		start = time.Now()
+	}
+	cfg := usid.CurrentConfig()
+	tick := usid.Precision
+
+	nodes := make([]*node, len(s.Nodes))
+	for i, spec := range s.Nodes {
+		if spec.Rate <= 0 {
+			return r, fmt.Errorf("usid: simulated node %d has no rate", i)
+		}
+		id := spec.ID
+		if id == 0 {
+			id = int64(i) + 1
+		}
+		if id < 0 || id > cfg.MaxNode() {
+			return r, fmt.Errorf("usid: simulated node ID %d does not fit in %d node bits", id, cfg.NodeBits)
+		}
+		interval := time.Duration(float64(time.Second) / spec.Rate)
+		if interval <= 0 {
+			return r, fmt.Errorf("usid: simulated node %d rate %g exceeds one ID per nanosecond", i, spec.Rate)
+		}
+		n := &node{
+			skew:     spec.Skew,
+			interval: interval,
+			regs:     spec.Regressions,
+		}
+		// Stagger nodes across the first interval so they don't move in lockstep.
+		n.next = n.interval * time.Duration(i) / time.Duration(len(s.Nodes))
+		n.g = usid.NewGenerator(id, append(slices.Clone(spec.Options), usid.WithClock(func() time.Time {
+			// Every reading after the first means the generator is waiting
+			// for the next tick, so let true time pass.
+			if n.calls++; n.calls > 1 {
+				n.now += tick
+			}
+			return start.Add(n.now + n.skew - n.back)
+		}))...)
+		nodes[i] = n
+	}
+
+	seen := make(map[usid.ID]struct{})
+	var highest usid.ID
+	for {
+		var n *node
+		for _, c := range nodes {
+			if c.next < s.Duration && (n == nil || c.next < n.next) {
+				n = c
+			}
+		}
+		if n == nil {
+			break
+		}
+		for len(n.regs) > 0 && n.regs[0].At <= n.next {
+			n.back += n.regs[0].By
+			n.regs = n.regs[1:]
+		}
+
+		n.now, n.calls = n.next, 0
+		if n.busy > n.now {
+			n.now = n.busy
+		}
+		id := n.g.Generate()
+		if n.calls > 1 {
+			r.Stalled++
+		}
+		r.MaxDelay = max(r.MaxDelay, n.now-n.next)
+		n.busy = n.now
+		n.next += n.interval
+
+		r.Generated++
+		if _, dup := seen[id]; dup {
+			r.Collisions++
+		}
+		seen[id] = struct{}{}
+		if id < highest {
+			r.OutOfOrder++
+			r.MaxInversion = max(r.MaxInversion, highest.Timestamp().Sub(id.Timestamp()))
+		} else {
+			highest = id
+		}
+	}
+
+	for _, n := range nodes {
+		r.Nodes = append(r.Nodes, n.g.Stats())
+	}
+	return r, nil
+}
+
+// String formats the report for terminals.
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ids:           %d\n", r.Generated)
+	fmt.Fprintf(&b, "collisions:    %d\n", r.Collisions)
+	fmt.Fprintf(&b, "out of order:  %d (max %s)\n", r.OutOfOrder, r.MaxInversion)
+	fmt.Fprintf(&b, "stalled:       %d (max %s)\n", r.Stalled, r.MaxDelay)
+	b.WriteString("nodes:\n")
+	for _, n := range r.Nodes {
+		fmt.Fprintf(&b, "  %4d generated=%d waits=%d regressions=%d\n",
+			n.Node, n.Generated, n.ExhaustionWaits, n.ClockRegressions)
+	}
+	return b.String()
+}
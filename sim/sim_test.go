@@ -0,0 +1,121 @@
+package sim_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/sim"
+)
+
+var start = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestHealthyFleet(t *testing.T) {
+	r, err := sim.Run(sim.Scenario{
+		Start:    start,
+		Duration: time.Second,
+		Nodes:    []sim.Node{{Rate: 10000}, {Rate: 10000}, {Rate: 10000}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Generated != 30000 || r.Collisions != 0 || r.OutOfOrder != 0 || r.Stalled != 0 {
+		t.Errorf("report:\n%s", r)
+	}
+	if len(r.Nodes) != 3 || r.Nodes[2].Node != 3 || r.Nodes[2].Generated != 10000 {
+		t.Errorf("node stats = %+v", r.Nodes)
+	}
+}
+
+func TestSkewAndMisassignment(t *testing.T) {
+	r, err := sim.Run(sim.Scenario{
+		Start:    start,
+		Duration: 100 * time.Millisecond,
+		Nodes: []sim.Node{
+			{Rate: 10000},
+			{Rate: 10000, Skew: -5 * time.Millisecond},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.OutOfOrder == 0 || r.MaxInversion < 4*time.Millisecond || r.MaxInversion > 6*time.Millisecond {
+		t.Errorf("skewed node: out of order %d, max inversion %s; want ~5ms", r.OutOfOrder, r.MaxInversion)
+	}
+
+	// The second node is staggered by half an interval; its skew puts both
+	// nodes' clocks on the same ticks.
+	r, err = sim.Run(sim.Scenario{
+		Start:    start,
+		Duration: 100 * time.Millisecond,
+		Nodes: []sim.Node{
+			{ID: 1, Rate: 10000},
+			{ID: 1, Rate: 10000, Skew: -50 * time.Microsecond},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Collisions == 0 {
+		t.Errorf("shared node ID produced no collisions:\n%s", r)
+	}
+}
+
+func TestExhaustionAndRegression(t *testing.T) {
+	// 64 sequence values per microsecond cannot keep up with 100M IDs/s.
+	r, err := sim.Run(sim.Scenario{
+		Start:    start,
+		Duration: 10 * time.Microsecond,
+		Nodes:    []sim.Node{{Rate: 1e8}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Stalled == 0 || r.MaxDelay <= 0 || r.Collisions != 0 {
+		t.Errorf("overloaded node:\n%s", r)
+	}
+
+	r, err = sim.Run(sim.Scenario{
+		Start:    start,
+		Duration: 10 * time.Millisecond,
+		Nodes: []sim.Node{{
+			Rate:        100000,
+			Regressions: []sim.Regression{{At: 5 * time.Millisecond, By: time.Millisecond}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Nodes[0].ClockRegressions == 0 || r.OutOfOrder != 0 || r.Collisions != 0 {
+		t.Errorf("regressing node:\n%s", r)
+	}
+}
+
+func TestInvalidScenario(t *testing.T) {
+	for _, s := range []sim.Scenario{
+		{Nodes: []sim.Node{{Rate: 1}}},
+		{Duration: time.Second},
+		{Duration: time.Second, Nodes: []sim.Node{{}}},
+		{Duration: time.Second, Nodes: []sim.Node{{Rate: 1, ID: usid.CurrentConfig().MaxNode() + 1}}},
+		{Duration: time.Second, Nodes: []sim.Node{{Rate: 2e9}}},
+	} {
+		if _, err := sim.Run(s); err == nil {
+			t.Errorf("Run(%+v): want err != nil", s)
+		}
+	}
+}
+
+func TestOptionsNotModified(t *testing.T) {
+	opts := make([]usid.Option, 1, 2)
+	opts[0] = usid.WithMonotonic()
+	if _, err := sim.Run(sim.Scenario{
+		Start:    start,
+		Duration: time.Millisecond,
+		Nodes:    []sim.Node{{Rate: 1000, Options: opts}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if opts[:2][1] != nil {
+		t.Error("Run wrote into the spare capacity of Node.Options")
+	}
+}
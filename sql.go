@@ -5,6 +5,10 @@ import (
 	"database/sql/driver"
 	"encoding"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 )
 
 // NullID can be used with the standard sql package to represent an
@@ -24,6 +28,33 @@ var (
 	_ encoding.TextUnmarshaler = (*NullID)(nil)
 )
 
+// NullIDFrom returns a valid NullID holding id.
+func NullIDFrom(id ID) NullID {
+	return NullID{ID: id, Valid: true}
+}
+
+// NullIDFromPtr returns a NullID holding *id, or NULL if id is nil.
+func NullIDFromPtr(id *ID) NullID {
+	if id == nil {
+		return NullID{}
+	}
+	return NullIDFrom(*id)
+}
+
+// Ptr returns a pointer to a copy of the ID, or nil if n is NULL.
+func (n NullID) Ptr() *ID {
+	if !n.Valid {
+		return nil
+	}
+	return Ptr(n.ID)
+}
+
+// IsZero reports whether the NullID is NULL or holds the Nil ID, so `omitzero`
+// and ORMs treat both as an absent value.
+func (n NullID) IsZero() bool {
+	return !n.Valid || n.ID.IsZero()
+}
+
 // Value implements the driver.Valuer interface.
 func (n NullID) Value() (driver.Value, error) {
 	if !n.Valid {
@@ -82,3 +113,75 @@ func (n *NullID) UnmarshalText(b []byte) error {
 	n.Valid = (err == nil)
 	return err
 }
+
+// IDArray represents a Postgres bigint[] (int8[]) of IDs, so a []ID can be
+// passed directly to queries like `WHERE id = ANY($1)`. It encodes to the
+// Postgres array literal, which lib/pq and pgx's database/sql driver both accept.
+// Elements are always stored as raw int64 values, regardless of SQLFormat.
+type IDArray []ID
+
+// Compile-time interface checks for IDArray
+var (
+	_ driver.Valuer = IDArray(nil)
+	_ sql.Scanner   = (*IDArray)(nil)
+)
+
+// Value implements the driver.Valuer interface.
+func (a IDArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	b := make([]byte, 0, 2+len(a)*20)
+	b = append(b, '{')
+	for i, id := range a {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = strconv.AppendInt(b, int64(id), 10)
+	}
+	b = append(b, '}')
+	return string(b), nil
+}
+
+// Scan implements the sql.Scanner interface.
+// Only one-dimensional arrays without NULL elements are supported.
+func (a *IDArray) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		return a.parse(string(v))
+	case string:
+		return a.parse(v)
+	default:
+		return fmt.Errorf("usid: cannot scan %T into IDArray", src)
+	}
+}
+
+// parse decodes a Postgres array literal such as "{1,2,3}".
+func (a *IDArray) parse(s string) error {
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return fmt.Errorf("usid: invalid array literal %q", s)
+	}
+	s = s[1 : len(s)-1]
+	if s == "" {
+		*a = IDArray{}
+		return nil
+	}
+	elems := strings.Split(s, ",")
+	out := make(IDArray, len(elems))
+	for i, e := range elems {
+		e = strings.TrimSpace(e)
+		if strings.EqualFold(e, "NULL") {
+			return errors.New("usid: cannot scan NULL array element into IDArray")
+		}
+		n, err := strconv.ParseInt(e, 10, 64)
+		if err != nil {
+			return fmt.Errorf("usid: invalid array element %q: %w", e, err)
+		}
+		out[i] = ID(n)
+	}
+	*a = out
+	return nil
+}
@@ -10,8 +10,11 @@ var testID = ID(1234567890123456789)
 
 func TestIDSQL(t *testing.T) {
 	t.Run("Value", testIDSQLValue)
+	t.Run("ValueString", testIDSQLValueString)
 	t.Run("Scan", func(t *testing.T) {
 		t.Run("Int64", testIDSQLScanInt64)
+		t.Run("Uint64", testIDSQLScanUint64)
+		t.Run("Float64", testIDSQLScanFloat64)
 		t.Run("String", testIDSQLScanString)
 		t.Run("Bytes", testIDSQLScanBytes)
 		t.Run("ID", testIDSQLScanID)
@@ -34,6 +37,39 @@ func testIDSQLValue(t *testing.T) {
 	}
 }
 
+func testIDSQLValueString(t *testing.T) {
+	SQLFormat = FormatBase58
+	defer func() { SQLFormat = "" }()
+
+	v, err := testID.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := v.(string)
+	if !ok {
+		t.Fatalf("Value() returned %T, want string", v)
+	}
+	if want := testID.Format(FormatBase58); got != want {
+		t.Errorf("Value() == %q, want %q", got, want)
+	}
+
+	var scanned ID
+	if err := scanned.Scan(got); err != nil {
+		t.Fatal(err)
+	}
+	if scanned != testID {
+		t.Errorf("Scan(%q): got %v, want %v", got, scanned, testID)
+	}
+	if err := scanned.Scan(testID.Int64()); err != nil || scanned != testID {
+		t.Errorf("Scan(int64) = %v, %v; want %v", scanned, err, testID)
+	}
+
+	n := NullID{ID: testID, Valid: true}
+	if v, _ := n.Value(); v != got {
+		t.Errorf("NullID.Value() == %v, want %q", v, got)
+	}
+}
+
 func testIDSQLScanInt64(t *testing.T) {
 	var got ID
 	err := got.Scan(testID.Int64())
@@ -45,6 +81,34 @@ func testIDSQLScanInt64(t *testing.T) {
 	}
 }
 
+func testIDSQLScanUint64(t *testing.T) {
+	var got ID
+	if err := got.Scan(uint64(testID)); err != nil {
+		t.Fatal(err)
+	}
+	if got != testID {
+		t.Errorf("Scan(uint64): got %v, want %v", got, testID)
+	}
+	if err := got.Scan(uint64(1 << 63)); err == nil {
+		t.Error("Scan(1<<63): want err != nil")
+	}
+}
+
+func testIDSQLScanFloat64(t *testing.T) {
+	var got ID
+	if err := got.Scan(float64(1 << 40)); err != nil {
+		t.Fatal(err)
+	}
+	if got != 1<<40 {
+		t.Errorf("Scan(float64): got %d, want %d", got, int64(1<<40))
+	}
+	for _, v := range []float64{42.5, 1e19, -1e19} {
+		if err := got.Scan(v); err == nil {
+			t.Errorf("Scan(%v): want err != nil", v)
+		}
+	}
+}
+
 func testIDSQLScanString(t *testing.T) {
 	s := testID.String()
 	var got ID
@@ -84,6 +148,7 @@ func testIDSQLScanUnsupported(t *testing.T) {
 	unsupported := []interface{}{
 		true,
 		42.5,
+		int32(7),
 	}
 	for _, v := range unsupported {
 		var got ID
@@ -296,6 +361,68 @@ func testNullIDUnmarshalJSONNumeric(t *testing.T) {
 	}
 }
 
+func TestPointerHelpers(t *testing.T) {
+	id := ID(42)
+	p := Ptr(id)
+	if p == nil || *p != id || FromPtr(p) != id || FromPtr(nil) != Nil {
+		t.Errorf("Ptr/FromPtr roundtrip failed")
+	}
+	if n := NullIDFrom(id); !n.Valid || n.ID != id {
+		t.Errorf("NullIDFrom(%d) = %+v", id, n)
+	}
+	if n := NullIDFromPtr(p); !n.Valid || n.ID != id {
+		t.Errorf("NullIDFromPtr(&%d) = %+v", id, n)
+	}
+	if n := NullIDFromPtr(nil); n.Valid {
+		t.Errorf("NullIDFromPtr(nil) = %+v, want NULL", n)
+	}
+	if got := NullIDFrom(id).Ptr(); got == nil || *got != id {
+		t.Errorf("NullID.Ptr() = %v", got)
+	}
+	if got := (NullID{}).Ptr(); got != nil {
+		t.Errorf("NULL NullID.Ptr() = %v, want nil", got)
+	}
+}
+
+func TestIDArray(t *testing.T) {
+	t.Run("Value", func(t *testing.T) {
+		v, err := IDArray{1, testID, Omni}.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "{1,1234567890123456789,9223372036854775807}"
+		if v != want {
+			t.Errorf("Value() = %v, want %s", v, want)
+		}
+		if v, _ := IDArray(nil).Value(); v != nil {
+			t.Errorf("nil IDArray.Value() = %v, want nil", v)
+		}
+		if v, _ := (IDArray{}).Value(); v != "{}" {
+			t.Errorf("empty IDArray.Value() = %v, want {}", v)
+		}
+	})
+	t.Run("Scan", func(t *testing.T) {
+		var a IDArray
+		if err := a.Scan([]byte("{1, 1234567890123456789}")); err != nil {
+			t.Fatal(err)
+		}
+		if len(a) != 2 || a[0] != 1 || a[1] != testID {
+			t.Errorf("Scan = %v, want [1 %d]", []ID(a), testID.Int64())
+		}
+		if err := a.Scan("{}"); err != nil || a == nil || len(a) != 0 {
+			t.Errorf("Scan({}) = %v, %v; want empty non-nil", a, err)
+		}
+		if err := a.Scan(nil); err != nil || a != nil {
+			t.Errorf("Scan(nil) = %v, %v; want nil", a, err)
+		}
+		for _, src := range []interface{}{"{1,NULL}", "1,2", "{a}", 42} {
+			if err := a.Scan(src); err == nil {
+				t.Errorf("Scan(%v): want err != nil", src)
+			}
+		}
+	})
+}
+
 func BenchmarkNullIDMarshalJSON(b *testing.B) {
 	b.Run("Valid", func(b *testing.B) {
 		n := NullID{ID: testID, Valid: true}
@@ -0,0 +1,44 @@
+package usid
+
+// GeneratorStats is a snapshot of a generator's counters since it was created.
+type GeneratorStats struct {
+	Node      int64  `json:"node"`
+	Generated uint64 `json:"generated"` // IDs issued
+
+	// Retries counts compare-and-swap races lost to concurrent callers.
+	// A high ratio to Generated suggests WithShards.
+	Retries uint64 `json:"retries"`
+
+	// ExhaustionWaits counts loop iterations spent waiting for the clock
+	// because a tick's sequence ran out.
+	ExhaustionWaits uint64 `json:"exhaustion_waits"`
+
+	// ClockRegressions counts times the clock was first read behind the
+	// last-issued tick: the wall clock stepped back, or the generator ran
+	// ahead of it (see WithHLC, WithMonotonic, and WithCoarseClock). Calls
+	// made before the clock catches up again are not counted again.
+	ClockRegressions uint64 `json:"clock_regressions"`
+}
+
+// Stats returns the generator's counters, including those of nodes served by
+// GenerateForNode. Safe for concurrent use; counters from different shards
+// are read one after another, not atomically together.
+func (g *Generator) Stats() GeneratorStats {
+	st := GeneratorStats{Node: g.node}
+	for i := range g.shards {
+		st.add(&g.shards[i])
+	}
+	g.nodes.Range(func(_, sh any) bool {
+		st.add(sh.(*shardState))
+		return true
+	})
+	return st
+}
+
+// add adds the counters of one shard, or of a node served by GenerateForNode.
+func (st *GeneratorStats) add(sh *shardState) {
+	st.Generated += sh.generated.Load()
+	st.Retries += sh.retries.Load()
+	st.ExhaustionWaits += sh.waits.Load()
+	st.ClockRegressions += sh.regressions.Load()
+}
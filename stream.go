@@ -0,0 +1,172 @@
+package usid
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Stream frames start with streamMagic, the format version, and a flags byte.
+const (
+	streamMagic   = "USID"
+	streamVersion = 1
+
+	streamFlate = 1 << 0
+)
+
+// MaxFrame is the largest decompressed frame payload ReadIDs accepts, so a
+// small compressed frame cannot expand into an unbounded allocation. It holds
+// millions of IDs; split larger sets across frames.
+const MaxFrame = 64 << 20
+
+// ErrStream is returned by ReadIDs for data that is not a valid ID frame.
+var ErrStream = errors.New("usid: invalid ID stream")
+
+// StreamOption configures WriteIDs.
+type StreamOption func(*streamOptions)
+
+type streamOptions struct {
+	compress bool
+	level    int
+}
+
+// WithCompression makes WriteIDs deflate the frame payload at the given
+// compress/flate level. ReadIDs detects compressed frames on its own.
+func WithCompression(level int) StreamOption {
+	return func(o *streamOptions) {
+		o.compress = true
+		o.level = level
+	}
+}
+
+// WriteIDs writes ids to w as one self-delimiting frame, for checkpoint files
+// and snapshots exchanged between services. Several frames may be written to
+// the same stream and read back one at a time with ReadIDs.
+//
+// A frame is the magic "USID", a version byte, a flags byte, the uvarint
+// payload length, and the payload: the uvarint count of IDs followed by the
+// zigzag varint difference of each ID from the one before it. Unlike
+// EncodeBlock, the order of ids is preserved. Compressed payloads may not
+// exceed MaxFrame before compression.
+func WriteIDs(w io.Writer, ids []ID, opts ...StreamOption) error {
+	var o streamOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	payload := binary.AppendUvarint(nil, uint64(len(ids)))
+	var prev ID
+	for _, id := range ids {
+		// Wrapping subtraction; ReadIDs undoes it with wrapping addition.
+		payload = binary.AppendVarint(payload, int64(id-prev))
+		prev = id
+	}
+
+	var flags byte
+	if o.compress {
+		if len(payload) > MaxFrame {
+			return fmt.Errorf("usid: %d IDs exceed MaxFrame when decompressed; split them across frames", len(ids))
+		}
+		var buf bytes.Buffer
+		zw, err := flate.NewWriter(&buf, o.level)
+		if err != nil {
+			return fmt.Errorf("usid: %w", err)
+		}
+		if _, err := zw.Write(payload); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		payload = buf.Bytes()
+		flags |= streamFlate
+	}
+
+	frame := append([]byte(streamMagic), streamVersion, flags)
+	frame = binary.AppendUvarint(frame, uint64(len(payload)))
+	if _, err := w.Write(frame); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+// ReadIDs reads one frame written by WriteIDs from r. It reads no further
+// than the end of the frame. It returns io.EOF if r is empty and
+// io.ErrUnexpectedEOF if the frame is truncated.
+func ReadIDs(r io.Reader) ([]ID, error) {
+	var header [len(streamMagic) + 2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	if string(header[:len(streamMagic)]) != streamMagic {
+		return nil, ErrStream
+	}
+	if v := header[len(streamMagic)]; v != streamVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrStream, v)
+	}
+	flags := header[len(streamMagic)+1]
+	if flags&^streamFlate != 0 {
+		return nil, fmt.Errorf("%w: unknown flags %#x", ErrStream, flags)
+	}
+
+	size, err := binary.ReadUvarint(byteReader{r})
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	// ReadAll grows the buffer as data arrives, so a corrupt size cannot
+	// force a huge allocation up front.
+	payload, err := io.ReadAll(io.LimitReader(r, int64(min(size, 1<<62))))
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(payload)) != size {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if flags&streamFlate != 0 {
+		zr := flate.NewReader(bytes.NewReader(payload))
+		payload, err = io.ReadAll(io.LimitReader(zr, MaxFrame+1))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrStream, err)
+		}
+		if len(payload) > MaxFrame {
+			return nil, fmt.Errorf("%w: decompressed frame exceeds %d bytes", ErrStream, MaxFrame)
+		}
+	}
+
+	count, n := binary.Uvarint(payload)
+	// Every ID takes at least one byte, which bounds the allocation.
+	if n <= 0 || count > uint64(len(payload)-n) {
+		return nil, ErrStream
+	}
+	ids := make([]ID, count)
+	var prev ID
+	for i := range ids {
+		delta, m := binary.Varint(payload[n:])
+		if m <= 0 {
+			return nil, ErrStream
+		}
+		n += m
+		prev += ID(delta)
+		ids[i] = prev
+	}
+	if n != len(payload) {
+		return nil, ErrStream
+	}
+	return ids, nil
+}
+
+// byteReader reads single bytes from an io.Reader without buffering ahead.
+type byteReader struct{ io.Reader }
+
+func (r byteReader) ReadByte() (byte, error) {
+	var b [1]byte
+	_, err := io.ReadFull(r.Reader, b[:])
+	return b[0], err
+}
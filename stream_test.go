@@ -0,0 +1,79 @@
+package usid
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestStream(t *testing.T) {
+	g := NewGenerator(2)
+	ids := make([]ID, 500)
+	for i := range ids {
+		ids[i] = g.Generate()
+	}
+	ids = append(ids, Nil, Omni, ID(-1), ids[3])
+
+	var buf bytes.Buffer
+	if err := WriteIDs(&buf, ids); err != nil {
+		t.Fatal(err)
+	}
+	plain := buf.Len()
+	if err := WriteIDs(&buf, ids, WithCompression(flate.BestCompression)); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteIDs(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if plain > 8*len(ids) {
+		t.Errorf("WriteIDs: %d bytes for %d IDs, want <= 8 per ID", plain, len(ids))
+	}
+	data := slices.Clone(buf.Bytes())
+
+	for i, want := range [][]ID{ids, ids, {}} {
+		got, err := ReadIDs(&buf)
+		if err != nil {
+			t.Fatalf("frame %d: %v", i, err)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("frame %d: ReadIDs returned %d IDs, want %v in order", i, len(got), len(want))
+		}
+	}
+	if _, err := ReadIDs(&buf); err != io.EOF {
+		t.Errorf("ReadIDs at end = %v, want io.EOF", err)
+	}
+
+	if _, err := ReadIDs(bytes.NewReader(data[:plain-1])); err != io.ErrUnexpectedEOF {
+		t.Errorf("truncated frame: err = %v, want io.ErrUnexpectedEOF", err)
+	}
+	for _, mutate := range []func([]byte){
+		func(b []byte) { b[0] = 'X' },  // magic
+		func(b []byte) { b[4] = 2 },    // version
+		func(b []byte) { b[5] = 0x80 }, // flags
+		func(b []byte) { b[5] = 1 },    // claims flate
+	} {
+		b := slices.Clone(data[:plain])
+		mutate(b)
+		if _, err := ReadIDs(bytes.NewReader(b)); !errors.Is(err, ErrStream) {
+			t.Errorf("corrupt frame: err = %v, want ErrStream", err)
+		}
+	}
+}
+
+func TestStreamDecompressionLimit(t *testing.T) {
+	var payload bytes.Buffer
+	zw, _ := flate.NewWriter(&payload, flate.BestSpeed)
+	zw.Write(make([]byte, MaxFrame+1))
+	zw.Close()
+
+	frame := append([]byte(streamMagic), streamVersion, streamFlate)
+	frame = binary.AppendUvarint(frame, uint64(payload.Len()))
+	frame = append(frame, payload.Bytes()...)
+	if _, err := ReadIDs(bytes.NewReader(frame)); !errors.Is(err, ErrStream) {
+		t.Errorf("frame expanding past MaxFrame: err = %v, want ErrStream", err)
+	}
+}
@@ -0,0 +1,81 @@
+package usid
+
+import (
+	"errors"
+	"iter"
+	"strconv"
+	"time"
+)
+
+// TimeRange is a SQL predicate matching IDs generated in the half-open
+// window [From, To). Because IDs are time-ordered, this turns a time filter
+// into an index range scan on the ID column.
+//
+// TimeRange implements squirrel's Sqlizer interface, so it can be passed
+// directly to squirrel's Where.
+type TimeRange struct {
+	// Column is written into the SQL as is, since identifier quoting differs
+	// between databases (Postgres "id", MySQL `id`). It must be a trusted
+	// identifier, such as a constant in the query's code, quoted already if
+	// it needs quoting; never build it from user input.
+	Column string
+	From   time.Time
+	To     time.Time
+}
+
+// Between returns a TimeRange predicate on column for [from, to). column is
+// not quoted; see TimeRange.Column.
+func Between(column string, from, to time.Time) TimeRange {
+	return TimeRange{Column: column, From: from, To: to}
+}
+
+// Bounds returns the inclusive lower and exclusive upper ID bounds.
+func (r TimeRange) Bounds() (ID, ID) {
+	return MinIDForTime(r.From), MinIDForTime(r.To)
+}
+
+// ToSql returns the predicate with `?` placeholders and its arguments,
+// e.g. "id >= ? AND id < ?", [min, max].
+func (r TimeRange) ToSql() (string, []interface{}, error) {
+	return r.build("?", "?")
+}
+
+// ToSqlDollar returns the predicate with Postgres `$n` placeholders numbered
+// from start, e.g. "id >= $1 AND id < $2", [min, max].
+func (r TimeRange) ToSqlDollar(start int) (string, []interface{}, error) {
+	return r.build("$"+strconv.Itoa(start), "$"+strconv.Itoa(start+1))
+}
+
+func (r TimeRange) build(p1, p2 string) (string, []interface{}, error) {
+	if r.Column == "" {
+		return "", nil, errors.New("usid: TimeRange requires a column")
+	}
+	if r.To.Before(r.From) {
+		return "", nil, errors.New("usid: TimeRange ends before it starts")
+	}
+	min, max := r.Bounds()
+	sql := r.Column + " >= " + p1 + " AND " + r.Column + " < " + p2
+	return sql, []interface{}{min.Int64(), max.Int64()}, nil
+}
+
+// IDsBetween yields the boundaries of the buckets of size step, aligned like
+// time.Time.Truncate, that overlap [from, to): each boundary time and its
+// MinIDForTime, ending with the upper boundary of the last bucket. Consecutive
+// IDs bound one bucket as [lo, hi), to drive partition creation, archival,
+// or sharded scans. It yields nothing if to is not after from and panics if
+// step is not positive.
+func IDsBetween(from, to time.Time, step time.Duration) iter.Seq2[time.Time, ID] {
+	if step <= 0 {
+		panic("usid: IDsBetween step must be positive")
+	}
+	return func(yield func(time.Time, ID) bool) {
+		if !to.After(from) {
+			return
+		}
+		for t := from.Truncate(step); ; t = t.Add(step) {
+			if !yield(t, MinIDForTime(t)) || !t.Before(to) {
+				return
+			}
+		}
+	}
+}
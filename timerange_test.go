@@ -0,0 +1,109 @@
+package usid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMinMaxIDForTime(t *testing.T) {
+	id := New()
+	ts := id.Timestamp()
+	if min := MinIDForTime(ts); min > id || min.Timestamp() != ts {
+		t.Errorf("MinIDForTime(%v) = %v, want <= %v at the same timestamp", ts, min, id)
+	}
+	if max := MaxIDForTime(ts); max < id || max.Timestamp() != ts {
+		t.Errorf("MaxIDForTime(%v) = %v, want >= %v at the same timestamp", ts, max, id)
+	}
+	if got := MaxIDForTime(ts) + 1; got != MinIDForTime(ts.Add(time.Microsecond)) {
+		t.Errorf("MaxIDForTime(t)+1 = %v, want MinIDForTime(t+1µs)", got)
+	}
+	before := time.UnixMicro(Epoch - 1)
+	if MinIDForTime(before) != Nil || MaxIDForTime(before) != Nil {
+		t.Error("IDs for times before Epoch should be Nil")
+	}
+	if got := MinIDForTime(time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)); got != Omni {
+		t.Errorf("MinIDForTime(far future) = %v, want Omni", got)
+	}
+}
+
+func TestTimeRange(t *testing.T) {
+	from := time.Now().Add(-time.Hour)
+	to := time.Now()
+	r := Between("id", from, to)
+
+	sql, args, err := r.ToSql()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "id >= ? AND id < ?"; sql != want {
+		t.Errorf("ToSql() = %q, want %q", sql, want)
+	}
+	if args[0] != MinIDForTime(from).Int64() || args[1] != MinIDForTime(to).Int64() {
+		t.Errorf("ToSql() args = %v, want bounds for [%v, %v)", args, from, to)
+	}
+
+	sql, _, err = r.ToSqlDollar(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "id >= $3 AND id < $4"; sql != want {
+		t.Errorf("ToSqlDollar(3) = %q, want %q", sql, want)
+	}
+
+	if _, _, err := Between("", from, to).ToSql(); err == nil {
+		t.Error("ToSql() without column: want err != nil")
+	}
+	if _, _, err := Between("id", to, from).ToSql(); err == nil {
+		t.Error("ToSql() with reversed range: want err != nil")
+	}
+}
+
+func TestAgeTruncate(t *testing.T) {
+	id := MinIDForTime(time.Now().Add(-time.Hour))
+	if age := id.Age(); age < time.Hour || age > time.Hour+time.Minute {
+		t.Errorf("Age() = %v, want ~1h", age)
+	}
+	if !id.OlderThan(30*time.Minute) || id.OlderThan(2*time.Hour) {
+		t.Error("OlderThan returned wrong result for a 1h-old ID")
+	}
+
+	id = New()
+	bucket := id.Truncate(time.Hour)
+	if want := id.Timestamp().Truncate(time.Hour); !bucket.Timestamp().Equal(want) {
+		t.Errorf("Truncate(1h).Timestamp() = %v, want %v", bucket.Timestamp(), want)
+	}
+	if bucket.Node() != 0 || bucket.Seq() != 0 || bucket > id {
+		t.Errorf("Truncate(1h) = %v, want bucket start <= %v", bucket.Fmt(), id)
+	}
+	if got := id.Truncate(0); got.Timestamp() != id.Timestamp() || got.Node() != 0 {
+		t.Errorf("Truncate(0) = %+v, want same timestamp with node zeroed", got.Fmt())
+	}
+}
+
+func TestIDsBetween(t *testing.T) {
+	from := time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC)
+	var times []time.Time
+	for ts, id := range IDsBetween(from, to, time.Hour) {
+		if id != MinIDForTime(ts) {
+			t.Errorf("IDsBetween yielded %v for %v, want MinIDForTime", id, ts)
+		}
+		times = append(times, ts)
+	}
+	want := []time.Time{from.Truncate(time.Hour), from.Truncate(time.Hour).Add(time.Hour), to.Add(-time.Hour), to}
+	if len(times) != len(want) {
+		t.Fatalf("IDsBetween yielded %v, want %v", times, want)
+	}
+	for i := range want {
+		if !times[i].Equal(want[i]) {
+			t.Errorf("boundary %d = %v, want %v", i, times[i], want[i])
+		}
+	}
+
+	for range IDsBetween(to, from, time.Hour) {
+		t.Error("IDsBetween with to before from yielded a boundary")
+	}
+	for range IDsBetween(from, to, 24*time.Hour) {
+		break // stopping early must not panic
+	}
+}
@@ -10,24 +10,24 @@
 //	id := usid.New()   // Generate IDs
 //	fmt.Println(id)    // Crockford Base32 encoded by default
 //
-// The bit layout is configurable via Epoch, NodeBits, and SeqBits variables.
+// The bit layout is configurable via the Epoch, Precision, NodeBits, and SeqBits
+// variables, or as a whole with Configure.
 package usid
 
 import (
 	"database/sql"
 	"database/sql/driver"
 	"encoding"
-	"encoding/base64"
 	"encoding/gob"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/paraglidehq/usid/v2/base58"
 	"github.com/paraglidehq/usid/v2/crockford"
 )
 
@@ -51,11 +51,15 @@ type Format string
 
 // Supported ID string formats.
 const (
-	FormatCrockford Format = "crockford" // Crockford Base32, case-insensitive (default)
-	FormatBase58  Format = "base58"  // URL-safe, compact
-	FormatBase64  Format = "base64"  // Standard base64 encoding
-	FormatHash    Format = "hash"    // Hexadecimal encoding
-	FormatDecimal Format = "decimal" // Decimal integer string
+	FormatCrockford      Format = "crockford"       // Crockford Base32, case-insensitive (default)
+	FormatCrockfordFixed Format = "crockford-fixed" // Crockford Base32 zero-padded to 13 chars, sorts lexicographically
+	FormatBase58         Format = "base58"          // URL-safe, compact
+	FormatBase58Fixed    Format = "base58-fixed"    // Base58 padded to 11 chars, sorts lexicographically
+	FormatBase62         Format = "base62"          // Strictly alphanumeric (0-9A-Za-z)
+	FormatBase64         Format = "base64"          // Standard base64 encoding
+	FormatBase64URL      Format = "base64url"       // Unpadded URL-safe base64 encoding
+	FormatHash           Format = "hash"            // Hexadecimal encoding
+	FormatDecimal        Format = "decimal"         // Decimal integer string
 )
 
 // ID is a 64-bit microsecond-precision time-ordered identifier.
@@ -67,6 +71,17 @@ var Nil ID = 0
 // Omni is the maximum ID value (math.MaxInt64), useful as an upper bound in queries.
 var Omni ID = math.MaxInt64
 
+// MaxSafeInteger is the largest integer JavaScript numbers represent exactly (2^53 - 1).
+const MaxSafeInteger = 1<<53 - 1
+
+// JSSafe reports whether the ID's external numeric value (after obfuscation)
+// survives a round trip through a JavaScript number, i.e. lies within
+// [-MaxSafeInteger, MaxSafeInteger].
+func (id ID) JSSafe() bool {
+	n := int64(obfuscate(id))
+	return n >= -MaxSafeInteger && n <= MaxSafeInteger
+}
+
 // Int64 returns the ID as an int64.
 func (id ID) Int64() int64 {
 	return int64(id)
@@ -77,6 +92,19 @@ func (id ID) IsNil() bool {
 	return id == Nil
 }
 
+// IsSpecial reports whether the ID is Nil or Omni, the sentinels APIs use as
+// open bounds meaning "from the beginning" and "until forever" in range
+// filters. Neither is ever generated.
+func (id ID) IsSpecial() bool {
+	return id == Nil || id == Omni
+}
+
+// IsZero reports whether the ID is Nil. It lets the `omitzero` JSON option
+// and ORMs that look for an IsZero method treat Nil IDs as empty.
+func (id ID) IsZero() bool {
+	return id == Nil
+}
+
 // Bytes returns the ID as an 8-byte big-endian slice.
 func (id ID) Bytes() []byte {
 	b := make([]byte, 8)
@@ -117,32 +145,97 @@ func (id ID) Format(f ...Format) string {
 	if len(f) > 0 {
 		format = f[0]
 	}
-	id = obfuscate(id)
-	switch format {
-	case FormatBase58:
-		return base58.Encode(int64(id))
-	case FormatDecimal:
-		return strconv.FormatInt(int64(id), 10)
-	case FormatBase64:
-		return base64.StdEncoding.EncodeToString(id.Bytes())
-	case FormatHash:
-		return strconv.FormatUint(uint64(id), 16)
-	default:
-		return crockford.Encode(int64(id))
+	return codecFor(format).enc(obfuscate(id))
+}
+
+// AppendFormat appends the ID encoded in format f to b and returns the
+// extended slice, as ID.Format would return it. An empty f means
+// DefaultFormat. Every built-in format and every format registered with
+// RegisterCodec appends without allocating, except Base62.
+func (id ID) AppendFormat(b []byte, f Format) []byte {
+	if f == "" {
+		f = DefaultFormat
 	}
+	return codecFor(f).app(b, obfuscate(id))
 }
 
-// Timestamp extracts the creation time from the ID.
+// Timestamp extracts the creation time from the ID, relative to the epoch of
+// its era.
 func (id ID) Timestamp() time.Time {
-	timeShift := SeqBits + NodeBits
-	µs := (int64(id) >> timeShift) + Epoch
-	return time.UnixMicro(µs)
+	ticks := int64(id) >> timeShift()
+	if EraBits > 0 {
+		ticks &= 1<<(63-EraBits-timeShift()) - 1
+	}
+	return time.UnixMicro(ticks*precisionMicros(Precision) + epochOf(id))
+}
+
+// Age returns how long ago the ID was generated.
+func (id ID) Age() time.Duration {
+	return time.Since(id.Timestamp())
+}
+
+// OlderThan reports whether the ID was generated more than d ago.
+func (id ID) OlderThan(d time.Duration) bool {
+	return id.Age() > d
+}
+
+// Truncate returns the smallest ID in the time bucket of size d containing id,
+// with node and sequence bits zeroed. Buckets are aligned like time.Time.Truncate,
+// so IDs can be grouped by hour or day without extracting timestamps.
+// If d <= 0, only the node and sequence bits are zeroed. The result keeps the
+// era of id; buckets starting before its era's epoch truncate to the epoch.
+func (id ID) Truncate(d time.Duration) ID {
+	ticks := (id.Timestamp().Truncate(d).UnixMicro() - epochOf(id)) / precisionMicros(Precision)
+	var era ID
+	if EraBits > 0 {
+		era = ID(id.Era() << (63 - EraBits))
+	}
+	return era | ID(max(ticks, 0)<<timeShift())
+}
+
+// MinIDForTime returns the smallest ID that could be generated at t in the
+// current Era, for use as an inclusive lower bound in range queries. Times
+// before Epoch return Nil.
+func MinIDForTime(t time.Time) ID {
+	if t.UnixMicro() < Epoch {
+		return Nil
+	}
+	ticks := (t.UnixMicro() - Epoch) / precisionMicros(Precision)
+	shift := timeShift()
+	if ticks > math.MaxInt64>>(shift+EraBits) {
+		return Omni
+	}
+	return eraPrefix() | ID(ticks<<shift)
+}
+
+// MaxIDForTime returns the largest ID that could be generated at t, for use as
+// an inclusive upper bound in range queries. Times before Epoch return Nil.
+func MaxIDForTime(t time.Time) ID {
+	if t.UnixMicro() < Epoch {
+		return Nil
+	}
+	min := MinIDForTime(t)
+	if min == Omni {
+		return Omni
+	}
+	return min | ID(int64(1)<<timeShift()-1)
 }
 
 // Node extracts the node ID component from the ID.
 func (id ID) Node() int64 {
 	nodeMax := int64((1 << NodeBits) - 1)
-	return (int64(id) >> SeqBits) & nodeMax
+	return (int64(id) >> (SeqBits + TenantBits)) & nodeMax
+}
+
+// Tenant extracts the tenant component from the ID: 0 unless TenantBits is set.
+func (id ID) Tenant() int64 {
+	tenantMax := int64((1 << TenantBits) - 1)
+	return (int64(id) >> SeqBits) & tenantMax
+}
+
+// timeShift returns the position of the timestamp field in the current layout.
+func timeShift() uint8 {
+	return SeqBits + TenantBits + NodeBits
 }
 
 // Seq extracts the sequence number component from the ID.
@@ -166,8 +259,12 @@ func (id *ID) UnmarshalText(b []byte) error {
 	return nil
 }
 
-// MarshalJSON implements json.Marshaler
+// MarshalJSON implements json.Marshaler.
+// IDs are emitted as strings in DefaultFormat, or as numbers if JSONNumeric is set.
 func (id ID) MarshalJSON() ([]byte, error) {
+	if JSONNumeric {
+		return strconv.AppendInt(nil, int64(obfuscate(id)), 10), nil
+	}
 	return []byte(`"` + id.String() + `"`), nil
 }
 
@@ -194,12 +291,18 @@ func (id *ID) UnmarshalJSON(b []byte) error {
 	return id.UnmarshalText(b[1 : len(b)-1])
 }
 
-// Value implements driver.Valuer for database storage
+// Value implements driver.Valuer for database storage.
+// Returns int64 by default, or the string encoding when SQLFormat is set.
 func (id ID) Value() (driver.Value, error) {
+	if SQLFormat != "" {
+		return id.Format(SQLFormat), nil
+	}
 	return int64(id), nil
 }
 
-// Scan implements sql.Scanner for database retrieval
+// Scan implements sql.Scanner for database retrieval.
+// Accepts int64, uint64 within range, integral float64, and strings encoded
+// in SQLFormat (or DefaultFormat if SQLFormat is unset).
 func (id *ID) Scan(src interface{}) error {
 	if src == nil {
 		*id = Nil
@@ -212,10 +315,24 @@ func (id *ID) Scan(src interface{}) error {
 	case int64:
 		*id = ID(v)
 		return nil
+	case uint64:
+		parsed, err := FromUint64(v)
+		if err != nil {
+			return err
+		}
+		*id = parsed
+		return nil
+	case float64:
+		// Some drivers return NUMERIC columns as float64; accept integral values only.
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return fmt.Errorf("usid: cannot scan non-integral or out-of-range float64 %v", v)
+		}
+		*id = ID(int64(v))
+		return nil
 	case []byte:
-		return id.UnmarshalText(v)
+		return id.scanString(string(v))
 	case string:
-		return id.UnmarshalText([]byte(v))
+		return id.scanString(v)
 	default:
 		return fmt.Errorf("usid: cannot scan %T", src)
 	}
@@ -223,89 +340,125 @@ func (id *ID) Scan(src interface{}) error {
 
 // Parse parses a string into an ID using DefaultFormat.
 func Parse(s string) (ID, error) {
-	switch DefaultFormat {
-	case FormatBase58:
-		return ParseBase58(s)
-	case FormatDecimal:
-		return ParseDecimal(s)
-	case FormatBase64:
-		return ParseBase64(s)
-	case FormatHash:
-		return ParseHash(s)
-	default:
-		return ParseCrockford(s)
+	return ParseFormat(s, DefaultFormat)
+}
+
+// Canonicalize parses s and returns the ID in DefaultFormat, so strings that
+// differ only in case, hyphens, padding, or encoding map to one key for
+// deduplication and caching. s is parsed in each of from in turn, or in
+// DefaultFormat if from is empty; list formats in order of preference where
+// a string is valid in several, such as digits in decimal and Crockford.
+func Canonicalize(s string, from ...Format) (string, error) {
+	if len(from) == 0 {
+		from = []Format{DefaultFormat}
+	}
+	var err error
+	for _, f := range from {
+		var id ID
+		if id, err = parseFormat(s, f); err == nil {
+			return id.String(), nil
+		}
 	}
+	reportFailure(s, from[len(from)-1], err)
+	return "", err
 }
 
-// ParseCrockford parses a Crockford Base32-encoded string into an ID.
-func ParseCrockford(s string) (ID, error) {
-	if len(s) == 0 {
-		return Nil, errors.New("usid: empty string")
-	}
-	n, err := crockford.Decode(s)
-	if err != nil {
-		return Nil, err
-	}
-	return deobfuscate(ID(n)), nil
+// IsCanonical reports whether s is an ID exactly as ID.String formats it.
+func IsCanonical(s string) bool {
+	id, err := Parse(s)
+	return err == nil && id.String() == s
 }
 
-// ParseBase58 parses a base58-encoded string into an ID.
-func ParseBase58(s string) (ID, error) {
-	if len(s) == 0 {
-		return Nil, errors.New("usid: empty string")
-	}
-	n, err := base58.Decode(s)
+// ParseFormat parses a string in the given format into an ID.
+// Unknown formats are parsed as Crockford Base32, mirroring ID.Format.
+// Failures are reported to OnParseFailure.
+func ParseFormat(s string, f Format) (ID, error) {
+	id, err := parseFormat(s, f)
 	if err != nil {
-		return Nil, err
+		reportFailure(s, f, err)
 	}
-	return deobfuscate(ID(n)), nil
+	return id, err
 }
 
-// ParseBase64 parses a base64-encoded string into an ID.
-func ParseBase64(s string) (ID, error) {
+func parseFormat(s string, f Format) (ID, error) {
+	return parseWith(s, codecFor(f).dec)
+}
+
+// parseWith parses s with dec and deobfuscates the result.
+func parseWith(s string, dec func(string) (ID, error)) (ID, error) {
 	if len(s) == 0 {
 		return Nil, errors.New("usid: empty string")
 	}
-	b, err := base64.StdEncoding.DecodeString(s)
-	if err != nil {
-		return Nil, fmt.Errorf("usid: invalid base64: %w", err)
-	}
-	id, err := FromBytes(b)
+	id, err := dec(s)
 	if err != nil {
 		return Nil, err
 	}
 	return deobfuscate(id), nil
 }
 
-// ParseHash parses a hex-encoded string into an ID.
-func ParseHash(s string) (ID, error) {
-	if len(s) == 0 {
-		return Nil, errors.New("usid: empty string")
-	}
-	if !isHex(s) {
-		return Nil, errors.New("usid: invalid hex string")
-	}
-	b, err := hexDecode(s)
-	if err != nil {
-		return Nil, err
-	}
-	id, err := FromBytes(b)
+// ParseCrockford parses a Crockford Base32-encoded string into an ID.
+// Both the compact and fixed-width forms are accepted.
+func ParseCrockford(s string) (ID, error) {
+	return parseFormat(s, FormatCrockford)
+}
+
+// ParseCrockfordStrict is ParseCrockford for systems that require canonical
+// strings: it accepts only the exact output of FormatCrockford or
+// FormatCrockfordFixed and returns crockford.ErrNotCanonical for uppercase,
+// hyphenated, substituted, or zero-padded variants. See crockford.DecodeStrict.
+func ParseCrockfordStrict(s string) (ID, error) {
+	n, err := crockford.DecodeStrict(s)
 	if err != nil {
 		return Nil, err
 	}
-	return deobfuscate(id), nil
+	return deobfuscate(ID(n)), nil
+}
+
+// ParseBase58 parses a base58-encoded string into an ID.
+// Both the compact and fixed-width forms are accepted.
+func ParseBase58(s string) (ID, error) {
+	return parseFormat(s, FormatBase58)
+}
+
+// ParseBase62 parses a base62-encoded string into an ID.
+func ParseBase62(s string) (ID, error) {
+	return parseFormat(s, FormatBase62)
+}
+
+// ParseBase64 parses a base64-encoded string into an ID in constant time.
+func ParseBase64(s string) (ID, error) {
+	return parseFormat(s, FormatBase64)
+}
+
+// ParseBase64URL parses an unpadded URL-safe base64-encoded string into an ID
+// in constant time.
+func ParseBase64URL(s string) (ID, error) {
+	return parseFormat(s, FormatBase64URL)
+}
+
+// ParseHash parses a hex-encoded string into an ID in constant time.
+func ParseHash(s string) (ID, error) {
+	return parseFormat(s, FormatHash)
 }
 
 // ParseDecimal parses a decimal string into an ID.
 func ParseDecimal(s string) (ID, error) {
-	if len(s) == 0 {
-		return Nil, errors.New("usid: empty string")
+	return parseFormat(s, FormatDecimal)
+}
+
+// scanString parses a string column value using SQLFormat if set,
+// otherwise DefaultFormat.
+func (id *ID) scanString(s string) error {
+	f := SQLFormat
+	if f == "" {
+		f = DefaultFormat
 	}
-	n, err := strconv.ParseInt(s, 10, 64)
+	parsed, err := ParseFormat(s, f)
 	if err != nil {
-		return Nil, fmt.Errorf("usid: invalid decimal: %w", err)
+		return err
 	}
-	return deobfuscate(ID(n)), nil
+	*id = parsed
+	return nil
 }
 
 // Parse parses a string into the ID receiver.
@@ -318,35 +471,6 @@ func (id *ID) Parse(s string) error {
 	return nil
 }
 
-func isHex(s string) bool {
-	for _, c := range s {
-		if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')) {
-			return false
-		}
-	}
-	return true
-}
-
-func hexDecode(s string) ([]byte, error) {
-	if len(s) == 0 || len(s) > 16 {
-		return nil, errors.New("usid: hex string must be 1-16 characters")
-	}
-	n, err := strconv.ParseUint(s, 16, 64)
-	if err != nil {
-		return nil, errors.New("usid: invalid hex character")
-	}
-	b := make([]byte, 8)
-	b[0] = byte(n >> 56)
-	b[1] = byte(n >> 48)
-	b[2] = byte(n >> 40)
-	b[3] = byte(n >> 32)
-	b[4] = byte(n >> 24)
-	b[5] = byte(n >> 16)
-	b[6] = byte(n >> 8)
-	b[7] = byte(n)
-	return b, nil
-}
-
 // FromString returns an ID parsed from the input string.
 // Alias for Parse.
 func FromString(s string) (ID, error) {
@@ -382,18 +506,47 @@ func FromBytesOrNil(b []byte) ID {
 	return id
 }
 
+// Ptr returns a pointer to a copy of id, for optional fields in API structs.
+func Ptr(id ID) *ID {
+	return &id
+}
+
+// FromPtr returns *id, or Nil if id is nil.
+func FromPtr(id *ID) ID {
+	if id == nil {
+		return Nil
+	}
+	return *id
+}
+
 // FromInt64 returns an ID from an int64.
 func FromInt64(n int64) ID {
 	return ID(n)
 }
 
+// FromUint64 returns an ID from a uint64, as returned by drivers for
+// BIGINT UNSIGNED columns. Returns an error if n exceeds math.MaxInt64.
+func FromUint64(n uint64) (ID, error) {
+	if n > math.MaxInt64 {
+		return Nil, fmt.Errorf("usid: uint64 %d overflows int64", n)
+	}
+	return ID(n), nil
+}
+
 // MarshalBinary implements encoding.BinaryMarshaler.
+// IDs are encoded as 8 big-endian bytes, or as a uvarint if BinaryVarint is set.
 func (id ID) MarshalBinary() ([]byte, error) {
+	if BinaryVarint {
+		return id.MarshalVarint(), nil
+	}
 	return id.Bytes(), nil
 }
 
 // UnmarshalBinary implements encoding.BinaryUnmarshaler.
 func (id *ID) UnmarshalBinary(data []byte) error {
+	if BinaryVarint {
+		return id.UnmarshalVarint(data)
+	}
 	parsed, err := FromBytes(data)
 	if err != nil {
 		return err
@@ -424,8 +577,23 @@ func Must(id ID, err error) ID {
 // Create with NewGenerator and call Generate to produce IDs.
 type Generator struct {
 	node      int64
-	state     atomic.Uint64
-	seqMask   int64
+	shards    []shardState
+	nodes     sync.Map      // int64 -> *shardState, for GenerateForNode
+	floor     atomic.Uint64 // state every node passed to advancePast starts from
+	shardBits uint8
+	seqMask   int64 // counter mask of each shard
+	nodeMask  int64 // counter mask of nodes served by GenerateForNode, which are not sharded
 	nodeShift uint8
+	seqBits   uint8
+	tenant    int64 // tenant component, shifted into place
 	timeShift uint8
+	randBits  uint8
+	era       ID    // era bits of every ID
+	maxTick   int64 // first tick that does not fit the layout; 0 if unbounded
+	hlc       bool
+	monotonic bool
+	coarse    bool
+	limit     *rateLimit
+	now       func() time.Time
+	opts      []Option
 }
@@ -1,6 +1,10 @@
 package usid
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"runtime"
 	"sync"
 	"testing"
 	"time"
@@ -8,6 +12,7 @@ import (
 
 func TestID(t *testing.T) {
 	t.Run("IsNil", testIDIsNil)
+	t.Run("IsZero", testIDIsZero)
 	t.Run("Bytes", testIDBytes)
 	t.Run("String", testIDString)
 	t.Run("Format", testIDFormats)
@@ -28,6 +33,29 @@ func testIDIsNil(t *testing.T) {
 	if id.IsNil() {
 		t.Errorf("New().IsNil() = true, want false")
 	}
+	if !Nil.IsSpecial() || !Omni.IsSpecial() || id.IsSpecial() {
+		t.Errorf("IsSpecial() of Nil, Omni, New() = %v, %v, %v, want true, true, false", Nil.IsSpecial(), Omni.IsSpecial(), id.IsSpecial())
+	}
+}
+
+func testIDIsZero(t *testing.T) {
+	type payload struct {
+		ID       ID     `json:"id,omitzero"`
+		ParentID NullID `json:"parent_id,omitzero"`
+	}
+	data, err := json.Marshal(payload{ParentID: NullID{Valid: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "{}" {
+		t.Errorf("json.Marshal(zero payload) = %s, want {}", data)
+	}
+	if New().IsZero() {
+		t.Error("New().IsZero() = true, want false")
+	}
+	if (NullID{ID: testID, Valid: true}).IsZero() {
+		t.Error("valid NullID.IsZero() = true, want false")
+	}
 }
 
 func testIDBytes(t *testing.T) {
@@ -60,7 +88,7 @@ func testIDString(t *testing.T) {
 func testIDFormats(t *testing.T) {
 	id := New()
 
-	formats := []Format{FormatCrockford, FormatBase58, FormatDecimal, FormatHash, FormatBase64}
+	formats := []Format{FormatCrockford, FormatCrockfordFixed, FormatBase58, FormatBase58Fixed, FormatBase62, FormatDecimal, FormatHash, FormatBase64, FormatBase64URL}
 	for _, f := range formats {
 		s := id.Format(f)
 		if s == "" {
@@ -127,12 +155,14 @@ func TestGenerator(t *testing.T) {
 }
 
 func TestConcurrentGeneration(t *testing.T) {
+	t.Run("Single", func(t *testing.T) { testConcurrentGeneration(t, NewGenerator(0)) })
+	t.Run("Sharded", func(t *testing.T) { testConcurrentGeneration(t, NewGenerator(0, WithShards(6))) })
+}
+
+func testConcurrentGeneration(t *testing.T, gen *Generator) {
 	const numGoroutines = 100
 	const numIDs = 100
 
-	// Use a dedicated generator to avoid interference from other tests
-	gen := NewGenerator(0)
-
 	var wg sync.WaitGroup
 	results := make([][]ID, numGoroutines)
 
@@ -245,6 +275,28 @@ func BenchmarkNewParallel(b *testing.B) {
 	})
 }
 
+func BenchmarkNewParallelSharded(b *testing.B) {
+	gen := NewGenerator(1, WithShards(runtime.GOMAXPROCS(0)))
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = gen.Generate()
+		}
+	})
+}
+
+func TestWithShards(t *testing.T) {
+	gen := NewGenerator(1, WithShards(3)) // rounds up to 4
+	if len(gen.shards) != 4 || gen.seqMask != 1<<(SeqBits-2)-1 {
+		t.Errorf("shards=%d seqMask=%d, want 4 and %d", len(gen.shards), gen.seqMask, 1<<(SeqBits-2)-1)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("WithShards beyond SeqBits did not panic")
+		}
+	}()
+	NewGenerator(1, WithShards(1<<(SeqBits+1)))
+}
+
 func BenchmarkIDString(b *testing.B) {
 	id := New()
 	b.ResetTimer()
@@ -260,3 +312,270 @@ func BenchmarkIDTimestamp(b *testing.B) {
 		_ = id.Timestamp()
 	}
 }
+
+func TestGeneratorHLC(t *testing.T) {
+	t.Run("Observe", func(t *testing.T) {
+		gen := NewGenerator(1, WithHLC())
+		// An ID from node 2, whose clock runs a minute ahead
+		ahead := MinIDForTime(time.Now().Add(time.Minute)) | ID(2<<SeqBits) | 5
+
+		gen.Observe(ahead)
+		id := gen.Generate()
+		if id <= ahead {
+			t.Errorf("Generate() after Observe = %v, want > %v", id, ahead)
+		}
+		if next := gen.Generate(); next <= id {
+			t.Errorf("Generate() = %v, want > %v", next, id)
+		}
+
+		// Observing an older ID must not move the clock backwards
+		gen.Observe(MinIDForTime(time.Now().Add(-time.Hour)))
+		if next := gen.Generate(); next <= id {
+			t.Errorf("Generate() after observing old ID = %v, want > %v", next, id)
+		}
+	})
+	t.Run("NoSpin", func(t *testing.T) {
+		gen := NewGenerator(1, WithHLC())
+		gen.Observe(MaxIDForTime(time.Now().Add(time.Hour)))
+		start := time.Now()
+		var last ID
+		for i := 0; i < 1000; i++ {
+			id := gen.Generate()
+			if id <= last {
+				t.Fatalf("Generate() = %v, want > %v", id, last)
+			}
+			last = id
+		}
+		if time.Since(start) > time.Second {
+			t.Error("HLC generator waited for the wall clock instead of advancing logically")
+		}
+	})
+	t.Run("PlainIgnoresObserve", func(t *testing.T) {
+		gen := NewGenerator(1)
+		gen.Observe(MaxIDForTime(time.Now().Add(time.Hour)))
+		if id := gen.Generate(); id.Timestamp().After(time.Now().Add(time.Second)) {
+			t.Errorf("plain generator honored Observe: %v", id.Timestamp())
+		}
+	})
+}
+
+func TestGeneratorMonotonic(t *testing.T) {
+	t.Run("ClockRegression", func(t *testing.T) {
+		wall := time.Now()
+		gen := NewGenerator(1, WithMonotonic(), WithClock(func() time.Time { return wall }))
+		var last ID
+		for i := 0; i < 500; i++ {
+			if i == 10 {
+				wall = wall.Add(-time.Minute) // NTP step backwards
+			}
+			id := gen.Generate()
+			if id <= last {
+				t.Fatalf("Generate() = %v, want > %v", id, last)
+			}
+			last = id
+		}
+	})
+	t.Run("SetNodeID", func(t *testing.T) {
+		defer SetNodeID(1)
+		SetNodeID(5)
+		before := New()
+		SetNodeID(2, WithMonotonic())
+		if after := New(); after <= before {
+			t.Errorf("New() after SetNodeID = %v, want > %v", after, before)
+		}
+	})
+	t.Run("ConfigureKeepsOptions", func(t *testing.T) {
+		defer SetNodeID(1)
+		SetNodeID(3, WithMonotonic())
+		if err := Configure(DefaultConfig()); err != nil {
+			t.Fatal(err)
+		}
+		if !DefaultGenerator.monotonic || DefaultGenerator.node != 3 {
+			t.Errorf("Configure dropped generator settings: node=%d monotonic=%v",
+				DefaultGenerator.node, DefaultGenerator.monotonic)
+		}
+	})
+}
+
+func TestCoarseClock(t *testing.T) {
+	gen := NewGenerator(1, WithCoarseClock(time.Millisecond))
+	start := time.Now()
+	var last ID
+	for i := 0; i < 10000; i++ {
+		id := gen.Generate()
+		if id <= last {
+			t.Fatalf("Generate() = %v, want > %v", id, last)
+		}
+		last = id
+	}
+	if time.Since(start) > time.Second {
+		t.Error("coarse generator waited for the clock instead of advancing logically")
+	}
+	time.Sleep(5 * time.Millisecond)
+	if skew := time.Since(gen.Generate().Timestamp()); skew < -time.Second || skew > time.Second {
+		t.Errorf("coarse timestamp off by %v", skew)
+	}
+}
+
+func TestMaxRate(t *testing.T) {
+	gen := NewGenerator(1, WithMaxRate(1000, 10))
+	start := time.Now()
+	for i := 0; i < 60; i++ {
+		gen.Generate()
+	}
+	// 10 IDs burst immediately, the other 50 are paced 1ms apart.
+	if elapsed := time.Since(start); elapsed < 45*time.Millisecond {
+		t.Errorf("60 IDs at 1000/s with burst 10 took %v, want >= 50ms", elapsed)
+	}
+
+	gen = NewGenerator(1, WithMaxRate(1, 2))
+	for i := 0; i < 2; i++ {
+		if _, err := gen.TryGenerate(); err != nil {
+			t.Fatalf("TryGenerate() #%d: %v", i, err)
+		}
+	}
+	if _, err := gen.TryGenerate(); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("TryGenerate() past burst: err = %v, want ErrRateLimited", err)
+	}
+	if _, err := NewGenerator(1).TryGenerate(); err != nil {
+		t.Errorf("TryGenerate() without limit: %v", err)
+	}
+}
+
+func BenchmarkNewCoarseClock(b *testing.B) {
+	gen := NewGenerator(1, WithCoarseClock(time.Millisecond))
+	for i := 0; i < b.N; i++ {
+		_ = gen.Generate()
+	}
+}
+
+func TestGeneratorIter(t *testing.T) {
+	gen := NewGenerator(1)
+	var ids []ID
+	for id := range gen.Iter() {
+		ids = append(ids, id)
+		if len(ids) == 100 {
+			break
+		}
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("ids[%d] = %v, want > %v", i, ids[i], ids[i-1])
+		}
+	}
+}
+
+func TestGeneratorChan(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := NewGenerator(1).Chan(ctx, 4)
+	var last ID
+	for i := 0; i < 100; i++ {
+		id := <-ch
+		if id <= last {
+			t.Fatalf("received %v, want > %v", id, last)
+		}
+		last = id
+	}
+	cancel()
+	for range ch {
+		// drain until the producer notices cancellation and closes
+	}
+}
+
+func TestGeneratorStats(t *testing.T) {
+	now := time.Now()
+	gen := NewGenerator(3, WithMonotonic(), WithClock(func() time.Time { return now }))
+	for range 10 {
+		gen.Generate()
+	}
+	now = now.Add(-time.Second)
+	for range 100 { // past the sequence, borrowing ticks while behind
+		gen.Generate()
+	}
+
+	st := gen.Stats()
+	if st.Node != 3 || st.Generated != 110 || st.ClockRegressions != 1 || st.ExhaustionWaits != 0 {
+		t.Errorf("Stats() = %+v", st)
+	}
+
+	now = now.Add(2 * time.Second) // caught up
+	gen.Generate()
+	now = now.Add(-time.Second)
+	gen.Generate()
+	if st := gen.Stats(); st.ClockRegressions != 2 {
+		t.Errorf("after a second regression, ClockRegressions = %d, want 2", st.ClockRegressions)
+	}
+}
+
+func TestGenerateForNode(t *testing.T) {
+	gen := NewGenerator(1)
+	seen := make(map[ID]bool)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := range int64(8) {
+		node := i + 1
+		wg.Go(func() {
+			var last ID
+			for range 1000 {
+				id, err := gen.GenerateForNode(node)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if id.Node() != node || id <= last {
+					t.Errorf("GenerateForNode(%d) = %v (node %d), want node %d after %v", node, id, id.Node(), node, last)
+					return
+				}
+				last = id
+				mu.Lock()
+				if seen[id] {
+					t.Errorf("duplicate ID %v", id)
+				}
+				seen[id] = true
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+
+	// The generator's own node shares its sequence with Generate
+	own, _ := gen.GenerateForNode(1)
+	if next := gen.Generate(); next <= own {
+		t.Errorf("Generate() = %v after GenerateForNode(1) = %v", next, own)
+	}
+	if st := gen.Stats(); st.Generated != 8002 {
+		t.Errorf("Stats().Generated = %d, want 8002", st.Generated)
+	}
+	for _, node := range []int64{-1, 1 << NodeBits} {
+		if _, err := gen.GenerateForNode(node); !errors.Is(err, ErrNodeRange) {
+			t.Errorf("GenerateForNode(%d) error = %v, want ErrNodeRange", node, err)
+		}
+	}
+	if _, err := gen.GenerateForNode(0); !errors.Is(err, ErrNodeReserved) {
+		t.Errorf("GenerateForNode(0) error = %v, want ErrNodeReserved", err)
+	}
+
+	t.Run("Shards", func(t *testing.T) {
+		// Other nodes get the whole sequence of a tick, not one shard's
+		now := time.Now()
+		gen := NewGenerator(1, WithShards(4), WithHLC(), WithClock(func() time.Time { return now }))
+		first, _ := gen.GenerateForNode(2)
+		for i := 1; i < 1<<(SeqBits-RandBits); i++ {
+			if id, _ := gen.GenerateForNode(2); id.Timestamp() != first.Timestamp() {
+				t.Fatalf("GenerateForNode(2) moved to the next tick after %d IDs", i)
+			}
+		}
+	})
+
+	t.Run("Observe", func(t *testing.T) {
+		gen := NewGenerator(1, WithHLC())
+		ahead := MinIDForTime(time.Now().Add(time.Minute))
+		gen.GenerateForNode(2)
+		gen.Observe(ahead)
+		for _, node := range []int64{2, 3} {
+			if id, _ := gen.GenerateForNode(node); id <= ahead {
+				t.Errorf("GenerateForNode(%d) after Observe = %v, want > %v", node, id, ahead)
+			}
+		}
+	})
+}
@@ -0,0 +1,16 @@
+module github.com/paraglidehq/usid/v2/usidcql
+
+go 1.25.5
+
+require (
+	github.com/gocql/gocql v1.7.0
+	github.com/paraglidehq/usid/v2 v2.0.0
+)
+
+require (
+	github.com/golang/snappy v0.0.3 // indirect
+	github.com/hailocab/go-hostpool v0.0.0-20160125115350-e80d13ce29ed // indirect
+	gopkg.in/inf.v0 v0.9.1 // indirect
+)
+
+replace github.com/paraglidehq/usid/v2 => ../
@@ -0,0 +1,70 @@
+// Package usidcql lets IDs be stored in Cassandra and ScyllaDB bigint columns
+// through gocql. It lives in its own module so the core package does not
+// depend on gocql.
+//
+// Convert at the call site:
+//
+//	session.Query(`INSERT INTO users (id, parent_id) VALUES (?, ?)`,
+//		usidcql.ID(user.ID), usidcql.NullID(user.ParentID))
+//
+//	var id usid.ID
+//	var parent usid.NullID
+//	iter.Scan((*usidcql.ID)(&id), (*usidcql.NullID)(&parent))
+package usidcql
+
+import (
+	"github.com/gocql/gocql"
+	"github.com/paraglidehq/usid/v2"
+)
+
+// ID implements gocql.Marshaler and gocql.Unmarshaler for usid.ID,
+// storing it as a bigint.
+type ID usid.ID
+
+// MarshalCQL implements gocql.Marshaler.
+func (id ID) MarshalCQL(info gocql.TypeInfo) ([]byte, error) {
+	return gocql.Marshal(info, int64(id))
+}
+
+// UnmarshalCQL implements gocql.Unmarshaler.
+func (id *ID) UnmarshalCQL(info gocql.TypeInfo, data []byte) error {
+	var n int64
+	if err := gocql.Unmarshal(info, data, &n); err != nil {
+		return err
+	}
+	*id = ID(n)
+	return nil
+}
+
+// NullID implements gocql.Marshaler and gocql.Unmarshaler for usid.NullID,
+// mapping Valid == false to CQL null.
+type NullID usid.NullID
+
+// MarshalCQL implements gocql.Marshaler.
+func (n NullID) MarshalCQL(info gocql.TypeInfo) ([]byte, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return gocql.Marshal(info, int64(n.ID))
+}
+
+// UnmarshalCQL implements gocql.Unmarshaler.
+func (n *NullID) UnmarshalCQL(info gocql.TypeInfo, data []byte) error {
+	if data == nil {
+		*n = NullID{}
+		return nil
+	}
+	var v int64
+	if err := gocql.Unmarshal(info, data, &v); err != nil {
+		return err
+	}
+	*n = NullID{ID: usid.ID(v), Valid: true}
+	return nil
+}
+
+var (
+	_ gocql.Marshaler   = ID(0)
+	_ gocql.Unmarshaler = (*ID)(nil)
+	_ gocql.Marshaler   = NullID{}
+	_ gocql.Unmarshaler = (*NullID)(nil)
+)
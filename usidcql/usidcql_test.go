@@ -0,0 +1,46 @@
+package usidcql_test
+
+import (
+	"testing"
+
+	"github.com/gocql/gocql"
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/usidcql"
+)
+
+var bigint = gocql.NewNativeType(4, gocql.TypeBigInt, "")
+
+func TestID(t *testing.T) {
+	id := usid.New()
+	data, err := usidcql.ID(id).MarshalCQL(bigint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got usid.ID
+	if err := (*usidcql.ID)(&got).UnmarshalCQL(bigint, data); err != nil {
+		t.Fatal(err)
+	}
+	if got != id {
+		t.Errorf("roundtrip = %d, want %d", got, id)
+	}
+}
+
+func TestNullID(t *testing.T) {
+	data, err := usidcql.NullID{}.MarshalCQL(bigint)
+	if err != nil || data != nil {
+		t.Fatalf("MarshalCQL(null) = %v, %v; want nil, nil", data, err)
+	}
+	got := usid.NullID{ID: 1, Valid: true}
+	if err := (*usidcql.NullID)(&got).UnmarshalCQL(bigint, nil); err != nil || got.Valid {
+		t.Errorf("UnmarshalCQL(nil) = %+v, %v; want invalid", got, err)
+	}
+
+	id := usid.New()
+	data, err = usidcql.NullID{ID: id, Valid: true}.MarshalCQL(bigint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (*usidcql.NullID)(&got).UnmarshalCQL(bigint, data); err != nil || got != (usid.NullID{ID: id, Valid: true}) {
+		t.Errorf("roundtrip = %+v, %v; want %d", got, err, id)
+	}
+}
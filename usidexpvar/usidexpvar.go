@@ -0,0 +1,28 @@
+// Package usidexpvar publishes usid generator statistics through expvar, so
+// /debug/vars shows ID generation health without a metrics library.
+//
+// Importing the package publishes usid.DefaultGenerator's stats as "usid":
+//
+//	import _ "github.com/paraglidehq/usid/v2/usidexpvar"
+package usidexpvar
+
+import (
+	"expvar"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+func init() {
+	expvar.Publish("usid", expvar.Func(func() any {
+		if g := usid.DefaultGenerator; g != nil {
+			return g.Stats()
+		}
+		return nil
+	}))
+}
+
+// Publish exports the stats of another generator under name.
+// Panics if name is already published, like expvar.Publish.
+func Publish(name string, g *usid.Generator) {
+	expvar.Publish(name, expvar.Func(func() any { return g.Stats() }))
+}
@@ -0,0 +1,31 @@
+package usidexpvar_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/usidexpvar"
+)
+
+func TestPublish(t *testing.T) {
+	usid.New()
+	var st usid.GeneratorStats
+	if err := json.Unmarshal([]byte(expvar.Get("usid").String()), &st); err != nil {
+		t.Fatal(err)
+	}
+	if st.Node != 1 || st.Generated == 0 {
+		t.Errorf("usid var = %+v, want node 1 with IDs generated", st)
+	}
+
+	g := usid.NewGenerator(7)
+	usidexpvar.Publish("usid_worker", g)
+	g.Generate()
+	if err := json.Unmarshal([]byte(expvar.Get("usid_worker").String()), &st); err != nil {
+		t.Fatal(err)
+	}
+	if st.Node != 7 || st.Generated != 1 {
+		t.Errorf("usid_worker var = %+v, want node 7 with 1 ID", st)
+	}
+}
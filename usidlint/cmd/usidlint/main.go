@@ -0,0 +1,12 @@
+// Command usidlint reports common misuse of github.com/paraglidehq/usid.
+//
+//	go install github.com/paraglidehq/usid/v2/usidlint/cmd/usidlint@latest
+//	usidlint ./...
+package main
+
+import (
+	"github.com/paraglidehq/usid/v2/usidlint"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() { singlechecker.Main(usidlint.Analyzer) }
@@ -0,0 +1,7 @@
+package main
+
+import "github.com/paraglidehq/usid/v2"
+
+func main() {
+	_ = usid.New() // want `usid.New called without usid.SetNodeID`
+}
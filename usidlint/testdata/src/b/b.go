@@ -0,0 +1,28 @@
+package b
+
+import "github.com/paraglidehq/usid/v2"
+
+type UserID usid.ID
+
+type OrderID usid.ID
+
+type Order struct{ id usid.ID }
+
+func (o *Order) ID() usid.ID { return o.id }
+
+func setup() {
+	usid.NodeBits = 8 // ok: before any generator
+	g := usid.NewGenerator(1)
+	usid.SeqBits = 4 // want `usid.SeqBits changed after a generator was created`
+	_ = g.Generate()
+
+	var cfg usid.Config
+	cfg.NodeBits = 10 // ok: a Config field, not the package variable
+	_ = usid.Configure(cfg)
+}
+
+func compare(u UserID, o OrderID, u2 UserID, ord *Order) bool {
+	_ = usid.ID(u) == usid.ID(u2) // ok: same domain
+	_ = usid.ID(u) < usid.ID(o)   // want `comparing IDs of different types b.UserID and b.OrderID`
+	return usid.ID(u) == ord.ID() // want `comparing IDs of different types b.UserID and b.Order`
+}
@@ -0,0 +1,25 @@
+// Package usid is a stub of github.com/paraglidehq/usid for analyzer tests.
+package usid
+
+import "time"
+
+type ID int64
+
+type Generator struct{}
+
+type Config struct{ NodeBits uint8 }
+
+var (
+	Epoch     int64
+	NodeBits  uint8
+	SeqBits   uint8
+	Precision time.Duration
+	TimeBits  uint8
+	RandBits  uint8
+)
+
+func New() ID                            { return 0 }
+func SetNodeID(node int64)               {}
+func Configure(cfg Config) error         { return nil }
+func NewGenerator(node int64) *Generator { return nil }
+func (g *Generator) Generate() ID        { return 0 }
@@ -0,0 +1,200 @@
+// Package usidlint provides a go/analysis analyzer that reports common misuse
+// of github.com/paraglidehq/usid:
+//
+//   - main packages that call usid.New without ever calling usid.SetNodeID or
+//     usid.Configure, so every instance shares node 1;
+//   - assignments to layout variables (Epoch, NodeBits, SeqBits, TenantBits,
+//     Precision, TimeBits, RandBits, EraBits, Era) after a generator has been
+//     created in the same function, which existing generators silently ignore;
+//   - comparisons between IDs unwrapped from different named ID types, such as
+//     usid.ID(userID) == usid.ID(orderID).
+package usidlint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const usidPath = "github.com/paraglidehq/usid/v2"
+
+// Analyzer reports common misuse of the usid package.
+var Analyzer = &analysis.Analyzer{
+	Name: "usidlint",
+	Doc:  "report common misuse of github.com/paraglidehq/usid",
+	Run:  run,
+}
+
+// layoutVars are the package-level variables read by NewGenerator.
+var layoutVars = map[string]bool{
+	"Epoch":      true,
+	"NodeBits":   true,
+	"SeqBits":    true,
+	"TenantBits": true,
+	"Precision":  true,
+	"TimeBits":   true,
+	"RandBits":   true,
+	"EraBits":    true,
+	"Era":        true,
+}
+
+// generatorFuncs create a generator or use DefaultGenerator.
+var generatorFuncs = map[string]bool{
+	"New":          true,
+	"NewGenerator": true,
+	"SetNodeID":    true,
+	"NewContext":   true,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	var newCalls []*ast.CallExpr
+	nodeSet := false
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.CallExpr:
+				switch usidFunc(pass, n.Fun) {
+				case "New":
+					newCalls = append(newCalls, n)
+				case "SetNodeID", "Configure":
+					nodeSet = true
+				}
+			case *ast.FuncDecl:
+				if n.Body != nil {
+					checkLayoutWrites(pass, n.Body)
+				}
+			case *ast.FuncLit:
+				checkLayoutWrites(pass, n.Body)
+			case *ast.BinaryExpr:
+				checkComparison(pass, n)
+			}
+			return true
+		})
+	}
+	if pass.Pkg.Name() == "main" && !nodeSet {
+		for _, call := range newCalls {
+			pass.Reportf(call.Pos(), "usid.New called without usid.SetNodeID: every instance shares node 1")
+		}
+	}
+	return nil, nil
+}
+
+// usidObject returns the usid package object expr refers to, or nil.
+func usidObject(pass *analysis.Pass, expr ast.Expr) types.Object {
+	var id *ast.Ident
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		id = e
+	case *ast.SelectorExpr:
+		id = e.Sel
+	default:
+		return nil
+	}
+	obj := pass.TypesInfo.Uses[id]
+	if obj == nil || obj.Pkg() == nil || obj.Pkg().Path() != usidPath {
+		return nil
+	}
+	return obj
+}
+
+// usidFunc returns the name of the usid package-level function expr refers
+// to, or "".
+func usidFunc(pass *analysis.Pass, expr ast.Expr) string {
+	fn, ok := usidObject(pass, expr).(*types.Func)
+	if !ok || fn.Type().(*types.Signature).Recv() != nil {
+		return ""
+	}
+	return fn.Name()
+}
+
+// checkLayoutWrites reports assignments to layout variables that follow a
+// generator-creating call in body. Nested function literals are checked on
+// their own.
+func checkLayoutWrites(pass *analysis.Pass, body *ast.BlockStmt) {
+	created := token.NoPos
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			if !created.IsValid() && generatorFuncs[usidFunc(pass, n.Fun)] {
+				created = n.Pos()
+			}
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				reportLayoutWrite(pass, lhs, created)
+			}
+		case *ast.IncDecStmt:
+			reportLayoutWrite(pass, n.X, created)
+		}
+		return true
+	})
+}
+
+func reportLayoutWrite(pass *analysis.Pass, lhs ast.Expr, created token.Pos) {
+	if !created.IsValid() {
+		return
+	}
+	v, ok := usidObject(pass, lhs).(*types.Var)
+	if !ok || v.IsField() || !layoutVars[v.Name()] {
+		return
+	}
+	pass.Reportf(lhs.Pos(), "usid.%s changed after a generator was created at %s; existing generators keep the old layout (call usid.Configure before generating IDs)",
+		v.Name(), pass.Fset.Position(created))
+}
+
+// checkComparison reports comparisons between IDs unwrapped from different
+// named types.
+func checkComparison(pass *analysis.Pass, e *ast.BinaryExpr) {
+	switch e.Op {
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+	default:
+		return
+	}
+	x, y := idDomain(pass, e.X), idDomain(pass, e.Y)
+	if x != nil && y != nil && !types.Identical(x, y) {
+		pass.Reportf(e.OpPos, "comparing IDs of different types %s and %s", x, y)
+	}
+}
+
+// idDomain returns the named type an ID-valued expression was unwrapped from,
+// as in usid.ID(userID) or userID.ID(), or nil.
+func idDomain(pass *analysis.Pass, expr ast.Expr) types.Type {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	var from types.Type
+	if tv := pass.TypesInfo.Types[call.Fun]; tv.IsType() && len(call.Args) == 1 {
+		// Conversion: usid.ID(x)
+		if !isID(tv.Type) {
+			return nil
+		}
+		from = pass.TypesInfo.TypeOf(call.Args[0])
+	} else if sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr); ok && sel.Sel.Name == "ID" && len(call.Args) == 0 {
+		// Accessor: x.ID()
+		if !isID(pass.TypesInfo.TypeOf(call)) {
+			return nil
+		}
+		from = pass.TypesInfo.TypeOf(sel.X)
+	}
+	if p, ok := from.(*types.Pointer); ok {
+		from = p.Elem()
+	}
+	if _, ok := types.Unalias(from).(*types.Named); !ok || isID(from) {
+		return nil
+	}
+	return from
+}
+
+// isID reports whether t is usid.ID.
+func isID(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == usidPath && obj.Name() == "ID"
+}
@@ -0,0 +1,12 @@
+package usidlint_test
+
+import (
+	"testing"
+
+	"github.com/paraglidehq/usid/v2/usidlint"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), usidlint.Analyzer, "a", "b")
+}
@@ -0,0 +1,109 @@
+// Package usidotel instruments usid with OpenTelemetry: spans and metrics for
+// Postgres node acquisition and migrations, and span attributes that carry
+// entity IDs in their external format.
+//
+//	node, err := usidotel.NextNode(ctx, db)
+//	usidotel.SetID(ctx, "order.id", orderID)
+package usidotel
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/postgres"
+)
+
+const scope = "github.com/paraglidehq/usid/v2/usidotel"
+
+type config struct {
+	tp trace.TracerProvider
+	mp metric.MeterProvider
+}
+
+// Option configures instrumentation.
+type Option func(*config)
+
+// WithTracerProvider records spans with tp instead of the global provider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) { c.tp = tp }
+}
+
+// WithMeterProvider records metrics with mp instead of the global provider.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) { c.mp = mp }
+}
+
+// NextNode calls postgres.NextNode in a "usid.NextNode" span and records the
+// duration in the usid.postgres.duration histogram. The acquired node is set
+// as the span's usid.node attribute.
+func NextNode(ctx context.Context, db postgres.DB, opts ...Option) (int64, error) {
+	var node int64
+	err := instrument(ctx, "usid.NextNode", "next_node", opts, func(ctx context.Context, span trace.Span) error {
+		var err error
+		node, err = postgres.NextNode(ctx, db)
+		if err == nil {
+			span.SetAttributes(attribute.Int64("usid.node", node))
+		}
+		return err
+	})
+	return node, err
+}
+
+// Migrate calls postgres.Migrate in a "usid.Migrate" span and records the
+// duration in the usid.postgres.duration histogram.
+func Migrate(ctx context.Context, db postgres.DB, cfg postgres.Config, opts ...Option) error {
+	return instrument(ctx, "usid.Migrate", "migrate", opts, func(ctx context.Context, span trace.Span) error {
+		span.SetAttributes(
+			attribute.Int("usid.node_bits", int(cfg.NodeBits)),
+			attribute.Int("usid.seq_bits", int(cfg.SeqBits)),
+		)
+		return postgres.Migrate(ctx, db, cfg)
+	})
+}
+
+// instrument runs fn in a span and records its duration and outcome.
+func instrument(ctx context.Context, name, operation string, opts []Option, fn func(context.Context, trace.Span) error) error {
+	c := config{tp: otel.GetTracerProvider(), mp: otel.GetMeterProvider()}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	ctx, span := c.tp.Tracer(scope).Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("db.system", "postgresql")))
+	defer span.End()
+
+	start := time.Now()
+	err := fn(ctx, span)
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	if hist, herr := c.mp.Meter(scope).Float64Histogram("usid.postgres.duration",
+		metric.WithUnit("s"), metric.WithDescription("Duration of usid Postgres operations")); herr == nil {
+		hist.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(
+			attribute.String("operation", operation),
+			attribute.String("outcome", outcome),
+		))
+	}
+	return err
+}
+
+// Attribute returns a span attribute holding id in its external format
+// (DefaultFormat, obfuscated if DefaultObfuscator is set), the same string
+// clients see, so traces can be searched by the IDs in API requests.
+func Attribute(key string, id usid.ID) attribute.KeyValue {
+	return attribute.String(key, id.String())
+}
+
+// SetID sets Attribute(key, id) on the span in ctx.
+func SetID(ctx context.Context, key string, id usid.ID) {
+	trace.SpanFromContext(ctx).SetAttributes(Attribute(key, id))
+}
@@ -0,0 +1,165 @@
+package usidotel_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	mnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tnoop "go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/usidotel"
+)
+
+// recorder captures spans and histogram records.
+type recorder struct {
+	tnoop.TracerProvider
+	mnoop.MeterProvider
+	spans   []*span
+	records []attribute.Set
+}
+
+func (r *recorder) Tracer(string, ...trace.TracerOption) trace.Tracer { return tracer{r: r} }
+func (r *recorder) Meter(string, ...metric.MeterOption) metric.Meter  { return meter{r: r} }
+
+type tracer struct {
+	tnoop.Tracer
+	r *recorder
+}
+
+func (t tracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	s := &span{name: name}
+	cfg := trace.NewSpanStartConfig(opts...)
+	s.SetAttributes(cfg.Attributes()...)
+	t.r.spans = append(t.r.spans, s)
+	return trace.ContextWithSpan(ctx, s), s
+}
+
+type span struct {
+	tnoop.Span
+	name   string
+	attrs  map[attribute.Key]attribute.Value
+	status codes.Code
+	ended  bool
+}
+
+func (s *span) SetAttributes(kv ...attribute.KeyValue) {
+	if s.attrs == nil {
+		s.attrs = map[attribute.Key]attribute.Value{}
+	}
+	for _, a := range kv {
+		s.attrs[a.Key] = a.Value
+	}
+}
+func (s *span) SetStatus(c codes.Code, _ string) { s.status = c }
+func (s *span) End(...trace.SpanEndOption)       { s.ended = true }
+func (s *span) IsRecording() bool                { return true }
+
+type meter struct {
+	mnoop.Meter
+	r *recorder
+}
+
+func (m meter) Float64Histogram(string, ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	return histogram{r: m.r}, nil
+}
+
+type histogram struct {
+	mnoop.Float64Histogram
+	r *recorder
+}
+
+func (h histogram) Record(_ context.Context, _ float64, opts ...metric.RecordOption) {
+	h.r.records = append(h.r.records, metric.NewRecordConfig(opts).Attributes())
+}
+
+func TestNextNode(t *testing.T) {
+	rec := &recorder{}
+	opts := []usidotel.Option{usidotel.WithTracerProvider(rec), usidotel.WithMeterProvider(rec)}
+
+	node, err := usidotel.NextNode(context.Background(), openFake(t, 7), opts...)
+	if err != nil || node != 7 {
+		t.Fatalf("NextNode() = %d, %v; want 7", node, err)
+	}
+	_, err = usidotel.NextNode(context.Background(), openFake(t, -1), opts...)
+	if err == nil {
+		t.Fatal("NextNode() with failing db: want err != nil")
+	}
+
+	if len(rec.spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(rec.spans))
+	}
+	ok, failed := rec.spans[0], rec.spans[1]
+	if ok.name != "usid.NextNode" || !ok.ended || ok.attrs["usid.node"].AsInt64() != 7 {
+		t.Errorf("span = %+v", ok)
+	}
+	if failed.status != codes.Error {
+		t.Errorf("failed span status = %v, want Error", failed.status)
+	}
+	if len(rec.records) != 2 {
+		t.Fatalf("recorded %d durations, want 2", len(rec.records))
+	}
+	if v, _ := rec.records[1].Value("outcome"); v.AsString() != "error" {
+		t.Errorf("outcome = %q, want error", v.AsString())
+	}
+}
+
+func TestSetID(t *testing.T) {
+	rec := &recorder{}
+	ctx, s := rec.Tracer("").Start(context.Background(), "handler")
+	id := usid.ID(1234567890)
+	usidotel.SetID(ctx, "order.id", id)
+	if got := s.(*span).attrs["order.id"].AsString(); got != id.String() {
+		t.Errorf("order.id = %q, want %q", got, id.String())
+	}
+}
+
+// openFake returns a database whose single query returns node, or fails if
+// node is negative.
+func openFake(t *testing.T, node int64) *sql.DB {
+	db := sql.OpenDB(fakeConnector{node})
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+type fakeConnector struct{ node int64 }
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn(c), nil }
+func (c fakeConnector) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ node int64 }
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c fakeConn) Close() error                        { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c fakeConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	if c.node < 0 {
+		return nil, errors.New("connection refused")
+	}
+	return &fakeRows{node: c.node}, nil
+}
+
+type fakeRows struct {
+	node int64
+	done bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"node"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.node
+	return nil
+}
@@ -0,0 +1,83 @@
+// Package usidslice provides helpers for working with slices of IDs,
+// such as the conversions needed around batch endpoints.
+package usidslice
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+// Sort sorts ids in place in ascending (chronological) order.
+func Sort(ids []usid.ID) {
+	slices.Sort(ids)
+}
+
+// Dedupe returns ids with duplicates removed, keeping the first occurrence
+// of each ID in its original position. The input slice is not modified.
+func Dedupe(ids []usid.ID) []usid.ID {
+	seen := make(map[usid.ID]struct{}, len(ids))
+	out := make([]usid.ID, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	return out
+}
+
+// Contains reports whether id is present in ids.
+func Contains(ids []usid.ID, id usid.ID) bool {
+	return slices.Contains(ids, id)
+}
+
+// Min returns the smallest (oldest) ID, or usid.Nil if ids is empty.
+func Min(ids []usid.ID) usid.ID {
+	if len(ids) == 0 {
+		return usid.Nil
+	}
+	return slices.Min(ids)
+}
+
+// Max returns the largest (newest) ID, or usid.Nil if ids is empty.
+func Max(ids []usid.ID) usid.ID {
+	if len(ids) == 0 {
+		return usid.Nil
+	}
+	return slices.Max(ids)
+}
+
+// Ints64 returns the raw int64 values of ids.
+func Ints64(ids []usid.ID) []int64 {
+	out := make([]int64, len(ids))
+	for i, id := range ids {
+		out[i] = id.Int64()
+	}
+	return out
+}
+
+// Strings returns ids encoded in format f.
+func Strings(ids []usid.ID, f usid.Format) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = id.Format(f)
+	}
+	return out
+}
+
+// ParseAll parses every string using usid.Parse. It stops at the first
+// invalid string and returns an error identifying its index.
+func ParseAll(strs []string) ([]usid.ID, error) {
+	out := make([]usid.ID, len(strs))
+	for i, s := range strs {
+		id, err := usid.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("usid: element %d: %w", i, err)
+		}
+		out[i] = id
+	}
+	return out, nil
+}
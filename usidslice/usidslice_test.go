@@ -0,0 +1,53 @@
+package usidslice_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/usidslice"
+)
+
+func TestSliceHelpers(t *testing.T) {
+	ids := []usid.ID{30, 10, 20, 10, 30}
+
+	if got, want := usidslice.Dedupe(ids), []usid.ID{30, 10, 20}; !slices.Equal(got, want) {
+		t.Errorf("Dedupe = %v, want %v", got, want)
+	}
+	if !usidslice.Contains(ids, 20) || usidslice.Contains(ids, 40) {
+		t.Error("Contains returned wrong result")
+	}
+	if got := usidslice.Min(ids); got != 10 {
+		t.Errorf("Min = %d, want 10", got)
+	}
+	if got := usidslice.Max(ids); got != 30 {
+		t.Errorf("Max = %d, want 30", got)
+	}
+	if got := usidslice.Min(nil); got != usid.Nil {
+		t.Errorf("Min(nil) = %d, want Nil", got)
+	}
+	if got, want := usidslice.Ints64(ids[:3]), []int64{30, 10, 20}; !slices.Equal(got, want) {
+		t.Errorf("Ints64 = %v, want %v", got, want)
+	}
+
+	sorted := slices.Clone(ids)
+	usidslice.Sort(sorted)
+	if want := []usid.ID{10, 10, 20, 30, 30}; !slices.Equal(sorted, want) {
+		t.Errorf("Sort = %v, want %v", sorted, want)
+	}
+}
+
+func TestStringsParseAll(t *testing.T) {
+	ids := []usid.ID{usid.New(), usid.New(), usid.New()}
+	strs := usidslice.Strings(ids, usid.DefaultFormat)
+	got, err := usidslice.ParseAll(strs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, ids) {
+		t.Errorf("ParseAll(Strings(ids)) = %v, want %v", got, ids)
+	}
+	if _, err := usidslice.ParseAll([]string{strs[0], "!!"}); err == nil {
+		t.Error("ParseAll(invalid): want err != nil")
+	}
+}
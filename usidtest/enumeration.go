@@ -0,0 +1,49 @@
+package usidtest
+
+import (
+	"testing"
+
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/analyze"
+)
+
+// AssertObfuscated reports a test error if exposed, ID strings in format f
+// collected in issue order from a public API, reveal their time order (see
+// analyze.Enumeration). Use it as a CI guard that obfuscation is enabled.
+func AssertObfuscated(t testing.TB, exposed []string, f usid.Format) {
+	t.Helper()
+	e := enumeration(t, exposed, f)
+	if e != nil && e.Ascending > analyze.MaxAscending {
+		t.Errorf("usidtest: exposed IDs are enumerable: %s", e.Warnings()[0])
+	}
+}
+
+// AssertNotEnumerable reports a test error for every way exposed, ID strings
+// in format f collected in issue order, let a client guess other IDs. IDs
+// obfuscated only by XOR fail: neighbors stay within a small range. IDs
+// exposed in signed form resist enumeration anyway; check those with
+// AssertObfuscated.
+func AssertNotEnumerable(t testing.TB, exposed []string, f usid.Format) {
+	t.Helper()
+	e := enumeration(t, exposed, f)
+	if e == nil {
+		return
+	}
+	for _, w := range e.Warnings() {
+		t.Errorf("usidtest: exposed IDs are enumerable: %s", w)
+	}
+}
+
+func enumeration(t testing.TB, exposed []string, f usid.Format) *analyze.Enumeration {
+	t.Helper()
+	if len(exposed) < 16 {
+		t.Errorf("usidtest: %d exposed IDs are too few to measure enumerability; collect at least 16", len(exposed))
+		return nil
+	}
+	e, err := analyze.Enumerability(exposed, f)
+	if err != nil {
+		t.Errorf("usidtest: %v", err)
+		return nil
+	}
+	return &e
+}
@@ -0,0 +1,55 @@
+package usidtest
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+// Example returns a realistic sample ID in format f for API documentation
+// and contract tests, in place of a placeholder string. Like a real ID, it
+// has a nonzero node and sequence and is obfuscated if usid.DefaultObfuscator
+// is set. It depends only on f, Start, and the package-level configuration,
+// so generated docs don't change from run to run.
+func Example(f usid.Format) string {
+	return Examples(f, 1)[0]
+}
+
+// Examples returns n distinct sample IDs in format f, as for Example,
+// generated over the days after Start in ascending order.
+func Examples(f usid.Format, n int) []string {
+	rng := rand.New(rand.NewPCG(uint64(Start.Unix()), uint64(n)))
+	out := make([]string, n)
+	t := Start
+	for i := range out {
+		t = t.Add(time.Hour + time.Duration(rng.Int64N(int64(time.Hour))))
+		out[i] = compose(rng, t).Format(f)
+	}
+	return out
+}
+
+// RandomExample returns a sample ID in format f from a random node and
+// sequence within the last day, for contract tests that must not depend on
+// fixed values.
+func RandomExample(f usid.Format) string {
+	t := time.Now().Add(-time.Duration(rand.Int64N(int64(24 * time.Hour))))
+	return compose(rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())), t).Format(f)
+}
+
+// compose builds the ID generated at t by a random node with a random
+// sequence number.
+func compose(rng *rand.Rand, t time.Time) usid.ID {
+	cfg := usid.CurrentConfig()
+	node := between(rng, 1, cfg.MaxNode())
+	seq := between(rng, 1, cfg.MaxSeq())
+	return usid.MinIDForTime(t) | usid.ID(node<<(cfg.TenantBits+cfg.SeqBits)|seq)
+}
+
+// between returns a random value in [lo, hi], or hi if the range is empty.
+func between(rng *rand.Rand, lo, hi int64) int64 {
+	if hi <= lo {
+		return hi
+	}
+	return lo + rng.Int64N(hi-lo+1)
+}
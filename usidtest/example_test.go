@@ -0,0 +1,40 @@
+package usidtest_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/usidtest"
+)
+
+func TestExamples(t *testing.T) {
+	got := usidtest.Examples(usid.FormatCrockford, 10)
+	if !slices.Equal(got, usidtest.Examples(usid.FormatCrockford, 10)) {
+		t.Fatalf("Examples not reproducible")
+	}
+	if usidtest.Example(usid.FormatBase58) != usidtest.Example(usid.FormatBase58) {
+		t.Errorf("Example not reproducible")
+	}
+	ids := make([]usid.ID, len(got))
+	for i, s := range got {
+		id, err := usid.ParseCrockford(s)
+		if err != nil {
+			t.Fatalf("Examples[%d] = %q: %v", i, s, err)
+		}
+		if id.Node() == 0 || id.Seq() == 0 || id.Timestamp().Before(usidtest.Start) {
+			t.Errorf("Examples[%d] = %+v, want nonzero node and seq after Start", i, id.Components())
+		}
+		ids[i] = id
+	}
+	usidtest.AssertMonotonic(t, ids)
+
+	id, err := usid.ParseBase62(usidtest.RandomExample(usid.FormatBase62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if age := id.Age(); age < 0 || age > 24*time.Hour {
+		t.Errorf("RandomExample is %s old, want within a day", age)
+	}
+}
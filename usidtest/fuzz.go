@@ -0,0 +1,49 @@
+package usidtest
+
+import (
+	"iter"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+// ValidIDs yields a fixed corpus of valid IDs for seeding fuzz tests: edge
+// values, the bounds of the current layout, and a short deterministic stream.
+func ValidIDs() iter.Seq[usid.ID] {
+	return func(yield func(usid.ID) bool) {
+		ids := []usid.ID{
+			usid.Nil, 1, usid.MaxSafeInteger, usid.MaxSafeInteger + 1, math.MaxInt64,
+			usid.MinIDForTime(Start), usid.MaxIDForTime(Start),
+		}
+		ids = append(ids, Sequential(Start, 4)...)
+		ids = append(ids, Sequential(Start.Add(365*24*time.Hour), 4)...)
+		for _, id := range ids {
+			if !yield(id) {
+				return
+			}
+		}
+	}
+}
+
+// ValidStrings yields the encodings of ValidIDs in format f, plus uppercase
+// variants for case-insensitive formats, for seeding fuzz tests:
+//
+//	for s := range usidtest.ValidStrings(usid.FormatBase58) {
+//		f.Add(s)
+//	}
+func ValidStrings(f usid.Format) iter.Seq[string] {
+	folds := f == usid.FormatCrockford || f == usid.FormatCrockfordFixed || f == usid.FormatHash
+	return func(yield func(string) bool) {
+		for id := range ValidIDs() {
+			s := id.Format(f)
+			if !yield(s) {
+				return
+			}
+			if up := strings.ToUpper(s); folds && up != s && !yield(up) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package usidtest_test
+
+import (
+	"testing"
+
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/usidtest"
+)
+
+func TestValidStrings(t *testing.T) {
+	for _, f := range usid.Formats() {
+		n := 0
+		for s := range usidtest.ValidStrings(f) {
+			n++
+			if _, err := usid.ParseFormat(s, f); err != nil {
+				t.Errorf("ParseFormat(%q, %s): %v", s, f, err)
+			}
+		}
+		if n == 0 {
+			t.Errorf("ValidStrings(%s) yielded nothing", f)
+		}
+	}
+}
+
+func FuzzParseCrockford(f *testing.F) {
+	for s := range usidtest.ValidStrings(usid.FormatCrockford) {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		id, err := usid.ParseCrockford(s)
+		if err != nil {
+			return
+		}
+		again, err := usid.ParseCrockford(id.Format(usid.FormatCrockford))
+		if err != nil || again != id {
+			t.Errorf("re-parse of %q: got %d, %v; want %d", s, again, err, id)
+		}
+	})
+}
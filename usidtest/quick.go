@@ -0,0 +1,26 @@
+package usidtest
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+// QuickID is an ID that implements testing/quick.Generator, for property
+// tests over IDs:
+//
+//	quick.Check(func(id usidtest.QuickID) bool { return roundtrips(usid.ID(id)) }, nil)
+type QuickID usid.ID
+
+// Generate returns a random valid (non-negative) ID, returning edge values
+// such as Nil, 1, MaxSafeInteger, and the largest ID about one time in
+// eight.
+func (QuickID) Generate(r *rand.Rand, size int) reflect.Value {
+	if r.Intn(8) == 0 {
+		edges := [...]usid.ID{usid.Nil, 1, usid.MaxSafeInteger, usid.MaxSafeInteger + 1, math.MaxInt64}
+		return reflect.ValueOf(QuickID(edges[r.Intn(len(edges))]))
+	}
+	return reflect.ValueOf(QuickID(r.Int63()))
+}
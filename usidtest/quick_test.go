@@ -0,0 +1,22 @@
+package usidtest_test
+
+import (
+	"testing"
+	"testing/quick"
+
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/usidtest"
+)
+
+func TestQuickID(t *testing.T) {
+	for _, f := range usid.Formats() {
+		roundtrip := func(q usidtest.QuickID) bool {
+			id := usid.ID(q)
+			parsed, err := usid.ParseFormat(id.Format(f), f)
+			return err == nil && parsed == id
+		}
+		if err := quick.Check(roundtrip, nil); err != nil {
+			t.Errorf("format %s: %v", f, err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Package usidtest provides deterministic ID generation and assertions for
+// tests, so golden files and snapshots stay stable from run to run.
+package usidtest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+)
+
+// Start is the default starting time for Clock, a fixed instant after the
+// default Epoch.
+var Start = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Node is the node ID used by Sequential and by NewGenerator when node is zero.
+const Node int64 = 1
+
+// Clock is a fake clock that advances by a fixed step on every call to Now.
+// It is safe for concurrent use.
+type Clock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+// NewClock returns a Clock that first reports start and then advances by step
+// on each call to Now. A zero step freezes the clock until Advance or Set.
+func NewClock(start time.Time, step time.Duration) *Clock {
+	return &Clock{now: start, step: step}
+}
+
+// Now returns the current fake time and advances the clock by its step.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := c.now
+	c.now = c.now.Add(c.step)
+	return t
+}
+
+// Advance moves the clock forward by d.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+// Set moves the clock to t.
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	c.now = t
+	c.mu.Unlock()
+}
+
+// NewGenerator returns a generator for node that reads time from clock.
+// A nil clock starts at Start and advances one usid.Precision tick per ID,
+// so the same sequence of calls always yields the same IDs.
+func NewGenerator(node int64, clock *Clock, opts ...usid.Option) *usid.Generator {
+	if node == 0 {
+		node = Node
+	}
+	if clock == nil {
+		clock = NewClock(Start, usid.Precision)
+	}
+	return usid.NewGenerator(node, append(opts, usid.WithClock(clock.Now))...)
+}
+
+// Sequential returns n IDs for Node whose timestamps are one usid.Precision
+// tick apart, beginning at start. The result depends only on its arguments
+// and the package-level layout.
+func Sequential(start time.Time, n int) []usid.ID {
+	g := NewGenerator(Node, NewClock(start, usid.Precision))
+	ids := make([]usid.ID, n)
+	for i := range ids {
+		ids[i] = g.Generate()
+	}
+	return ids
+}
+
+// AssertMonotonic reports a test error for the first ID in ids that is not
+// strictly greater than its predecessor.
+func AssertMonotonic(t testing.TB, ids []usid.ID) {
+	t.Helper()
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Errorf("usidtest: ids[%d] = %s (%d) is not greater than ids[%d] = %s (%d)",
+				i, ids[i], ids[i], i-1, ids[i-1], ids[i-1])
+			return
+		}
+	}
+}
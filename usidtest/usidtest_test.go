@@ -0,0 +1,85 @@
+package usidtest_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/paraglidehq/usid/v2"
+	"github.com/paraglidehq/usid/v2/usidtest"
+)
+
+func TestSequential(t *testing.T) {
+	a := usidtest.Sequential(usidtest.Start, 5)
+	b := usidtest.Sequential(usidtest.Start, 5)
+	if !slices.Equal(a, b) {
+		t.Fatalf("Sequential not reproducible: %v vs %v", a, b)
+	}
+	usidtest.AssertMonotonic(t, a)
+	for i, id := range a {
+		want := usidtest.Start.Add(time.Duration(i) * usid.Precision)
+		if !id.Timestamp().Equal(want) {
+			t.Errorf("ids[%d].Timestamp() = %v, want %v", i, id.Timestamp(), want)
+		}
+		if id.Node() != usidtest.Node {
+			t.Errorf("ids[%d].Node() = %d, want %d", i, id.Node(), usidtest.Node)
+		}
+	}
+}
+
+func TestFrozenClock(t *testing.T) {
+	clock := usidtest.NewClock(usidtest.Start, 0)
+	g := usidtest.NewGenerator(3, clock)
+	first, second := g.Generate(), g.Generate()
+	if first.Timestamp() != second.Timestamp() || second.Seq() != first.Seq()+1 {
+		t.Errorf("frozen clock: got %+v then %+v", first.Components(), second.Components())
+	}
+	clock.Advance(time.Hour)
+	if got := g.Generate().Timestamp(); !got.Equal(usidtest.Start.Add(time.Hour)) {
+		t.Errorf("after Advance: Timestamp() = %v", got)
+	}
+}
+
+func TestAssertMonotonic(t *testing.T) {
+	ft := &fakeT{TB: t}
+	usidtest.AssertMonotonic(ft, []usid.ID{1, 2, 2})
+	if !ft.failed {
+		t.Error("AssertMonotonic accepted a repeated ID")
+	}
+}
+
+type fakeT struct {
+	testing.TB
+	failed bool
+}
+
+func (f *fakeT) Helper()               {}
+func (f *fakeT) Errorf(string, ...any) { f.failed = true }
+
+func TestAssertNotEnumerable(t *testing.T) {
+	exposed := func() []string {
+		var s []string
+		for _, id := range usidtest.Sequential(usidtest.Start, 100) {
+			s = append(s, id.String())
+		}
+		return s
+	}
+
+	ft := &fakeT{TB: t}
+	usidtest.AssertObfuscated(ft, exposed(), usid.FormatCrockford)
+	if !ft.failed {
+		t.Error("AssertObfuscated accepted raw IDs")
+	}
+
+	usid.SetObfuscator(0x5deece66d123f567)
+	defer func() { usid.DefaultObfuscator = nil }()
+	ft = &fakeT{TB: t}
+	usidtest.AssertObfuscated(ft, exposed(), usid.FormatCrockford)
+	if ft.failed {
+		t.Error("AssertObfuscated rejected obfuscated IDs")
+	}
+	usidtest.AssertNotEnumerable(ft, exposed(), usid.FormatCrockford)
+	if !ft.failed {
+		t.Error("AssertNotEnumerable accepted XOR-obfuscated IDs")
+	}
+}
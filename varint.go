@@ -0,0 +1,43 @@
+package usid
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// MaxVarintLen is the maximum length of an ID encoded by AppendVarint.
+const MaxVarintLen = binary.MaxVarintLen64
+
+var errVarint = errors.New("usid: invalid varint ID")
+
+// AppendVarint appends the uvarint encoding of id to b. IDs below 2^49 take
+// at most 7 bytes and IDs below 2^56 at most 8; larger ones take 9, or 10
+// for negative IDs. How soon a layout's IDs outgrow 8 bytes depends on its
+// Precision and Epoch, so measure before relying on the savings.
+func (id ID) AppendVarint(b []byte) []byte {
+	return binary.AppendUvarint(b, uint64(id))
+}
+
+// MarshalVarint returns the uvarint encoding of id.
+func (id ID) MarshalVarint() []byte {
+	return id.AppendVarint(make([]byte, 0, MaxVarintLen))
+}
+
+// UnmarshalVarint decodes an ID encoded by MarshalVarint. b must contain
+// exactly one encoded ID.
+func (id *ID) UnmarshalVarint(b []byte) error {
+	parsed, n := ReadVarint(b)
+	if n <= 0 || n != len(b) {
+		return errVarint
+	}
+	*id = parsed
+	return nil
+}
+
+// ReadVarint decodes an ID from the start of b and returns it with the number
+// of bytes read. n is 0 if b is too short and negative if the value
+// overflows, as with binary.Uvarint.
+func ReadVarint(b []byte) (id ID, n int) {
+	v, n := binary.Uvarint(b)
+	return ID(v), n
+}
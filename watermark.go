@@ -0,0 +1,43 @@
+package usid
+
+import "time"
+
+// Watermark is a consumer's position in a stream of time-ordered IDs, such
+// as a changefeed, replication, or export job: every ID up to and including
+// ID has been processed. The zero Watermark precedes every ID. It marshals
+// as its ID does, and postgres.SaveWatermark persists it across restarts.
+type Watermark struct {
+	ID ID
+}
+
+// Advance moves the watermark to id if id is later, and reports whether it
+// moved. Out-of-order and replayed IDs leave it unchanged.
+func (w *Watermark) Advance(id ID) bool {
+	if id <= w.ID {
+		return false
+	}
+	w.ID = id
+	return true
+}
+
+// Lag returns how far the watermark's timestamp trails the current time: how
+// stale the consumer's view is, assuming it has caught up with every ID
+// issued so far.
+func (w Watermark) Lag() time.Duration {
+	return time.Since(w.ID.Timestamp())
+}
+
+// String returns the watermark's ID in DefaultFormat.
+func (w Watermark) String() string {
+	return w.ID.String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (w Watermark) MarshalText() ([]byte, error) {
+	return w.ID.MarshalText()
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (w *Watermark) UnmarshalText(b []byte) error {
+	return w.ID.UnmarshalText(b)
+}
@@ -0,0 +1,35 @@
+package usid
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestWatermark(t *testing.T) {
+	g := NewGenerator(1)
+	first, second := g.Generate(), g.Generate()
+
+	var w Watermark
+	if !w.Advance(second) || w.ID != second {
+		t.Fatalf("Advance(%v) from zero did not move the watermark", second)
+	}
+	if w.Advance(first) || w.Advance(second) || w.ID != second {
+		t.Errorf("Advance of an older or equal ID moved the watermark to %v", w.ID)
+	}
+	if lag := w.Lag(); lag < 0 || lag > time.Minute {
+		t.Errorf("Lag() = %v for a fresh ID", lag)
+	}
+
+	b, err := json.Marshal(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := json.Marshal(w.ID.String()); string(b) != string(want) {
+		t.Errorf("json.Marshal(Watermark) = %s, want %s", b, want)
+	}
+	var got Watermark
+	if err := json.Unmarshal(b, &got); err != nil || got != w {
+		t.Errorf("json.Unmarshal(%s) = %v, %v, want %v", b, got, err, w)
+	}
+}